@@ -4,9 +4,15 @@ import (
 	"context"
 
 	"github.com/Benbentwo/aim/backend/agent"
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/conversation"
 	"github.com/Benbentwo/aim/backend/linear"
+	"github.com/Benbentwo/aim/backend/notify"
+	"github.com/Benbentwo/aim/backend/repostatus"
 	"github.com/Benbentwo/aim/backend/session"
 	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/storage"
+	"github.com/Benbentwo/aim/backend/transcript"
 	"github.com/Benbentwo/aim/backend/worktree"
 	"github.com/Benbentwo/aim/backend/workspace"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -15,25 +21,44 @@ import (
 // App is the main application struct wired to the Wails runtime.
 type App struct {
 	ctx              context.Context
+	Config           *config.Locator
 	SessionManager   *session.Manager
 	WorktreeManager  *worktree.Manager
 	SettingsManager  *settings.Manager
 	WorkspaceManager *workspace.Manager
 	LinearManager    *linear.Manager
 	AgentTracker     *agent.Tracker
+	Notifier         *notify.Manager
+	Conversations    *conversation.Manager
+	RepoStatus       *repostatus.Manager
+	Transcripts      *transcript.Follower
 }
 
 // NewApp creates and returns a new App instance.
 func NewApp() *App {
-	sessMgr := session.NewManager()
+	locator := config.NewLocator()
+	settingsMgr := settings.NewManager(locator)
+	sessMgr := session.NewManager(locator, settingsMgr)
 	wtrMgr := worktree.NewManager()
+	transcripts := transcript.NewFollower(func() map[string]transcript.Source {
+		return session.TranscriptSources(sessMgr.ListSessions())
+	})
+	tracker := agent.NewTracker(sessMgr, settingsMgr, transcripts)
+	wsMgr := workspace.NewManager(locator, sessMgr, wtrMgr, settingsMgr)
+	linearMgr := linear.NewManager()
+	wsMgr.SetIssueLinker(linearMgr)
 	return &App{
+		Config:           locator,
 		SessionManager:   sessMgr,
 		WorktreeManager:  wtrMgr,
-		SettingsManager:  settings.NewManager(),
-		WorkspaceManager: workspace.NewManager(sessMgr, wtrMgr),
-		LinearManager:    linear.NewManager(),
-		AgentTracker:     agent.NewTracker(sessMgr),
+		SettingsManager:  settingsMgr,
+		WorkspaceManager: wsMgr,
+		LinearManager:    linearMgr,
+		AgentTracker:     tracker,
+		Notifier:         notify.NewManager(locator, tracker),
+		Conversations:    conversation.NewManager(sessMgr, transcripts),
+		RepoStatus:       repostatus.NewManager(sessMgr, wtrMgr),
+		Transcripts:      transcripts,
 	}
 }
 
@@ -46,8 +71,15 @@ func (a *App) startup(ctx context.Context) {
 	a.WorkspaceManager.SetContext(ctx)
 	a.LinearManager.SetContext(ctx)
 	a.AgentTracker.SetContext(ctx)
+	a.Notifier.SetContext(ctx)
+	a.Conversations.SetContext(ctx)
+	a.RepoStatus.SetContext(ctx)
+	a.Transcripts.Start()
+	a.loadLinearCredentials()
+}
 
-	// Load Linear credentials from settings (OAuth token takes precedence)
+// loadLinearCredentials loads Linear credentials from settings (OAuth token takes precedence).
+func (a *App) loadLinearCredentials() {
 	s := a.SettingsManager.GetSettings()
 	if s.LinearOAuthToken != "" {
 		a.LinearManager.LoadOAuthToken(s.LinearOAuthToken)
@@ -59,6 +91,8 @@ func (a *App) startup(ctx context.Context) {
 // shutdown is called when the application terminates.
 func (a *App) shutdown(ctx context.Context) {
 	a.AgentTracker.Shutdown()
+	a.Transcripts.Shutdown()
+	a.RepoStatus.Shutdown()
 	a.LinearManager.StopPolling()
 	a.SessionManager.Shutdown()
 }
@@ -71,3 +105,57 @@ func (a *App) OpenDirectoryDialog(title string) string {
 	})
 	return path
 }
+
+// ListProfiles returns all profiles, marking the active one.
+func (a *App) ListProfiles() ([]config.Profile, error) {
+	return a.Config.ListProfiles()
+}
+
+// GetActiveProfile returns the name of the active profile.
+func (a *App) GetActiveProfile() string {
+	return a.Config.ActiveProfile()
+}
+
+// CreateProfile creates a new, empty profile without switching to it.
+func (a *App) CreateProfile(name string) error {
+	return a.Config.CreateProfile(name)
+}
+
+// DeleteProfile removes an inactive profile and all of its state.
+func (a *App) DeleteProfile(name string) error {
+	return a.Config.DeleteProfile(name)
+}
+
+// SwitchProfile stops all running sessions, makes name the active profile and
+// reloads settings, workspaces, sessions, their metrics and git status and
+// Linear credentials from it.
+func (a *App) SwitchProfile(name string) error {
+	if name == a.Config.ActiveProfile() {
+		return nil
+	}
+	if err := a.Config.SetActiveProfile(name); err != nil {
+		return err
+	}
+	a.LinearManager.Disconnect()
+	a.SettingsManager.Reload()
+	a.SessionManager.Reload()
+	a.WorkspaceManager.Reload()
+	a.Conversations.Reload()
+	a.AgentTracker.Reload()
+	a.RepoStatus.Reload()
+	a.Notifier.Reload()
+	a.loadLinearCredentials()
+	runtime.EventsEmit(a.ctx, "profile:changed", name)
+	return nil
+}
+
+// GetRecoveries returns the state files restored from backup (or found
+// unreadable) since startup, so the frontend can tell the user on load.
+func (a *App) GetRecoveries() []storage.Recovery {
+	return storage.Recoveries()
+}
+
+// DismissRecoveries clears the recoveries the user has acknowledged.
+func (a *App) DismissRecoveries() {
+	storage.DismissRecoveries()
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/transcript"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -27,6 +29,15 @@ type SessionMetrics struct {
 	TotalTime    int64  `json:"totalTime"`
 	LastActivity string `json:"lastActivity"`
 	IsStuck      bool   `json:"isStuck"`
+
+	WorkspaceID     string `json:"workspaceId"`
+	IssueID         string `json:"issueId,omitempty"`
+	IssueIdentifier string `json:"issueIdentifier,omitempty"`
+
+	// Token usage read from the agent's transcripts.
+	Tokens  transcript.Usage `json:"tokens"`
+	Models  []ModelUsage     `json:"models"`
+	CostUSD float64          `json:"costUsd"`
 }
 
 // MetricSnapshot is a point-in-time aggregate.
@@ -43,6 +54,26 @@ type DashboardData struct {
 	Sessions    []SessionMetrics `json:"sessions"`
 	History     []MetricSnapshot `json:"history"`
 	StuckAgents []SessionMetrics `json:"stuckAgents"`
+	Workspaces  []UsageTotal     `json:"workspaces"` // usage per workspace
+	Issues      []UsageTotal     `json:"issues"`     // usage per Linear issue
+}
+
+// Event types published to subscribers.
+const (
+	EventStatus = "status" // a session changed status
+	EventStuck  = "stuck"  // a session has been waiting longer than the stuck threshold
+)
+
+// Event is a session state change published to Tracker subscribers.
+type Event struct {
+	Type        string    `json:"type"`
+	SessionID   string    `json:"sessionId"`
+	SessionName string    `json:"sessionName"`
+	WorkspaceID string    `json:"workspaceId"`
+	Agent       string    `json:"agent"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Time        time.Time `json:"time"`
 }
 
 // Tracker monitors session activity and computes metrics.
@@ -50,21 +81,63 @@ type Tracker struct {
 	ctx            context.Context
 	mu             sync.RWMutex
 	sessionManager *session.Manager
+	settings       *settings.Manager
+	usage          *usageCollector
 	metrics        map[string]*SessionMetrics
 	history        []MetricSnapshot
 	lastStatuses   map[string]string
 	lastSample     time.Time
 	stopCh         chan struct{}
 	running        bool
+	subscribers    []func(Event)
 }
 
-// NewTracker creates a new Tracker.
-func NewTracker(sm *session.Manager) *Tracker {
-	return &Tracker{
+// NewTracker creates a new Tracker that reads token usage from the
+// transcript lines follower reads.
+func NewTracker(sm *session.Manager, settingsMgr *settings.Manager, follower *transcript.Follower) *Tracker {
+	t := &Tracker{
 		sessionManager: sm,
+		settings:       settingsMgr,
+		usage:          newUsageCollector(),
 		metrics:        make(map[string]*SessionMetrics),
 		lastStatuses:   make(map[string]string),
 	}
+	sm.AddStatusListener(t.onStatusChange)
+	follower.Subscribe(t.usage.add, t.usage.forget)
+	return t
+}
+
+// Subscribe registers fn to receive status transitions and stuck detections.
+func (t *Tracker) Subscribe(fn func(Event)) {
+	t.mu.Lock()
+	t.subscribers = append(t.subscribers, fn)
+	t.mu.Unlock()
+}
+
+func (t *Tracker) onStatusChange(id, from, to string) {
+	s, err := t.sessionManager.GetSession(id)
+	if err != nil {
+		return
+	}
+	t.publish(Event{
+		Type:        EventStatus,
+		SessionID:   id,
+		SessionName: s.Name,
+		WorkspaceID: s.WorkspaceID,
+		Agent:       s.Agent,
+		From:        from,
+		To:          to,
+		Time:        time.Now(),
+	})
+}
+
+func (t *Tracker) publish(ev Event) {
+	t.mu.RLock()
+	subscribers := t.subscribers
+	t.mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(ev)
+	}
 }
 
 // SetContext sets the Wails context and starts sampling.
@@ -99,8 +172,17 @@ func (t *Tracker) startSampling() {
 
 func (t *Tracker) sample() {
 	sessions := t.sessionManager.ListSessions()
+	prices := t.settings.GetSettings()
 	now := time.Now()
 
+	// Deferred before the unlock so subscribers run after the lock is released.
+	var stuckEvents []Event
+	defer func() {
+		for _, ev := range stuckEvents {
+			t.publish(ev)
+		}
+	}()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -126,6 +208,10 @@ func (t *Tracker) sample() {
 		m.Status = s.Status
 		m.SessionName = s.Name
 		m.Agent = s.Agent
+		m.WorkspaceID = s.WorkspaceID
+		m.IssueID = s.IssueID
+		m.IssueIdentifier = s.IssueIdentifier
+		m.Models, m.Tokens, m.CostUSD = priceUsage(t.usage.usage(s.ID), prices)
 
 		switch s.Status {
 		case "thinking":
@@ -149,6 +235,7 @@ func (t *Tracker) sample() {
 		}
 
 		// Stuck detection: waiting for > threshold
+		wasStuck := m.IsStuck
 		m.IsStuck = false
 		if s.Status == "waiting" {
 			prev := t.lastStatuses[s.ID]
@@ -159,6 +246,18 @@ func (t *Tracker) sample() {
 				}
 			}
 		}
+		if m.IsStuck && !wasStuck {
+			stuckEvents = append(stuckEvents, Event{
+				Type:        EventStuck,
+				SessionID:   s.ID,
+				SessionName: s.Name,
+				WorkspaceID: s.WorkspaceID,
+				Agent:       s.Agent,
+				From:        s.Status,
+				To:          s.Status,
+				Time:        now,
+			})
+		}
 
 		t.lastStatuses[s.ID] = s.Status
 	}
@@ -210,6 +309,12 @@ func (t *Tracker) buildDashboardDataLocked() DashboardData {
 		Sessions:    sessions,
 		History:     history,
 		StuckAgents: stuck,
+		Workspaces: usageTotals(t.metrics, func(m *SessionMetrics) (string, string) {
+			return m.WorkspaceID, m.WorkspaceID
+		}),
+		Issues: usageTotals(t.metrics, func(m *SessionMetrics) (string, string) {
+			return m.IssueID, m.IssueIdentifier
+		}),
 	}
 }
 
@@ -220,6 +325,21 @@ func (t *Tracker) GetDashboardData() DashboardData {
 	return t.buildDashboardDataLocked()
 }
 
+// Reload forgets the metrics and history of the previous profile's sessions,
+// after a profile switch. Their usage goes as the follower drops them.
+func (t *Tracker) Reload() {
+	t.mu.Lock()
+	t.metrics = make(map[string]*SessionMetrics)
+	t.lastStatuses = make(map[string]string)
+	t.history = nil
+	t.lastSample = time.Time{}
+	data := t.buildDashboardDataLocked()
+	t.mu.Unlock()
+	if t.ctx != nil {
+		runtime.EventsEmit(t.ctx, "agent:metrics:updated", data)
+	}
+}
+
 // Shutdown stops the sampling goroutine.
 func (t *Tracker) Shutdown() {
 	t.mu.Lock()
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/transcript"
+)
+
+// ModelUsage is the token usage and cost attributed to one model.
+type ModelUsage struct {
+	Model   string           `json:"model"`
+	Tokens  transcript.Usage `json:"tokens"`
+	CostUSD float64          `json:"costUsd"`
+	Priced  bool             `json:"priced"` // false if the model has no price configured
+}
+
+// UsageTotal aggregates usage across the sessions of a workspace or issue.
+type UsageTotal struct {
+	ID       string           `json:"id"`
+	Label    string           `json:"label"`
+	Sessions int              `json:"sessions"`
+	Tokens   transcript.Usage `json:"tokens"`
+	CostUSD  float64          `json:"costUsd"`
+}
+
+// sessionUsage accumulates usage across every transcript a session has
+// written.
+type sessionUsage struct {
+	parsers map[string]*transcript.UsageParser // by file
+	seen    map[string]bool                    // records already counted
+	byModel map[string]transcript.Usage
+}
+
+// usageCollector totals the usage in the transcript lines the follower
+// reads, per session.
+type usageCollector struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionUsage
+}
+
+func newUsageCollector() *usageCollector {
+	return &usageCollector{sessions: make(map[string]*sessionUsage)}
+}
+
+// add counts the usage recorded on a session's new transcript lines.
+func (c *usageCollector) add(id string, lines []transcript.Line) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u, ok := c.sessions[id]
+	if !ok {
+		u = &sessionUsage{
+			parsers: make(map[string]*transcript.UsageParser),
+			seen:    make(map[string]bool),
+			byModel: make(map[string]transcript.Usage),
+		}
+		c.sessions[id] = u
+	}
+	for _, line := range lines {
+		p, ok := u.parsers[line.Path]
+		if !ok {
+			p = &transcript.UsageParser{Format: line.Format, Path: line.Path}
+			u.parsers[line.Path] = p
+		}
+		rec, ok := p.Parse(line.Data)
+		if !ok {
+			continue
+		}
+		// A record Claude copies into a new transcript on resume or /clear
+		// keeps its ID, so it counts once.
+		if u.seen[rec.ID] {
+			continue
+		}
+		u.seen[rec.ID] = true
+		total := u.byModel[rec.Model]
+		total.Add(rec.Usage)
+		u.byModel[rec.Model] = total
+	}
+}
+
+// forget drops a closed session's usage.
+func (c *usageCollector) forget(id string) {
+	c.mu.Lock()
+	delete(c.sessions, id)
+	c.mu.Unlock()
+}
+
+// usage returns a session's usage per model.
+func (c *usageCollector) usage(id string) map[string]transcript.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u, ok := c.sessions[id]
+	if !ok {
+		return nil
+	}
+	result := make(map[string]transcript.Usage, len(u.byModel))
+	for model, usage := range u.byModel {
+		result[model] = usage
+	}
+	return result
+}
+
+// priceUsage prices per-model usage with the configured price table.
+func priceUsage(byModel map[string]transcript.Usage, s settings.Settings) ([]ModelUsage, transcript.Usage, float64) {
+	models := make([]ModelUsage, 0, len(byModel))
+	var total transcript.Usage
+	var cost float64
+	for model, u := range byModel {
+		mu := ModelUsage{Model: model, Tokens: u}
+		if p, ok := s.PriceFor(model); ok {
+			mu.Priced = true
+			mu.CostUSD = (float64(u.InputTokens)*p.Input +
+				float64(u.OutputTokens)*p.Output +
+				float64(u.CacheReadTokens)*p.CacheRead +
+				float64(u.CacheWriteTokens)*p.CacheWrite) / 1e6
+		}
+		models = append(models, mu)
+		total.Add(u)
+		cost += mu.CostUSD
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Model < models[j].Model })
+	return models, total, cost
+}
+
+// usageTotals groups session metrics by key, skipping sessions with no key.
+func usageTotals(metrics map[string]*SessionMetrics, key func(*SessionMetrics) (id, label string)) []UsageTotal {
+	byID := make(map[string]*UsageTotal)
+	for _, m := range metrics {
+		id, label := key(m)
+		if id == "" {
+			continue
+		}
+		t, ok := byID[id]
+		if !ok {
+			t = &UsageTotal{ID: id, Label: label}
+			byID[id] = t
+		}
+		t.Sessions++
+		t.Tokens.Add(m.Tokens)
+		t.CostUSD += m.CostUSD
+	}
+	totals := make([]UsageTotal, 0, len(byID))
+	for _, t := range byID {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].CostUSD > totals[j].CostUSD })
+	return totals
+}
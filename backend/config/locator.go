@@ -0,0 +1,197 @@
+// Package config resolves where aim keeps its state on disk.
+//
+// All state lives under a home directory: $AIM_HOME if set, otherwise
+// <user config dir>/aim. The "default" profile uses the home directory
+// itself so existing installs keep working; other named profiles each get
+// an isolated directory under <home>/profiles/<name> with their own
+// settings, workspaces, sessions and Linear credentials.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/Benbentwo/aim/backend/storage"
+)
+
+const (
+	// HomeEnv overrides the aim home directory.
+	HomeEnv = "AIM_HOME"
+	// ProfileEnv selects the profile at startup, overriding the saved choice.
+	ProfileEnv = "AIM_PROFILE"
+	// DefaultProfile is the profile stored directly in the home directory.
+	DefaultProfile = "default"
+)
+
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Profile is a named, isolated set of aim state.
+type Profile struct {
+	Name   string `json:"name"`
+	Dir    string `json:"dir"`
+	Active bool   `json:"active"`
+}
+
+type activeProfile struct {
+	Active string `json:"active"`
+}
+
+// Locator is the single source of truth for aim's on-disk paths. Managers
+// resolve paths through it on every access so a profile switch takes effect
+// without rebuilding them.
+type Locator struct {
+	mu      sync.RWMutex
+	root    string
+	profile string
+}
+
+// NewLocator resolves the home directory and active profile from the
+// environment and the saved profile choice.
+func NewLocator() *Locator {
+	root := os.Getenv(HomeEnv)
+	if root == "" {
+		confDir, _ := os.UserConfigDir()
+		root = filepath.Join(confDir, "aim")
+	}
+	l := &Locator{root: root, profile: DefaultProfile}
+
+	if name := os.Getenv(ProfileEnv); name != "" && profileNameRe.MatchString(name) {
+		l.profile = name
+		return l
+	}
+	var saved activeProfile
+	if _, err := storage.LoadJSON(l.activeFile(), &saved); err == nil && profileNameRe.MatchString(saved.Active) {
+		l.profile = saved.Active
+	}
+	return l
+}
+
+// Root returns the aim home directory.
+func (l *Locator) Root() string {
+	return l.root
+}
+
+// Dir returns the state directory of the active profile.
+func (l *Locator) Dir() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.profileDir(l.profile)
+}
+
+// Path joins elem onto the active profile's state directory.
+func (l *Locator) Path(elem ...string) string {
+	return filepath.Join(append([]string{l.Dir()}, elem...)...)
+}
+
+// ActiveProfile returns the name of the active profile.
+func (l *Locator) ActiveProfile() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.profile
+}
+
+// ListProfiles returns the default profile plus every named profile on disk.
+func (l *Locator) ListProfiles() ([]Profile, error) {
+	active := l.ActiveProfile()
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(l.root, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != DefaultProfile && profileNameRe.MatchString(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names[1:])
+
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, Profile{
+			Name:   name,
+			Dir:    l.profileDir(name),
+			Active: name == active,
+		})
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates an empty named profile.
+func (l *Locator) CreateProfile(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	dir := l.profileDir(name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// SetActiveProfile switches to an existing profile and remembers the choice.
+func (l *Locator) SetActiveProfile(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if name != DefaultProfile {
+		if info, err := os.Stat(l.profileDir(name)); err != nil || !info.IsDir() {
+			return fmt.Errorf("profile %q not found", name)
+		}
+	}
+	data, err := json.MarshalIndent(activeProfile{Active: name}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := storage.WriteFile(l.activeFile(), data, 0644); err != nil {
+		return fmt.Errorf("save active profile: %w", err)
+	}
+	l.mu.Lock()
+	l.profile = name
+	l.mu.Unlock()
+	return nil
+}
+
+// DeleteProfile removes a named profile and all of its state. The default
+// profile and the active profile cannot be deleted.
+func (l *Locator) DeleteProfile(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile cannot be deleted")
+	}
+	if name == l.ActiveProfile() {
+		return fmt.Errorf("switch away from profile %q before deleting it", name)
+	}
+	dir := l.profileDir(name)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	return os.RemoveAll(dir)
+}
+
+func (l *Locator) profileDir(name string) string {
+	if name == DefaultProfile {
+		return l.root
+	}
+	return filepath.Join(l.root, "profiles", name)
+}
+
+func (l *Locator) activeFile() string {
+	return filepath.Join(l.root, "profile.json")
+}
+
+func validateName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
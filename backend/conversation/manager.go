@@ -0,0 +1,162 @@
+// Package conversation exposes agent transcripts as structured turns and
+// keeps the frontend updated as agents write to them.
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/transcript"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Transcript is a session's conversation as returned to the frontend.
+type Transcript struct {
+	SessionID string                `json:"sessionId"`
+	Agent     string                `json:"agent"`
+	Files     []string              `json:"files"` // transcript files read, oldest first
+	Turns     []transcript.Turn     `json:"turns"`
+	FileEdits []transcript.FileEdit `json:"fileEdits"`
+}
+
+// Update is emitted as session:transcript:<id> when a watched transcript
+// grows. Turns replace any existing turn with the same index.
+type Update struct {
+	SessionID string                `json:"sessionId"`
+	Turns     []transcript.Turn     `json:"turns"`
+	FileEdits []transcript.FileEdit `json:"fileEdits"`
+}
+
+// watched is the parse state of a session whose transcript was requested.
+// Its own lock lets transcripts be read without holding the manager's.
+type watched struct {
+	mu     sync.Mutex
+	parser *transcript.ConversationParser
+	live   bool // fed the transcript so far and receiving new lines
+}
+
+// Manager parses agent transcripts on demand and, as the follower reads
+// more of them, keeps the frontend updated on the ones it has opened.
+type Manager struct {
+	ctx            context.Context
+	mu             sync.Mutex
+	sessionManager *session.Manager
+	follower       *transcript.Follower
+	sessions       map[string]*watched
+}
+
+// NewManager creates a conversation manager fed by follower.
+func NewManager(sm *session.Manager, follower *transcript.Follower) *Manager {
+	m := &Manager{
+		sessionManager: sm,
+		follower:       follower,
+		sessions:       make(map[string]*watched),
+	}
+	follower.Subscribe(m.onLines, m.onGone)
+	return m
+}
+
+// SetContext sets the Wails context used to emit updates.
+func (m *Manager) SetContext(ctx context.Context) {
+	m.ctx = ctx
+}
+
+// Reload forgets every watched session, for when the sessions are replaced
+// by another profile's.
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	m.sessions = make(map[string]*watched)
+	m.mu.Unlock()
+}
+
+// GetSessionTranscript returns the session's conversation parsed from the
+// agent's transcript, and keeps emitting session:transcript:<id> updates for
+// it afterwards.
+func (m *Manager) GetSessionTranscript(id string) (Transcript, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return Transcript{}, err
+	}
+	if s.Agent != "claude" && s.Agent != "codex" {
+		return Transcript{}, fmt.Errorf("%s sessions have no transcript", s.Agent)
+	}
+
+	m.mu.Lock()
+	w, ok := m.sessions[id]
+	if !ok {
+		w = &watched{parser: transcript.NewConversationParser()}
+		// An archived session's agent can't write any more; there's nothing
+		// to watch.
+		if !s.Archived {
+			m.sessions[id] = w
+		}
+	}
+	m.mu.Unlock()
+
+	var files []string
+	if ok {
+		files = m.follower.Update(id, s.TranscriptSource())
+	} else {
+		// Parse what the follower has already read, then new lines as they come.
+		files = m.follower.Replay(id, s.TranscriptSource(), func(lines []transcript.Line) {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if !w.live {
+				feed(w, lines)
+				w.live = true
+			}
+		})
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// The caller gets everything, so pending changes needn't be emitted.
+	w.parser.Changes()
+	conv := w.parser.Conversation()
+	return Transcript{
+		SessionID: id,
+		Agent:     s.Agent,
+		Files:     files,
+		Turns:     conv.Turns,
+		FileEdits: conv.FileEdits,
+	}, nil
+}
+
+// onLines feeds new transcript lines to a watched session's parser and
+// emits what changed.
+func (m *Manager) onLines(id string, lines []transcript.Line) {
+	m.mu.Lock()
+	w := m.sessions[id]
+	m.mu.Unlock()
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	if !w.live {
+		// Replay hasn't run yet; it will include these lines.
+		w.mu.Unlock()
+		return
+	}
+	feed(w, lines)
+	turns, edits := w.parser.Changes()
+	w.mu.Unlock()
+	if m.ctx != nil && (len(turns) > 0 || len(edits) > 0) {
+		runtime.EventsEmit(m.ctx, "session:transcript:"+id, Update{SessionID: id, Turns: turns, FileEdits: edits})
+	}
+}
+
+// onGone stops watching a session that was closed.
+func (m *Manager) onGone(id string) {
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+}
+
+// feed hands lines to w's parser. Caller must hold w.mu.
+func feed(w *watched, lines []transcript.Line) {
+	for _, line := range lines {
+		w.parser.Feed(line.Format, line.Data)
+	}
+}
@@ -0,0 +1,90 @@
+// Package forge opens pull requests on code hosts (GitHub, GitLab, or a
+// local bare repository standing in for one).
+package forge
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PullRequestSpec describes the pull request to open.
+type PullRequestSpec struct {
+	Dir       string // worktree the branch is checked out in; CLI providers run here
+	RemoteURL string
+	Branch    string // head branch, already pushed
+	Base      string // target branch
+	Title     string
+	Body      string
+	Draft     bool
+}
+
+// PullRequest is an opened pull (or merge) request.
+type PullRequest struct {
+	URL      string `json:"url"`
+	Number   int    `json:"number,omitempty"`
+	Provider string `json:"provider"`
+}
+
+// Provider opens pull requests on one kind of forge.
+type Provider interface {
+	// Name identifies the provider, e.g. "github-cli".
+	Name() string
+	// Supports reports whether the provider can handle remoteURL on this machine.
+	Supports(remoteURL string) bool
+	CreatePullRequest(spec PullRequestSpec) (PullRequest, error)
+}
+
+// Providers are tried in order by Resolve.
+var Providers = []Provider{
+	Local{},
+	GitHubCLI{},
+	GitHubREST{},
+	GitLabCLI{},
+}
+
+// Resolve returns the first provider that supports remoteURL.
+func Resolve(remoteURL string) (Provider, error) {
+	for _, p := range Providers {
+		if p.Supports(remoteURL) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no pull request provider for %s (install gh or glab, or set GITHUB_TOKEN)", remoteURL)
+}
+
+// hostOf returns the host of an scp-style or URL-style git remote.
+func hostOf(remoteURL string) string {
+	s := remoteURL
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	} else if i := strings.Index(s, ":"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "/"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		s = s[i+1:]
+	}
+	if i := strings.Index(s, ":"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.ToLower(s)
+}
+
+func onPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// lastURL returns the last http(s) URL printed by a CLI.
+func lastURL(out string) string {
+	fields := strings.Fields(out)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if strings.HasPrefix(fields[i], "https://") || strings.HasPrefix(fields[i], "http://") {
+			return fields[i]
+		}
+	}
+	return ""
+}
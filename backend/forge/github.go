@@ -0,0 +1,106 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// GitHubCLI opens pull requests with the gh CLI and its stored credentials.
+type GitHubCLI struct{}
+
+func (GitHubCLI) Name() string { return "github-cli" }
+
+func (GitHubCLI) Supports(remoteURL string) bool {
+	return strings.Contains(hostOf(remoteURL), "github") && onPath("gh")
+}
+
+func (GitHubCLI) CreatePullRequest(spec PullRequestSpec) (PullRequest, error) {
+	args := []string{"pr", "create", "--head", spec.Branch, "--base", spec.Base, "--title", spec.Title, "--body", spec.Body}
+	if spec.Draft {
+		args = append(args, "--draft")
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = spec.Dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("gh pr create: %s", strings.TrimSpace(string(out)))
+	}
+	url := lastURL(string(out))
+	if url == "" {
+		return PullRequest{}, fmt.Errorf("gh pr create: no URL in output: %s", strings.TrimSpace(string(out)))
+	}
+	return PullRequest{URL: url, Provider: "github-cli"}, nil
+}
+
+// GitHubREST opens pull requests through the GitHub REST API using
+// $GITHUB_TOKEN or $GH_TOKEN. $GITHUB_API_URL points it at GitHub Enterprise.
+type GitHubREST struct{}
+
+func (GitHubREST) Name() string { return "github-rest" }
+
+func (GitHubREST) Supports(remoteURL string) bool {
+	return strings.Contains(hostOf(remoteURL), "github") && githubToken() != ""
+}
+
+func (GitHubREST) CreatePullRequest(spec PullRequestSpec) (PullRequest, error) {
+	repo, err := worktree.ParseRepoURL(spec.RemoteURL)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	api := os.Getenv("GITHUB_API_URL")
+	if api == "" {
+		api = "https://api.github.com"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"head":  spec.Branch,
+		"base":  spec.Base,
+		"title": spec.Title,
+		"body":  spec.Body,
+		"draft": spec.Draft,
+	})
+	if err != nil {
+		return PullRequest{}, err
+	}
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/repos/%s/%s/pulls", strings.TrimSuffix(api, "/"), repo.Org, repo.Repo), bytes.NewReader(body))
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+githubToken())
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("github request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusCreated {
+		return PullRequest{}, fmt.Errorf("github returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var pr struct {
+		HTMLURL string `json:"html_url"`
+		Number  int    `json:"number"`
+	}
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return PullRequest{}, fmt.Errorf("parse github response: %w", err)
+	}
+	return PullRequest{URL: pr.HTMLURL, Number: pr.Number, Provider: "github-rest"}, nil
+}
+
+func githubToken() string {
+	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
+		return t
+	}
+	return os.Getenv("GH_TOKEN")
+}
@@ -0,0 +1,35 @@
+package forge
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GitLabCLI opens merge requests with the glab CLI.
+type GitLabCLI struct{}
+
+func (GitLabCLI) Name() string { return "gitlab-cli" }
+
+func (GitLabCLI) Supports(remoteURL string) bool {
+	return strings.Contains(hostOf(remoteURL), "gitlab") && onPath("glab")
+}
+
+func (GitLabCLI) CreatePullRequest(spec PullRequestSpec) (PullRequest, error) {
+	args := []string{"mr", "create", "--source-branch", spec.Branch, "--target-branch", spec.Base,
+		"--title", spec.Title, "--description", spec.Body, "--yes"}
+	if spec.Draft {
+		args = append(args, "--draft")
+	}
+	cmd := exec.Command("glab", args...)
+	cmd.Dir = spec.Dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("glab mr create: %s", strings.TrimSpace(string(out)))
+	}
+	url := lastURL(string(out))
+	if url == "" {
+		return PullRequest{}, fmt.Errorf("glab mr create: no URL in output: %s", strings.TrimSpace(string(out)))
+	}
+	return PullRequest{URL: url, Provider: "gitlab-cli"}, nil
+}
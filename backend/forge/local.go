@@ -0,0 +1,114 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pullsDir is where Local records pull requests inside the bare repository.
+const pullsDir = "aim-pulls"
+
+// Local treats a bare repository on the local filesystem as a forge. Pull
+// requests are recorded as JSON files inside it, which lets the whole
+// commit/push/PR flow run without a network or credentials.
+type Local struct{}
+
+// LocalPullRequest is the record Local writes for each pull request.
+type LocalPullRequest struct {
+	Number  int       `json:"number"`
+	Branch  string    `json:"branch"`
+	Base    string    `json:"base"`
+	Head    string    `json:"head"` // commit the branch pointed at
+	Title   string    `json:"title"`
+	Body    string    `json:"body"`
+	Draft   bool      `json:"draft"`
+	Created time.Time `json:"created"`
+}
+
+func (Local) Name() string { return "local" }
+
+func (Local) Supports(remoteURL string) bool {
+	dir := localPath(remoteURL)
+	if dir == "" {
+		return false
+	}
+	out, err := exec.Command("git", "--git-dir", dir, "rev-parse", "--is-bare-repository").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+func (Local) CreatePullRequest(spec PullRequestSpec) (PullRequest, error) {
+	dir := localPath(spec.RemoteURL)
+	head, err := exec.Command("git", "--git-dir", dir, "rev-parse", "--verify", "refs/heads/"+spec.Branch).Output()
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("branch %s has not been pushed to %s", spec.Branch, dir)
+	}
+	if err := exec.Command("git", "--git-dir", dir, "rev-parse", "--verify", "refs/heads/"+spec.Base).Run(); err != nil {
+		return PullRequest{}, fmt.Errorf("base branch %s not found in %s", spec.Base, dir)
+	}
+
+	pulls := filepath.Join(dir, pullsDir)
+	if err := os.MkdirAll(pulls, 0755); err != nil {
+		return PullRequest{}, fmt.Errorf("create %s: %w", pulls, err)
+	}
+	for n := 1; ; n++ {
+		path := filepath.Join(pulls, fmt.Sprintf("%d.json", n))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return PullRequest{}, fmt.Errorf("record pull request: %w", err)
+		}
+		rec := LocalPullRequest{
+			Number:  n,
+			Branch:  spec.Branch,
+			Base:    spec.Base,
+			Head:    strings.TrimSpace(string(head)),
+			Title:   spec.Title,
+			Body:    spec.Body,
+			Draft:   spec.Draft,
+			Created: time.Now(),
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rec)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return PullRequest{}, fmt.Errorf("record pull request: %w", err)
+		}
+		return PullRequest{URL: fmt.Sprintf("file://%s#pull/%d", dir, n), Number: n, Provider: "local"}, nil
+	}
+}
+
+// PullRequest reads back pull request number from the bare repository at
+// remoteURL.
+func (Local) PullRequest(remoteURL string, number int) (LocalPullRequest, error) {
+	path := filepath.Join(localPath(remoteURL), pullsDir, fmt.Sprintf("%d.json", number))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LocalPullRequest{}, fmt.Errorf("pull request %d: %w", number, err)
+	}
+	var rec LocalPullRequest
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return LocalPullRequest{}, fmt.Errorf("pull request %d: %w", number, err)
+	}
+	return rec, nil
+}
+
+// localPath returns the directory a local remote URL refers to, or "".
+func localPath(remoteURL string) string {
+	switch {
+	case strings.HasPrefix(remoteURL, "file://"):
+		return strings.TrimPrefix(remoteURL, "file://")
+	case filepath.IsAbs(remoteURL):
+		return remoteURL
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package forge
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-C", dir}, args...)
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return string(out)
+}
+
+func TestLocalPullRequestFlow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "aim")
+	t.Setenv("GIT_AUTHOR_EMAIL", "aim@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "aim")
+	t.Setenv("GIT_COMMITTER_EMAIL", "aim@example.com")
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	clone := filepath.Join(root, "clone")
+	git(t, root, "init", "--bare", "--quiet", "--initial-branch=main", remote)
+	git(t, root, "clone", "--quiet", remote, clone)
+	git(t, clone, "checkout", "--quiet", "-b", "main")
+	git(t, clone, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	git(t, clone, "push", "--quiet", "origin", "main")
+	git(t, clone, "checkout", "--quiet", "-b", "aim/feature")
+
+	if err := os.WriteFile(filepath.Join(clone, "feature.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt := worktree.NewManager()
+	head, err := wt.CommitWorktree(clone, "Add feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := wt.PushBranch(clone, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "aim/feature" {
+		t.Fatalf("pushed %q, want aim/feature", branch)
+	}
+
+	remoteURL, err := worktree.RemoteURL(clone, "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := Resolve(remoteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Name() != "local" {
+		t.Fatalf("resolved %s, want local", provider.Name())
+	}
+	pr, err := provider.CreatePullRequest(PullRequestSpec{
+		Dir:       clone,
+		RemoteURL: remoteURL,
+		Branch:    branch,
+		Base:      "main",
+		Title:     "Add feature",
+		Body:      "Adds feature.txt",
+		Draft:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 1 || pr.Provider != "local" {
+		t.Errorf("opened %+v, want local #1", pr)
+	}
+
+	rec, err := Local{}.PullRequest(remoteURL, pr.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Branch != branch || rec.Base != "main" || rec.Head != head || rec.Title != "Add feature" || !rec.Draft {
+		t.Errorf("recorded %+v", rec)
+	}
+
+	// A second pull request gets the next number.
+	second, err := provider.CreatePullRequest(PullRequestSpec{RemoteURL: remoteURL, Branch: branch, Base: "main", Title: "Again"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Number != 2 {
+		t.Errorf("second pull request is #%d, want #2", second.Number)
+	}
+
+	if _, err := provider.CreatePullRequest(PullRequestSpec{RemoteURL: remoteURL, Branch: "unpushed", Base: "main"}); err == nil {
+		t.Error("opened a pull request for a branch that was never pushed")
+	}
+}
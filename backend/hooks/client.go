@@ -0,0 +1,43 @@
+package hooks
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+const clientTimeout = 2 * time.Second
+
+// RunClient forwards one hook invocation to the aim app and returns the
+// process exit code. It always returns 0: a missing or unresponsive aim must
+// never block or fail the agent's own hook pipeline.
+func RunClient(stdin io.Reader) int {
+	sessionID := os.Getenv(SessionEnv)
+	socket := os.Getenv(SocketEnv)
+	if sessionID == "" || socket == "" {
+		return 0
+	}
+	payload, err := io.ReadAll(io.LimitReader(stdin, 8*1024*1024))
+	if err != nil {
+		return 0
+	}
+	ev, err := parseEvent(sessionID, payload)
+	if err != nil {
+		return 0
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return 0
+	}
+
+	conn, err := net.DialTimeout("unix", socket, clientTimeout)
+	if err != nil {
+		return 0
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(clientTimeout))
+	_, _ = conn.Write(append(data, '\n'))
+	return 0
+}
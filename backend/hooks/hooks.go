@@ -0,0 +1,111 @@
+// Package hooks receives Claude Code hook callbacks from agent sessions.
+//
+// aim launches Claude with a settings file whose hooks run "aim hook". That
+// client forwards the hook payload over a local Unix socket to the running
+// app, keyed by the AIM_SESSION_ID of the session that launched the agent.
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// ClientArg is the argv[1] that runs the aim binary as a hook client.
+	ClientArg = "hook"
+	// SocketEnv carries the hook socket path into agent sessions.
+	SocketEnv = "AIM_HOOK_SOCKET"
+	// SessionEnv carries the aim session ID into agent sessions.
+	SessionEnv = "AIM_SESSION_ID"
+)
+
+// Claude Code hook event names aim subscribes to.
+const (
+	EventUserPromptSubmit = "UserPromptSubmit"
+	EventPreToolUse       = "PreToolUse"
+	EventPostToolUse      = "PostToolUse"
+	EventNotification     = "Notification"
+	EventStop             = "Stop"
+)
+
+// subscribed lists the hooks written into the Claude settings file.
+var subscribed = []string{
+	EventUserPromptSubmit,
+	EventPreToolUse,
+	EventPostToolUse,
+	EventNotification,
+	EventStop,
+}
+
+// Event is a single hook callback reported by an agent.
+type Event struct {
+	SessionID string          `json:"sessionId"`          // aim session ID
+	Name      string          `json:"name"`               // hook event name
+	ToolName  string          `json:"toolName,omitempty"` // PreToolUse/PostToolUse
+	Message   string          `json:"message,omitempty"`  // Notification
+	Prompt    string          `json:"prompt,omitempty"`   // UserPromptSubmit
+	AgentID   string          `json:"agentId,omitempty"`  // the agent's own session ID
+	Payload   json.RawMessage `json:"payload,omitempty"`  // raw hook input
+}
+
+// hookInput is the subset of Claude's hook stdin payload aim reads.
+type hookInput struct {
+	SessionID     string `json:"session_id"`
+	HookEventName string `json:"hook_event_name"`
+	ToolName      string `json:"tool_name"`
+	Message       string `json:"message"`
+	Prompt        string `json:"prompt"`
+}
+
+// parseEvent builds an Event from a hook's stdin payload.
+func parseEvent(sessionID string, payload []byte) (Event, error) {
+	var in hookInput
+	if err := json.Unmarshal(payload, &in); err != nil {
+		return Event{}, fmt.Errorf("parse hook input: %w", err)
+	}
+	if in.HookEventName == "" {
+		return Event{}, fmt.Errorf("hook input has no hook_event_name")
+	}
+	return Event{
+		SessionID: sessionID,
+		Name:      in.HookEventName,
+		ToolName:  in.ToolName,
+		Message:   in.Message,
+		Prompt:    in.Prompt,
+		AgentID:   in.SessionID,
+		Payload:   json.RawMessage(payload),
+	}, nil
+}
+
+// ClaudeSettings returns a Claude Code settings document that runs command
+// for every subscribed hook. Pass it to claude with --settings.
+func ClaudeSettings(command string) ([]byte, error) {
+	type hookCommand struct {
+		Type    string `json:"type"`
+		Command string `json:"command"`
+		Timeout int    `json:"timeout"`
+	}
+	type matcher struct {
+		Matcher string        `json:"matcher,omitempty"`
+		Hooks   []hookCommand `json:"hooks"`
+	}
+	hooks := make(map[string][]matcher, len(subscribed))
+	for _, name := range subscribed {
+		m := matcher{Hooks: []hookCommand{{Type: "command", Command: command, Timeout: 5}}}
+		if name == EventPreToolUse || name == EventPostToolUse {
+			m.Matcher = "*"
+		}
+		hooks[name] = []matcher{m}
+	}
+	return json.MarshalIndent(map[string]interface{}{"hooks": hooks}, "", "  ")
+}
+
+// ClientCommand returns the shell command hooks use to invoke exe as a client.
+func ClientCommand(exe string) string {
+	return shellQuote(exe) + " " + ClientArg
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,100 @@
+package hooks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Server accepts hook events from agent sessions on a Unix socket.
+type Server struct {
+	mu       sync.Mutex
+	path     string
+	listener net.Listener
+	handler  func(Event)
+}
+
+// NewServer creates a server that passes every received event to handler.
+// Its socket is created in dir, which is made private to the user so no one
+// else can connect or swap the socket; see RuntimeDir.
+func NewServer(dir string, handler func(Event)) *Server {
+	return &Server{
+		path:    filepath.Join(dir, fmt.Sprintf("aim-%d-hooks.sock", os.Getpid())),
+		handler: handler,
+	}
+}
+
+// RuntimeDir returns the directory for aim's sockets: aim's own directory
+// under $XDG_RUNTIME_DIR when set, otherwise "run" under root.
+func RuntimeDir(root string) string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "aim")
+	}
+	return filepath.Join(root, "run")
+}
+
+// SocketPath returns the socket agents should report to.
+func (s *Server) SocketPath() string {
+	return s.path
+}
+
+// Start begins listening. It is safe to call once.
+func (s *Server) Start() error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create hook socket dir: %w", err)
+	}
+	// MkdirAll leaves an existing directory's mode alone.
+	if err := os.Chmod(dir, 0700); err != nil {
+		return fmt.Errorf("create hook socket dir: %w", err)
+	}
+	_ = os.Remove(s.path) // stale socket from a crashed run with the same pid
+	l, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("listen on hook socket: %w", err)
+	}
+
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 64*1024), 8*1024*1024)
+	for sc.Scan() {
+		var ev Event
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil || ev.SessionID == "" {
+			continue
+		}
+		s.handler(ev)
+	}
+}
+
+// Close stops listening and removes the socket.
+func (s *Server) Close() {
+	s.mu.Lock()
+	l := s.listener
+	s.listener = nil
+	s.mu.Unlock()
+	if l != nil {
+		_ = l.Close()
+		_ = os.Remove(s.path)
+	}
+}
@@ -282,6 +282,16 @@ func (m *Manager) UpdateIssueState(issueID, stateID string) error {
 	return err
 }
 
+// AttachLink attaches a URL (e.g. a pull request) to an issue.
+func (m *Manager) AttachLink(issueID, url, title string) error {
+	_, err := m.doQuery(mutationAttachmentLinkURL, map[string]interface{}{
+		"issueId": issueID,
+		"url":     url,
+		"title":   title,
+	})
+	return err
+}
+
 // StartPolling begins polling the active cycle for changes.
 func (m *Manager) StartPolling(teamID string, intervalSec int) {
 	m.mu.Lock()
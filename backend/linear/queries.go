@@ -146,3 +146,9 @@ const mutationUpdateIssueState = `mutation($id: String!, $stateId: String!) {
     }
   }
 }`
+
+const mutationAttachmentLinkURL = `mutation($issueId: String!, $url: String!, $title: String) {
+  attachmentLinkURL(issueId: $issueId, url: $url, title: $title) {
+    success
+  }
+}`
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	goruntime "runtime"
+	"strings"
+	"time"
+)
+
+const commandTimeout = 30 * time.Second
+
+// send delivers n through ch.
+func send(client *http.Client, ch Channel, n Notification) error {
+	switch ch.Type {
+	case ChannelDesktop:
+		return sendDesktop(n)
+	case ChannelCommand:
+		return sendCommand(ch.Command, n)
+	case ChannelWebhook:
+		return sendWebhook(client, ch.WebhookURL, n)
+	default:
+		return fmt.Errorf("unknown channel type %q", ch.Type)
+	}
+}
+
+func sendDesktop(n Notification) error {
+	var cmd *exec.Cmd
+	switch goruntime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Message), appleScriptString(n.Title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", "--app-name=aim", n.Title, n.Message)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", goruntime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", cmd.Path, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func sendCommand(command string, n Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = notificationEnv(n)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func sendWebhook(client *http.Client, url string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aim")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
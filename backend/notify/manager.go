@@ -0,0 +1,366 @@
+// Package notify delivers session state changes to desktop notifications,
+// user commands and webhooks.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/agent"
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/storage"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Channel types.
+const (
+	ChannelDesktop = "desktop"
+	ChannelCommand = "command"
+	ChannelWebhook = "webhook"
+)
+
+// Triggers a rule can subscribe to.
+const (
+	TriggerDone     = "done"     // agent finished its turn (thinking/waiting -> idle)
+	TriggerWaiting  = "waiting"  // agent needs input or permission
+	TriggerStuck    = "stuck"    // agent has been waiting past the stuck threshold
+	TriggerErrored  = "errored"  // agent process exited with an error
+	TriggerStopped  = "stopped"  // agent process exited cleanly
+	TriggerThinking = "thinking" // agent started working
+)
+
+var validTriggers = map[string]bool{
+	TriggerDone: true, TriggerWaiting: true, TriggerStuck: true,
+	TriggerErrored: true, TriggerStopped: true, TriggerThinking: true,
+}
+
+// QuietHours suppresses notifications between Start and End (local "HH:MM").
+// A range where End is before Start wraps past midnight.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Rule decides which events a channel delivers for a workspace.
+type Rule struct {
+	WorkspaceID     string      `json:"workspaceId"` // "" = any workspace without its own rule
+	Triggers        []string    `json:"triggers"`
+	QuietHours      *QuietHours `json:"quietHours,omitempty"`
+	DebounceSeconds int         `json:"debounceSeconds"` // min gap per session and trigger
+}
+
+// Channel is a notification destination with its own rules.
+type Channel struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"` // "desktop", "command", "webhook"
+	Enabled    bool   `json:"enabled"`
+	Command    string `json:"command,omitempty"`    // command channels; run with sh -c
+	WebhookURL string `json:"webhookUrl,omitempty"` // webhook channels
+	Rules      []Rule `json:"rules"`
+}
+
+// Config is the persisted notification configuration.
+type Config struct {
+	Channels []Channel `json:"channels"`
+}
+
+// Notification is the payload delivered to every channel.
+type Notification struct {
+	Trigger     string    `json:"trigger"`
+	Title       string    `json:"title"`
+	Message     string    `json:"message"`
+	SessionID   string    `json:"sessionId"`
+	SessionName string    `json:"sessionName"`
+	WorkspaceID string    `json:"workspaceId"`
+	Agent       string    `json:"agent"`
+	Status      string    `json:"status"`
+	PrevStatus  string    `json:"previousStatus"`
+	Time        time.Time `json:"time"`
+}
+
+var configSchema = storage.Schema{
+	Name:    "notifications.json",
+	Version: 1,
+	Migrations: []storage.Migration{
+		{From: 0, Description: "add version envelope", Apply: storage.Wrap},
+	},
+}
+
+// Manager subscribes to agent.Tracker events and dispatches notifications.
+type Manager struct {
+	ctx        context.Context
+	mu         sync.RWMutex
+	locator    *config.Locator
+	cfg        Config
+	lastSent   map[string]time.Time // channelID|sessionID|trigger -> last delivery
+	httpClient *http.Client
+}
+
+// NewManager creates a notifier subscribed to tracker.
+func NewManager(locator *config.Locator, tracker *agent.Tracker) *Manager {
+	m := &Manager{
+		locator:    locator,
+		lastSent:   make(map[string]time.Time),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	tracker.Subscribe(m.handleEvent)
+	return m
+}
+
+// SetContext sets the Wails context and loads the configuration.
+func (m *Manager) SetContext(ctx context.Context) {
+	m.ctx = ctx
+	m.Reload()
+}
+
+// Reload re-reads the configuration from the active profile.
+func (m *Manager) Reload() {
+	var cfg Config
+	rec, err := configSchema.Load(m.confPath(), &cfg)
+	if rec != nil {
+		runtime.EventsEmit(m.ctx, storage.RecoveredEvent, rec)
+	}
+	if err != nil {
+		cfg = Config{}
+	}
+	m.mu.Lock()
+	m.cfg = cfg
+	m.lastSent = make(map[string]time.Time)
+	m.mu.Unlock()
+}
+
+// GetNotificationConfig returns the channels and their rules.
+func (m *Manager) GetNotificationConfig() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// SaveNotificationConfig validates and persists the configuration. Channels
+// without an ID are assigned one.
+func (m *Manager) SaveNotificationConfig(cfg Config) (Config, error) {
+	for i := range cfg.Channels {
+		ch := &cfg.Channels[i]
+		if ch.ID == "" {
+			ch.ID = uuid.New().String()
+		}
+		if err := validateChannel(*ch); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := configSchema.Save(m.confPath(), cfg); err != nil {
+		return Config{}, fmt.Errorf("save notifications: %w", err)
+	}
+	m.mu.Lock()
+	m.cfg = cfg
+	m.mu.Unlock()
+	return cfg, nil
+}
+
+// TestChannel sends a sample notification through a channel, ignoring its rules.
+func (m *Manager) TestChannel(channelID string) error {
+	ch, ok := m.channel(channelID)
+	if !ok {
+		return fmt.Errorf("channel %s not found", channelID)
+	}
+	return send(m.httpClient, ch, Notification{
+		Trigger: "test",
+		Title:   "aim",
+		Message: fmt.Sprintf("Test notification from channel %q", ch.Name),
+		Time:    time.Now(),
+	})
+}
+
+func (m *Manager) handleEvent(ev agent.Event) {
+	n, ok := toNotification(ev)
+	if !ok {
+		return
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	var due []Channel
+	for _, ch := range m.cfg.Channels {
+		if !ch.Enabled {
+			continue
+		}
+		rule, ok := ruleFor(ch, n.WorkspaceID)
+		if !ok || !containsString(rule.Triggers, n.Trigger) || inQuietHours(rule.QuietHours, now) {
+			continue
+		}
+		key := ch.ID + "|" + n.SessionID + "|" + n.Trigger
+		if last, ok := m.lastSent[key]; ok && now.Sub(last) < time.Duration(rule.DebounceSeconds)*time.Second {
+			continue
+		}
+		m.lastSent[key] = now
+		due = append(due, ch)
+	}
+	m.mu.Unlock()
+
+	for _, ch := range due {
+		go func(ch Channel) {
+			if err := send(m.httpClient, ch, n); err != nil && m.ctx != nil {
+				runtime.LogWarningf(m.ctx, "notify %s: %v", ch.Name, err)
+				runtime.EventsEmit(m.ctx, "notify:error", map[string]string{
+					"channelId": ch.ID,
+					"error":     err.Error(),
+				})
+			}
+		}(ch)
+	}
+}
+
+func (m *Manager) channel(id string) (Channel, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, ch := range m.cfg.Channels {
+		if ch.ID == id {
+			return ch, true
+		}
+	}
+	return Channel{}, false
+}
+
+func (m *Manager) confPath() string {
+	return m.locator.Path("notifications.json")
+}
+
+// toNotification maps a tracker event to a trigger and message.
+func toNotification(ev agent.Event) (Notification, bool) {
+	name := ev.SessionName
+	if name == "" {
+		name = ev.SessionID
+	}
+	n := Notification{
+		Title:       "aim — " + name,
+		SessionID:   ev.SessionID,
+		SessionName: ev.SessionName,
+		WorkspaceID: ev.WorkspaceID,
+		Agent:       ev.Agent,
+		Status:      ev.To,
+		PrevStatus:  ev.From,
+		Time:        ev.Time,
+	}
+	switch {
+	case ev.Type == agent.EventStuck:
+		n.Trigger, n.Message = TriggerStuck, "Agent looks stuck waiting for input"
+	case ev.To == session.StatusIdle && (ev.From == session.StatusThinking || ev.From == session.StatusWaiting):
+		n.Trigger, n.Message = TriggerDone, "Agent finished"
+	case ev.To == session.StatusWaiting:
+		n.Trigger, n.Message = TriggerWaiting, "Agent is waiting for you"
+	case ev.To == session.StatusErrored:
+		n.Trigger, n.Message = TriggerErrored, "Agent exited with an error"
+	case ev.To == session.StatusStopped:
+		n.Trigger, n.Message = TriggerStopped, "Agent stopped"
+	case ev.To == session.StatusThinking:
+		n.Trigger, n.Message = TriggerThinking, "Agent is working"
+	default:
+		return Notification{}, false
+	}
+	return n, true
+}
+
+// ruleFor returns the channel's rule for workspaceID, falling back to its
+// catch-all rule.
+func ruleFor(ch Channel, workspaceID string) (Rule, bool) {
+	var fallback *Rule
+	for i := range ch.Rules {
+		r := ch.Rules[i]
+		if r.WorkspaceID == workspaceID && workspaceID != "" {
+			return r, true
+		}
+		if r.WorkspaceID == "" && fallback == nil {
+			fallback = &r
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Rule{}, false
+}
+
+func inQuietHours(q *QuietHours, now time.Time) bool {
+	if q == nil || q.Start == "" || q.End == "" {
+		return false
+	}
+	start, err1 := time.Parse("15:04", q.Start)
+	end, err2 := time.Parse("15:04", q.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	s := start.Hour()*60 + start.Minute()
+	e := end.Hour()*60 + end.Minute()
+	if s <= e {
+		return cur >= s && cur < e
+	}
+	return cur >= s || cur < e
+}
+
+func validateChannel(ch Channel) error {
+	switch ch.Type {
+	case ChannelDesktop:
+	case ChannelCommand:
+		if strings.TrimSpace(ch.Command) == "" {
+			return fmt.Errorf("channel %q: command is required", ch.Name)
+		}
+	case ChannelWebhook:
+		if !strings.HasPrefix(ch.WebhookURL, "http://") && !strings.HasPrefix(ch.WebhookURL, "https://") {
+			return fmt.Errorf("channel %q: webhook URL must be http(s)", ch.Name)
+		}
+	default:
+		return fmt.Errorf("channel %q: unknown type %q", ch.Name, ch.Type)
+	}
+	for _, r := range ch.Rules {
+		for _, t := range r.Triggers {
+			if !validTriggers[t] {
+				return fmt.Errorf("channel %q: unknown trigger %q", ch.Name, t)
+			}
+		}
+		if r.QuietHours != nil {
+			for _, v := range []string{r.QuietHours.Start, r.QuietHours.End} {
+				if _, err := time.Parse("15:04", v); err != nil {
+					return fmt.Errorf("channel %q: quiet hours must be HH:MM, got %q", ch.Name, v)
+				}
+			}
+		}
+		if r.DebounceSeconds < 0 {
+			return fmt.Errorf("channel %q: debounce must not be negative", ch.Name)
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationEnv exposes a notification to command channels.
+func notificationEnv(n Notification) []string {
+	payload, _ := json.Marshal(n)
+	return append(os.Environ(),
+		"AIM_TRIGGER="+n.Trigger,
+		"AIM_TITLE="+n.Title,
+		"AIM_MESSAGE="+n.Message,
+		"AIM_SESSION_ID="+n.SessionID,
+		"AIM_SESSION_NAME="+n.SessionName,
+		"AIM_WORKSPACE_ID="+n.WorkspaceID,
+		"AIM_STATUS="+n.Status,
+		"AIM_PREVIOUS_STATUS="+n.PrevStatus,
+		"AIM_NOTIFICATION="+string(payload),
+	)
+}
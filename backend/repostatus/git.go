@@ -0,0 +1,328 @@
+package repostatus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// gitDebounce is how long a work dir must stay quiet after a change
+	// before its status is recomputed, and gitMaxDelay how long a stream of
+	// changes may put that off.
+	gitDebounce = 300 * time.Millisecond
+	gitMaxDelay = 3 * time.Second
+	// rewatchInterval is how often the set of watched work dirs is brought
+	// in line with the running sessions, in case a change was missed.
+	rewatchInterval = 30 * time.Second
+	// gitPollInterval is how often running sessions' work dirs are checked
+	// when they can't be watched.
+	gitPollInterval = 5 * time.Second
+)
+
+// gitWatcher watches the work dirs of running sessions. It is only used
+// from the goroutine started by watchGit and needs no locking.
+type gitWatcher struct {
+	w       *fsnotify.Watcher
+	owners  map[string][]string // watched dir -> work dirs it belongs to
+	dirs    map[string][]string // work dir -> watched dirs
+	ids     map[string][]string // work dir -> sessions working in it
+	notRepo map[string]bool     // work dirs found not to be in a git repository
+	pending map[string]bool     // work dirs changed since the last refresh
+	since   time.Time           // when the oldest pending change happened
+}
+
+// GetGitStatus returns the current git status of a session's work dir.
+func (m *Manager) GetGitStatus(id string) (worktree.GitStatus, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return worktree.GitStatus{}, err
+	}
+	return m.refreshGit(s)
+}
+
+// GetGitStatuses returns the last known git status of every session, keyed
+// by session ID. Sessions whose work dir isn't a git repository are omitted.
+func (m *Manager) GetGitStatuses() map[string]worktree.GitStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]worktree.GitStatus, len(m.gitStatuses))
+	for id, st := range m.gitStatuses {
+		result[id] = st
+	}
+	return result
+}
+
+// onStatusChange refreshes a session's git status when its agent finishes a
+// turn, since that's when it has most likely changed files, and has the
+// watcher pick up sessions that started or stopped.
+func (m *Manager) onStatusChange(id, from, to string) {
+	select {
+	case m.rewatch <- struct{}{}:
+	default:
+	}
+	if to != session.StatusIdle && to != session.StatusStopped && to != session.StatusErrored {
+		return
+	}
+	go func() {
+		if s, err := m.sessionManager.GetSession(id); err == nil {
+			_, _ = m.refreshGit(s)
+		}
+	}()
+}
+
+// watchGit refreshes the git status of running sessions as their work dirs
+// change, until stop is closed. It falls back to polling if the file system
+// can't be watched.
+func (m *Manager) watchGit(stop <-chan struct{}) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		m.pollGit(stop)
+		return
+	}
+	defer w.Close()
+	gw := &gitWatcher{
+		w:       w,
+		owners:  make(map[string][]string),
+		dirs:    make(map[string][]string),
+		ids:     make(map[string][]string),
+		notRepo: make(map[string]bool),
+		pending: make(map[string]bool),
+	}
+	m.updateWatches(gw)
+
+	rewatch := time.NewTicker(rewatchInterval)
+	defer rewatch.Stop()
+	debounce := time.NewTimer(gitDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if m.noteChange(gw, ev) && time.Since(gw.since) < gitMaxDelay {
+				debounce.Reset(gitDebounce)
+			}
+		case _, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce.C:
+			m.refreshPending(gw)
+		case <-rewatch.C:
+			m.updateWatches(gw)
+		case <-m.rewatch:
+			m.updateWatches(gw)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// pollGit refreshes running sessions on a timer until stop is closed.
+func (m *Manager) pollGit(stop <-chan struct{}) {
+	ticker := time.NewTicker(gitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, s := range m.sessionManager.ListSessions() {
+				if watchable(s) && inGitRepo(workDir(s)) {
+					_, _ = m.refreshGit(s)
+				}
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// refreshAllGit refreshes every session whose work dir is in a git
+// repository, running or not.
+func (m *Manager) refreshAllGit() {
+	live := m.sessionManager.ListSessions()
+	for _, s := range live {
+		if !s.Archived && inGitRepo(workDir(s)) {
+			_, _ = m.refreshGit(s)
+		}
+	}
+	m.forgetGit(live)
+}
+
+// updateWatches watches the work dirs of running sessions and stops
+// watching the rest.
+func (m *Manager) updateWatches(gw *gitWatcher) {
+	live := m.sessionManager.ListSessions()
+	want := make(map[string][]string)
+	for _, s := range live {
+		if watchable(s) {
+			want[workDir(s)] = append(want[workDir(s)], s.ID)
+		}
+	}
+	for dir := range gw.dirs {
+		if _, ok := want[dir]; !ok {
+			gw.unwatch(dir)
+		}
+	}
+	for dir, ids := range want {
+		gw.ids[dir] = ids
+		if _, ok := gw.dirs[dir]; ok || gw.notRepo[dir] {
+			continue
+		}
+		if !inGitRepo(dir) {
+			gw.notRepo[dir] = true
+			continue
+		}
+		dirs, err := worktree.WatchDirs(dir)
+		if err != nil {
+			continue
+		}
+		gw.dirs[dir] = nil
+		for _, d := range dirs {
+			gw.watch(d, dir)
+		}
+		// Catch up with whatever changed while it wasn't watched.
+		gw.pending[dir] = true
+	}
+	for dir := range gw.ids {
+		if _, ok := want[dir]; !ok {
+			delete(gw.ids, dir)
+		}
+	}
+	m.refreshPending(gw)
+	m.forgetGit(live)
+}
+
+// noteChange records which work dirs ev touches and watches directories
+// created in them. It reports whether ev touched any.
+func (m *Manager) noteChange(gw *gitWatcher, ev fsnotify.Event) bool {
+	owners := gw.owners[filepath.Dir(ev.Name)]
+	if len(owners) == 0 {
+		return false
+	}
+	if len(gw.pending) == 0 {
+		gw.since = time.Now()
+	}
+	for _, dir := range owners {
+		gw.pending[dir] = true
+		inTree := strings.HasPrefix(ev.Name, dir+string(filepath.Separator)) &&
+			!strings.HasPrefix(ev.Name, filepath.Join(dir, ".git")+string(filepath.Separator))
+		if ev.Has(fsnotify.Create) && inTree {
+			if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() && !worktree.IsIgnored(dir, ev.Name) {
+				gw.watch(ev.Name, dir)
+			}
+		}
+	}
+	return true
+}
+
+// refreshPending refreshes the sessions working in changed work dirs.
+func (m *Manager) refreshPending(gw *gitWatcher) {
+	for dir := range gw.pending {
+		for _, id := range gw.ids[dir] {
+			if s, err := m.sessionManager.GetSession(id); err == nil {
+				_, _ = m.refreshGit(s)
+			}
+		}
+	}
+	gw.pending = make(map[string]bool)
+}
+
+// forgetGit drops the git status of sessions that no longer exist.
+func (m *Manager) forgetGit(live []session.SessionState) {
+	ids := make(map[string]bool, len(live))
+	for _, s := range live {
+		ids[s.ID] = true
+	}
+	m.mu.Lock()
+	for id := range m.gitStatuses {
+		if !ids[id] {
+			delete(m.gitStatuses, id)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// watch adds dir to the directories watched for workDir.
+func (gw *gitWatcher) watch(dir, workDir string) {
+	for _, owner := range gw.owners[dir] {
+		if owner == workDir {
+			return
+		}
+	}
+	if len(gw.owners[dir]) == 0 && gw.w.Add(dir) != nil {
+		return
+	}
+	gw.owners[dir] = append(gw.owners[dir], workDir)
+	gw.dirs[workDir] = append(gw.dirs[workDir], dir)
+}
+
+// unwatch stops watching workDir, keeping directories other work dirs
+// still need.
+func (gw *gitWatcher) unwatch(workDir string) {
+	for _, dir := range gw.dirs[workDir] {
+		var rest []string
+		for _, owner := range gw.owners[dir] {
+			if owner != workDir {
+				rest = append(rest, owner)
+			}
+		}
+		if len(rest) == 0 {
+			delete(gw.owners, dir)
+			_ = gw.w.Remove(dir)
+		} else {
+			gw.owners[dir] = rest
+		}
+	}
+	delete(gw.dirs, workDir)
+	delete(gw.pending, workDir)
+}
+
+// refreshGit computes and stores the git status of s, emitting
+// session:git:<id> when it changed.
+func (m *Manager) refreshGit(s session.SessionState) (worktree.GitStatus, error) {
+	st, err := m.worktreeManager.GetGitStatus(workDir(s))
+	if err != nil {
+		return st, err
+	}
+	m.mu.Lock()
+	prev, existed := m.gitStatuses[s.ID]
+	m.gitStatuses[s.ID] = st
+	m.mu.Unlock()
+	if !existed || prev != st {
+		m.emit("session:git:"+s.ID, st)
+	}
+	return st, nil
+}
+
+// watchable reports whether s has a running agent whose work dir may change.
+func watchable(s session.SessionState) bool {
+	return !s.Archived && s.Status != session.StatusStopped && s.Status != session.StatusErrored
+}
+
+// workDir returns the directory s's agent works in.
+func workDir(s session.SessionState) string {
+	if s.WorktreePath != "" {
+		return filepath.Clean(s.WorktreePath)
+	}
+	return filepath.Clean(s.Directory)
+}
+
+// inGitRepo reports whether dir is inside a git checkout, without running
+// git.
+func inGitRepo(dir string) bool {
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return true
+		}
+		if filepath.Dir(d) == d {
+			return false
+		}
+	}
+}
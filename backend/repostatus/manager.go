@@ -0,0 +1,148 @@
+// Package repostatus tracks the git state of session work dirs — working
+// tree status and how far each branch has drifted from its base — and keeps
+// the frontend informed as it changes.
+package repostatus
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	syncInterval  = 2 * time.Minute  // how often ahead/behind is recomputed
+	fetchInterval = 10 * time.Minute // how often each repository is fetched
+)
+
+// Manager watches session work dirs for git status changes, and periodically
+// fetches session repositories to compute how each branch relates to its base.
+type Manager struct {
+	ctx             context.Context
+	mu              sync.RWMutex
+	sessionManager  *session.Manager
+	worktreeManager *worktree.Manager
+	syncs           map[string]SyncStatus         // session ID -> last sync status
+	gitStatuses     map[string]worktree.GitStatus // session ID -> last git status
+	fetched         map[string]time.Time          // repo path -> last fetch
+	rewatch         chan struct{}                 // asks the git watcher to update what it watches
+	stopCh          chan struct{}
+	running         bool
+}
+
+// NewManager creates a repository status manager.
+func NewManager(sm *session.Manager, wm *worktree.Manager) *Manager {
+	m := &Manager{
+		sessionManager:  sm,
+		worktreeManager: wm,
+		syncs:           make(map[string]SyncStatus),
+		gitStatuses:     make(map[string]worktree.GitStatus),
+		fetched:         make(map[string]time.Time),
+		rewatch:         make(chan struct{}, 1),
+	}
+	sm.AddStatusListener(m.onStatusChange)
+	return m
+}
+
+// SetContext sets the Wails context and starts the background checks.
+func (m *Manager) SetContext(ctx context.Context) {
+	m.ctx = ctx
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.stopCh = make(chan struct{})
+	m.running = true
+	stop := m.stopCh
+	m.mu.Unlock()
+
+	go func() {
+		m.syncAll()
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.syncAll()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go func() {
+		m.refreshAllGit()
+		m.watchGit(stop)
+	}()
+}
+
+// Reload forgets the previous profile's sessions after a profile switch and
+// checks the new profile's ones.
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	m.syncs = make(map[string]SyncStatus)
+	m.gitStatuses = make(map[string]worktree.GitStatus)
+	m.fetched = make(map[string]time.Time)
+	running := m.running
+	m.mu.Unlock()
+	if running {
+		go func() {
+			m.refreshAllGit()
+			m.syncAll()
+		}()
+		select {
+		case m.rewatch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Shutdown stops the background checks.
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running {
+		close(m.stopCh)
+		m.running = false
+	}
+}
+
+// worktreeSessions returns the live sessions that have a worktree on disk.
+func (m *Manager) worktreeSessions() []session.SessionState {
+	var result []session.SessionState
+	for _, s := range m.sessionManager.ListSessions() {
+		if s.Archived || s.WorktreePath == "" || s.RepoPath == "" {
+			continue
+		}
+		if _, err := os.Stat(s.WorktreePath); err != nil {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// prune forgets sessions that no longer exist.
+func (m *Manager) prune(live []session.SessionState) {
+	ids := make(map[string]bool, len(live))
+	for _, s := range live {
+		ids[s.ID] = true
+	}
+	m.mu.Lock()
+	for id := range m.syncs {
+		if !ids[id] {
+			delete(m.syncs, id)
+		}
+	}
+	m.mu.Unlock()
+}
+
+func (m *Manager) emit(event string, data interface{}) {
+	if m.ctx != nil {
+		runtime.EventsEmit(m.ctx, event, data)
+	}
+}
@@ -0,0 +1,193 @@
+package repostatus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// SyncStatus is how a session branch compares to the base it was created from.
+type SyncStatus struct {
+	SessionID string              `json:"sessionId"`
+	Branch    string              `json:"branch"`
+	Base      string              `json:"base"`   // ref compared against, e.g. origin/main
+	Ahead     int                 `json:"ahead"`  // session commits not on base
+	Behind    int                 `json:"behind"` // base commits not on the session branch
+	Conflicts []worktree.Conflict `json:"conflicts"`
+	CheckedAt time.Time           `json:"checkedAt"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// GetSyncStatuses returns the last computed sync status of every session
+// with a worktree.
+func (m *Manager) GetSyncStatuses() []SyncStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]SyncStatus, 0, len(m.syncs))
+	for _, s := range m.syncs {
+		result = append(result, s)
+	}
+	return result
+}
+
+// RefreshSyncStatus fetches the session's repository and recomputes its
+// sync status immediately.
+func (m *Manager) RefreshSyncStatus(id string) (SyncStatus, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return SyncStatus{}, err
+	}
+	if s.WorktreePath == "" {
+		return SyncStatus{}, fmt.Errorf("session %s has no worktree", id)
+	}
+	fetchedAt := m.fetch(s.RepoPath, true)
+	return m.syncSession(s, fetchedAt), nil
+}
+
+// RebaseSessionWorktree rebases the session branch onto the latest base. It
+// refuses while the agent is working or waiting for an answer, and aborts
+// leaving the branch as it was if the rebase conflicts.
+func (m *Manager) RebaseSessionWorktree(id string) (SyncStatus, error) {
+	return m.updateSession(id, worktree.StrategyRebase)
+}
+
+// UpdateFromBase merges the latest base into the session branch. It refuses
+// while the agent is working or waiting for an answer, and aborts leaving
+// the branch as it was if the merge conflicts.
+func (m *Manager) UpdateFromBase(id string) (SyncStatus, error) {
+	return m.updateSession(id, worktree.StrategyMerge)
+}
+
+func (m *Manager) updateSession(id, strategy string) (SyncStatus, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return SyncStatus{}, err
+	}
+	if s.WorktreePath == "" {
+		return SyncStatus{}, fmt.Errorf("session %s has no worktree", id)
+	}
+	switch s.Status {
+	case session.StatusThinking:
+		return SyncStatus{}, fmt.Errorf("the agent is working; wait until it's idle")
+	case session.StatusWaiting:
+		// It may be asking to run a command or edit a file in this worktree.
+		return SyncStatus{}, fmt.Errorf("the agent is waiting for an answer; reply to it first")
+	}
+	if !m.worktreeManager.IsClean(s.WorktreePath) {
+		return SyncStatus{}, fmt.Errorf("%s has uncommitted changes; commit or discard them first", s.Branch)
+	}
+
+	fetchedAt := m.fetch(s.RepoPath, true)
+	base := worktree.ResolveBase(s.WorktreePath, s.Branch)
+	if base == "" {
+		return SyncStatus{}, fmt.Errorf("no base branch found for %s", s.Branch)
+	}
+	ref := worktree.SyncRef(s.WorktreePath, base)
+
+	if strategy == worktree.StrategyRebase {
+		err = m.worktreeManager.StartRebase(s.WorktreePath, ref)
+	} else {
+		err = m.worktreeManager.StartMerge(s.WorktreePath, ref)
+	}
+	if err != nil {
+		// Only a stopped merge or rebase means conflicts; anything else
+		// failed before git changed the branch.
+		switch m.worktreeManager.InProgress(s.WorktreePath) {
+		case worktree.OpRebase:
+			_ = m.worktreeManager.AbortRebase(s.WorktreePath)
+			return m.syncSession(s, fetchedAt), fmt.Errorf("rebase onto %s conflicts; nothing was changed", ref)
+		case worktree.OpMerge:
+			_ = m.worktreeManager.AbortMerge(s.WorktreePath)
+			return m.syncSession(s, fetchedAt), fmt.Errorf("merging %s conflicts; nothing was changed", ref)
+		}
+		return m.syncSession(s, fetchedAt), err
+	}
+	_, _ = m.refreshGit(s)
+	return m.syncSession(s, fetchedAt), nil
+}
+
+// syncAll refreshes the sync status of every worktree session, fetching
+// repositories that haven't been fetched recently.
+func (m *Manager) syncAll() {
+	sessions := m.worktreeSessions()
+	m.prune(sessions)
+	fetched := make(map[string]time.Time)
+	for _, s := range sessions {
+		if _, ok := fetched[s.RepoPath]; !ok {
+			fetched[s.RepoPath] = m.fetch(s.RepoPath, false)
+		}
+		m.syncSession(s, fetched[s.RepoPath])
+	}
+}
+
+// fetch fetches repoPath if it's due (or force) and returns when it was last
+// fetched. Failures such as being offline leave the last fetch time as is.
+func (m *Manager) fetch(repoPath string, force bool) time.Time {
+	m.mu.RLock()
+	last := m.fetched[repoPath]
+	m.mu.RUnlock()
+	if !force && time.Since(last) < fetchInterval {
+		return last
+	}
+	if err := m.worktreeManager.Fetch(repoPath); err != nil {
+		return last
+	}
+	now := time.Now()
+	m.mu.Lock()
+	m.fetched[repoPath] = now
+	m.mu.Unlock()
+	return now
+}
+
+// syncSession computes, stores and emits the sync status of s.
+func (m *Manager) syncSession(s session.SessionState, fetchedAt time.Time) SyncStatus {
+	st := SyncStatus{SessionID: s.ID, Branch: s.Branch, CheckedAt: time.Now(), FetchedAt: fetchedAt}
+	if err := m.computeSync(s, &st); err != nil {
+		st.Error = err.Error()
+	}
+
+	m.mu.Lock()
+	prev, existed := m.syncs[s.ID]
+	m.syncs[s.ID] = st
+	m.mu.Unlock()
+	if !existed || syncChanged(prev, st) {
+		m.emit("session:sync:"+s.ID, st)
+	}
+	return st
+}
+
+func (m *Manager) computeSync(s session.SessionState, st *SyncStatus) error {
+	base := worktree.ResolveBase(s.WorktreePath, s.Branch)
+	if base == "" {
+		return fmt.Errorf("no base branch found")
+	}
+	st.Base = worktree.SyncRef(s.WorktreePath, base)
+	var err error
+	if st.Ahead, st.Behind, err = m.worktreeManager.AheadBehind(s.WorktreePath, "HEAD", st.Base); err != nil {
+		return err
+	}
+	if st.Behind == 0 || st.Ahead == 0 {
+		return nil // nothing to merge, or a fast-forward
+	}
+	check, err := m.worktreeManager.CheckMerge(s.WorktreePath, "HEAD", st.Base)
+	if err != nil {
+		return err
+	}
+	st.Conflicts = check.Conflicts
+	return nil
+}
+
+func syncChanged(a, b SyncStatus) bool {
+	if a.Base != b.Base || a.Ahead != b.Ahead || a.Behind != b.Behind || a.Error != b.Error || len(a.Conflicts) != len(b.Conflicts) {
+		return true
+	}
+	for i := range a.Conflicts {
+		if a.Conflicts[i] != b.Conflicts[i] {
+			return true
+		}
+	}
+	return a.Branch != b.Branch
+}
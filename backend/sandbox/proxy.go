@@ -0,0 +1,144 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Proxy is an HTTP/HTTPS forward proxy that only connects to allowed hosts.
+// It listens on a unix socket bound into the sandbox, whose own network
+// namespace has no other route out; the relay inside hands it to agents
+// through the standard proxy environment variables.
+type Proxy struct {
+	allowed  []string
+	onDeny   func(host string)
+	listener net.Listener
+}
+
+// NewProxy creates a proxy allowing hosts; onDeny is called for each blocked request.
+func NewProxy(hosts []string, onDeny func(host string)) *Proxy {
+	allowed := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+	return &Proxy{allowed: allowed, onDeny: onDeny}
+}
+
+// Start listens on the unix socket at path.
+func (p *Proxy) Start(path string) error {
+	_ = os.Remove(path)
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("start sandbox proxy: %w", err)
+	}
+	p.listener = l
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go p.handle(conn)
+		}
+	}()
+	return nil
+}
+
+// Env returns the environment variables that route traffic through the proxy.
+func Env(proxyURL string) []string {
+	return []string{
+		"HTTP_PROXY=" + proxyURL, "HTTPS_PROXY=" + proxyURL, "ALL_PROXY=" + proxyURL,
+		"http_proxy=" + proxyURL, "https_proxy=" + proxyURL, "all_proxy=" + proxyURL,
+		"NO_PROXY=", "no_proxy=",
+	}
+}
+
+// Close stops accepting connections and removes the socket.
+func (p *Proxy) Close() {
+	if p.listener != nil {
+		_ = p.listener.Close()
+	}
+}
+
+// Allows reports whether host (without port) is on the allowlist.
+func (p *Proxy) Allows(host string) bool {
+	host = strings.ToLower(host)
+	for _, a := range p.allowed {
+		switch {
+		case a == host:
+			return true
+		case strings.HasPrefix(a, "*.") && strings.HasSuffix(host, a[1:]):
+			return true
+		case strings.HasPrefix(a, ".") && strings.HasSuffix(host, a):
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Proxy) handle(client net.Conn) {
+	defer client.Close()
+	_ = client.SetReadDeadline(time.Now().Add(30 * time.Second))
+	br := bufio.NewReader(client)
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		return
+	}
+	_ = client.SetReadDeadline(time.Time{})
+
+	target := req.Host
+	if req.Method != http.MethodConnect {
+		target = req.URL.Host
+	}
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		host, port = target, "80"
+		if req.Method == http.MethodConnect {
+			port = "443"
+		}
+	}
+	if !p.Allows(host) {
+		if p.onDeny != nil {
+			p.onDeny(host)
+		}
+		fmt.Fprintf(client, "HTTP/1.1 403 Forbidden\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), 15*time.Second)
+	if err != nil {
+		fmt.Fprintf(client, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return
+	}
+	defer upstream.Close()
+
+	if req.Method == http.MethodConnect {
+		fmt.Fprintf(client, "HTTP/1.1 200 Connection Established\r\n\r\n")
+	} else {
+		req.RequestURI = ""
+		req.Header.Del("Proxy-Connection")
+		req.Header.Set("Connection", "close")
+		if err := req.Write(upstream); err != nil {
+			return
+		}
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, br)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,81 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// RelayArg is the argv[1] that runs the aim binary as the network relay of
+// an allowlist sandbox: aim sandbox-relay <socket> <command> [args...].
+const RelayArg = "sandbox-relay"
+
+// RunRelay runs inside the sandbox's private network namespace. It serves
+// the proxy socket on a loopback port, runs the agent with the proxy
+// variables pointing there and returns the agent's exit code.
+func RunRelay(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: aim %s <socket> <command> [args...]\n", RelayArg)
+		return 2
+	}
+	socket := args[0]
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aim sandbox: %v\n", err)
+		return 1
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go relay(conn, socket)
+		}
+	}()
+
+	// Ctrl-C and friends from the terminal are meant for the agent.
+	signal.Notify(make(chan os.Signal, 1), syscall.SIGINT, syscall.SIGQUIT)
+
+	cmd := exec.Command(args[1], args[2:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), Env("http://"+l.Addr().String())...)
+	err = cmd.Run()
+	var exit *exec.ExitError
+	switch {
+	case errors.As(err, &exit):
+		if code := exit.ExitCode(); code >= 0 {
+			return code
+		}
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "aim sandbox: %v\n", err)
+		return 127
+	}
+	return 0
+}
+
+// relay copies one loopback connection to and from the proxy socket.
+func relay(client net.Conn, socket string) {
+	defer client.Close()
+	upstream, err := net.Dial("unix", socket)
+	if err != nil {
+		return
+	}
+	defer upstream.Close()
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(upstream, client)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
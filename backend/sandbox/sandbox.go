@@ -0,0 +1,179 @@
+// Package sandbox confines agent processes with Linux namespaces via
+// bubblewrap (bwrap). The whole filesystem is mounted read-only except the
+// session's worktree, the agent's own state directories and any configured
+// cache directories; networking can be left alone, cut off entirely, or
+// limited to an allowlisting proxy.
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+)
+
+// Network modes.
+const (
+	NetworkFull      = "full"      // no network restrictions
+	NetworkNone      = "none"      // private network namespace with no connectivity
+	NetworkAllowlist = "allowlist" // private network namespace; only HTTP(S) via a proxy that allows AllowedHosts
+)
+
+// Policy is a workspace's sandbox configuration.
+type Policy struct {
+	Enabled       bool     `json:"enabled"`
+	WritablePaths []string `json:"writablePaths"` // extra read-write paths; "~" expands to home
+	CacheDirs     []string `json:"cacheDirs"`     // read-write cache dirs, created if missing
+	Network       string   `json:"network"`       // "full" (default), "none", "allowlist"
+	AllowedHosts  []string `json:"allowedHosts"`  // for "allowlist"; "*.example.com" matches subdomains
+}
+
+// Spec is everything needed to wrap one agent launch.
+type Spec struct {
+	WorkDir  string   // session working directory, always writable
+	Writable []string // additional writable paths supplied by aim (git dir, hook socket)
+	ReadOnly []string // paths inside writable ones that stay read-only (git hooks and config)
+	// ProxySocket is the allowlist proxy's unix socket, required for
+	// NetworkAllowlist. It is the sandbox's only way out.
+	ProxySocket string
+	Command     string
+	Args        []string
+}
+
+// DefaultCacheDirs are writable in every sandbox so agents and package
+// managers keep working.
+var DefaultCacheDirs = []string{
+	"~/.claude",
+	"~/.claude.json",
+	"~/.codex",
+	"~/.cache",
+}
+
+// Available reports why sandboxing can't be used on this machine, or nil.
+func Available() error {
+	if goruntime.GOOS != "linux" {
+		return fmt.Errorf("sandboxing requires Linux (running on %s)", goruntime.GOOS)
+	}
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		return fmt.Errorf("sandboxing requires bubblewrap (bwrap) on PATH")
+	}
+	return nil
+}
+
+// Validate checks a policy for obvious mistakes.
+func (p Policy) Validate() error {
+	switch p.Network {
+	case "", NetworkFull, NetworkNone:
+	case NetworkAllowlist:
+		if len(p.AllowedHosts) == 0 {
+			return fmt.Errorf("network allowlist is empty")
+		}
+	default:
+		return fmt.Errorf("unknown network mode %q", p.Network)
+	}
+	return nil
+}
+
+// Wrap returns the bwrap command line that runs spec under p.
+func (p Policy) Wrap(spec Spec) (string, []string, error) {
+	if err := Available(); err != nil {
+		return "", nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return "", nil, err
+	}
+	bwrap, _ := exec.LookPath("bwrap")
+
+	args := []string{
+		"--die-with-parent",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+	}
+	seen := make(map[string]bool)
+	bind := func(path string, create bool) {
+		path = expandHome(path)
+		if path == "" || seen[path] {
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			if !create {
+				return
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return
+			}
+		}
+		seen[path] = true
+		args = append(args, "--bind", path, path)
+	}
+
+	bind(spec.WorkDir, false)
+	for _, path := range spec.Writable {
+		bind(path, false)
+	}
+	for _, path := range DefaultCacheDirs {
+		bind(path, false)
+	}
+	for _, path := range p.CacheDirs {
+		bind(path, true)
+	}
+	for _, path := range p.WritablePaths {
+		bind(path, false)
+	}
+	// Later mounts win, so these stay read-only inside writable parents.
+	for _, path := range spec.ReadOnly {
+		if _, err := os.Stat(path); err == nil {
+			args = append(args, "--ro-bind", path, path)
+		}
+	}
+
+	command := append([]string{spec.Command}, spec.Args...)
+	switch p.Network {
+	case NetworkNone:
+		args = append(args, "--unshare-net")
+	case NetworkAllowlist:
+		// Nothing but the proxy socket leads out of the private network;
+		// aim's relay serves it on loopback inside. The pid namespace takes
+		// the agent down with the relay.
+		if spec.ProxySocket == "" {
+			return "", nil, fmt.Errorf("network allowlist needs the proxy socket")
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return "", nil, fmt.Errorf("locate aim executable: %w", err)
+		}
+		bind(spec.ProxySocket, false)
+		args = append(args, "--unshare-net", "--unshare-pid")
+		command = append([]string{exe, RelayArg, spec.ProxySocket}, command...)
+	}
+	args = append(args, "--chdir", spec.WorkDir, "--")
+	args = append(args, command...)
+	return bwrap, args, nil
+}
+
+// Describe summarises the policy for the session log.
+func (p Policy) Describe(spec Spec) string {
+	network := p.Network
+	if network == "" {
+		network = NetworkFull
+	}
+	if network == NetworkAllowlist {
+		network += " (" + strings.Join(p.AllowedHosts, ", ") + ")"
+	}
+	return fmt.Sprintf("sandbox on: writes limited to %s and cache dirs; network %s", spec.WorkDir, network)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
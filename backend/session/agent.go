@@ -0,0 +1,132 @@
+package session
+
+import (
+	"os"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/transcript"
+	"github.com/google/uuid"
+)
+
+// agentCommand returns the command line that launches the session's agent.
+// When resume is true and the agent's own conversation can be found, the
+// agent is started with its resume flags so it keeps its context.
+func agentCommand(s *Session, resume bool) (string, []string) {
+	switch s.Config.Agent {
+	case "claude":
+		args := append([]string{"--dangerously-skip-permissions"}, addDirArgs(s)...)
+		if resume && s.AgentSessionID != "" && transcript.ClaudeTranscriptPath(s.WorkDir, s.AgentSessionID) != "" {
+			return "claude", append(args, "--resume", s.AgentSessionID)
+		}
+		// Claude only writes a transcript once the first prompt is sent, so a
+		// session with no transcript yet starts fresh under the same ID.
+		args = append(args, "--session-id", s.AgentSessionID)
+		if !resume && s.Config.InitialPrompt != "" {
+			args = append(args, s.Config.InitialPrompt)
+		}
+		return "claude", args
+	case "codex":
+		dirs := addDirArgs(s)
+		if resume && s.AgentSessionID != "" {
+			return "codex", append(append([]string{"resume"}, dirs...), s.AgentSessionID)
+		}
+		if !resume && s.Config.InitialPrompt != "" {
+			return "codex", append(dirs, s.Config.InitialPrompt)
+		}
+		return "codex", dirs
+	default:
+		// shell
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/zsh"
+		}
+		return shell, nil
+	}
+}
+
+// prepareAgentSession makes sure s has the agent conversation ID needed by
+// agentCommand. It reads transcripts from disk, so s should be a copy taken
+// under m.mu rather than the live session.
+func prepareAgentSession(s *Session, resume bool) {
+	switch s.Config.Agent {
+	case "claude":
+		if s.AgentSessionID == "" && resume {
+			// Sessions created before aim assigned IDs: adopt the newest transcript.
+			if id, err := transcript.LatestClaudeSession(s.WorkDir); err == nil {
+				s.AgentSessionID = id
+			}
+		}
+		if s.AgentSessionID == "" {
+			s.AgentSessionID = uuid.New().String()
+		}
+	case "codex":
+		// Codex picks its own ID, so a session that never captured one can
+		// only adopt the newest rollout for its working directory, and only
+		// one started after the session was created.
+		if resume && s.AgentSessionID == "" {
+			var since time.Time
+			if s.CreatedAt != nil {
+				since = *s.CreatedAt
+			}
+			if id, _, err := transcript.LatestCodexSession(s.WorkDir, since); err == nil {
+				s.AgentSessionID = id
+			}
+		}
+	}
+}
+
+// captureAgentSession records the Codex conversation ID once the agent has
+// exited, so the next resume can find it. Only a session without an ID
+// yet is updated, from a rollout started since the agent was launched.
+func (m *Manager) captureAgentSession(id string, launched time.Time) {
+	m.mu.RLock()
+	s, ok := m.sessions[id]
+	var agent, workDir, agentID string
+	if ok {
+		agent, workDir, agentID = s.Config.Agent, s.WorkDir, s.AgentSessionID
+	}
+	m.mu.RUnlock()
+	if agent != "codex" || agentID != "" {
+		return
+	}
+	agentID, _, err := transcript.LatestCodexSession(workDir, launched)
+	if err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	changed := s.AgentSessionID == ""
+	if changed {
+		s.AgentSessionID = agentID
+	}
+	m.mu.Unlock()
+	if changed {
+		m.persist()
+	}
+}
+
+// TranscriptSource returns where s's agent writes its transcripts.
+func (s SessionState) TranscriptSource() transcript.Source {
+	workDir := s.Directory
+	if s.WorktreePath != "" {
+		workDir = s.WorktreePath
+	}
+	return transcript.Source{
+		Agent:          s.Agent,
+		WorkDir:        workDir,
+		AgentSessionID: s.AgentSessionID,
+		Running:        s.Status != StatusStopped && s.Status != StatusErrored,
+	}
+}
+
+// TranscriptSources returns the transcript sources of the sessions whose
+// agents write transcripts, keyed by session ID.
+func TranscriptSources(sessions []SessionState) map[string]transcript.Source {
+	sources := make(map[string]transcript.Source, len(sessions))
+	for _, s := range sessions {
+		if s.Agent == "claude" || s.Agent == "codex" {
+			sources[s.ID] = s.TranscriptSource()
+		}
+	}
+	return sources
+}
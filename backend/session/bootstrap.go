@@ -0,0 +1,220 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	setupCommandTimeout = 30 * time.Minute
+	setupWaitDelay      = 5 * time.Second // for output pipes after a setup command is killed
+	maxSetupLog         = 64 * 1024       // tail of the setup output kept on a failed session
+)
+
+// SetupEvent is emitted on session:setup:<id> while a worktree is bootstrapped.
+type SetupEvent struct {
+	Output string `json:"output,omitempty"` // a chunk of setup output
+	Done   bool   `json:"done"`
+	Error  string `json:"error,omitempty"`
+}
+
+// setupRun is a worktree setup in progress.
+type setupRun struct {
+	cancel context.CancelFunc
+	done   chan struct{} // closed once the setup goroutine has returned
+}
+
+// setupStep prepares one of a session's worktrees.
+type setupStep struct {
+	repoPath string // where files are copied and linked from
+	dir      string
+	b        worktree.Bootstrap
+}
+
+// setupSteps returns the setup for s's worktree and for those of its linked
+// repositories. Each worktree is prepared by its own repository's setup.
+func (m *Manager) setupSteps(s *Session) []setupStep {
+	opts := m.resolveLaunchOptions(s.Config.WorkspaceID)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var steps []setupStep
+	add := func(repoPath, dir string) {
+		if b := opts.bootstrapFor(repoPath); dir != "" && b != nil && !b.Empty() {
+			steps = append(steps, setupStep{repoPath: repoPath, dir: dir, b: *b})
+		}
+	}
+	repoPath := s.Config.RepoPath
+	if repoPath == "" {
+		repoPath = s.Config.Directory
+	}
+	add(repoPath, s.WorkDir)
+	for _, l := range s.Config.LinkedRepos {
+		add(l.RepoPath, l.WorktreePath)
+	}
+	return steps
+}
+
+// startWithBootstrap prepares s's worktrees in the background, then starts
+// the agent. A failed setup leaves the session errored with the log on
+// SetupLog; resuming it runs the setup again.
+func (m *Manager) startWithBootstrap(s *Session, steps []setupStep) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &setupRun{cancel: cancel, done: make(chan struct{})}
+	m.mu.Lock()
+	m.setups[s.ID] = run
+	s.SetupLog = ""
+	m.mu.Unlock()
+	m.updateStatus(s.ID, StatusStarting)
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			delete(m.setups, s.ID)
+			m.mu.Unlock()
+			cancel()
+			close(run.done)
+		}()
+
+		log := &setupLog{m: m, id: s.ID}
+		err := m.bootstrap(ctx, s, steps, log)
+		if ctx.Err() != nil {
+			return // session closed or archived mid-setup
+		}
+		if err == nil {
+			var ps *ptySession
+			if ps, err = spawnPTY(s, m, false); err == nil {
+				m.mu.Lock()
+				if ctx.Err() != nil {
+					// Closed while the agent was starting.
+					m.mu.Unlock()
+					_ = ps.kill()
+					return
+				}
+				m.ptySessions[s.ID] = ps
+				m.mu.Unlock()
+				m.emitSetup(s.ID, SetupEvent{Done: true})
+				m.updateStatus(s.ID, StatusIdle)
+				m.persist()
+				return
+			}
+			err = fmt.Errorf("start agent: %w", err)
+		}
+
+		fmt.Fprintf(log, "\nsetup failed: %v\n", err)
+		m.mu.Lock()
+		s.SetupLog = log.String()
+		m.mu.Unlock()
+		m.emitSetup(s.ID, SetupEvent{Done: true, Error: err.Error()})
+		m.updateStatus(s.ID, StatusErrored)
+		m.persist()
+	}()
+}
+
+// bootstrap copies and links the configured files into each worktree, then
+// runs its setup commands in it with the user's shell.
+func (m *Manager) bootstrap(ctx context.Context, s *Session, steps []setupStep, log *setupLog) error {
+	env := m.resolveLaunchOptions(s.Config.WorkspaceID).environ()
+	shell := m.settings.GetSettings().ShellPath
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	for _, step := range steps {
+		fmt.Fprintf(log, "[aim setup] preparing %s\n", step.dir)
+		if err := step.b.LinkFiles(step.repoPath, step.dir, log); err != nil {
+			return err
+		}
+		for _, command := range step.b.Commands {
+			fmt.Fprintf(log, "[aim setup] $ %s\n", command)
+			cmdCtx, cancel := context.WithTimeout(ctx, setupCommandTimeout)
+			cmd := setupCommand(cmdCtx, shell, command, step.dir, log)
+			cmd.Env = append(append(os.Environ(), env...), fmt.Sprintf("AIM_SESSION_ID=%s", s.ID))
+			err := cmd.Run()
+			timedOut := cmdCtx.Err() == context.DeadlineExceeded
+			cancel()
+			if timedOut {
+				return fmt.Errorf("%s: timed out after %s", command, setupCommandTimeout)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", command, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setupCommand runs command with shell in dir, writing its output to out.
+// Cancelling ctx kills everything the command started, not just the shell:
+// its children hold the output pipe open and would otherwise keep running,
+// and writing into a worktree that may be about to be removed.
+func setupCommand(ctx context.Context, shell, command, dir string, out io.Writer) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, shell, "-lc", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = setupWaitDelay
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd
+}
+
+// cancelSetup stops a bootstrap in progress for id, if any, and waits for
+// it to return so the caller can safely remove the worktree.
+func (m *Manager) cancelSetup(id string) {
+	m.mu.Lock()
+	run, ok := m.setups[id]
+	m.mu.Unlock()
+	if ok {
+		run.cancel()
+		<-run.done
+	}
+}
+
+// emitSetup sends a setup event to the frontend.
+func (m *Manager) emitSetup(id string, ev SetupEvent) {
+	runtime.EventsEmit(m.ctx, fmt.Sprintf("session:setup:%s", id), ev)
+}
+
+// setupLog streams setup output to the session's terminal and setup events,
+// keeping its tail in case the setup fails.
+type setupLog struct {
+	m   *Manager
+	id  string
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *setupLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	l.buf.Write(p)
+	if l.buf.Len() > maxSetupLog {
+		l.buf.Next(l.buf.Len() - maxSetupLog)
+	}
+	l.mu.Unlock()
+
+	// The terminal expects CRLF line endings.
+	chunk := []byte(strings.ReplaceAll(strings.ReplaceAll(string(p), "\r\n", "\n"), "\n", "\r\n"))
+	_ = l.m.persister.appendScrollback(l.id, chunk)
+	runtime.EventsEmit(l.m.ctx, fmt.Sprintf("session:data:%s", l.id), base64.StdEncoding.EncodeToString(chunk))
+	l.m.emitSetup(l.id, SetupEvent{Output: string(p)})
+	return len(p), nil
+}
+
+func (l *setupLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
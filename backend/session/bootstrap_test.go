@@ -0,0 +1,48 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a writer that isn't an *os.File, so exec copies from a pipe.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestSetupCommandCancelKillsChildren(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "late")
+	ctx, cancel := context.WithCancel(context.Background())
+	// The background child keeps the output pipe open and writes late.
+	cmd := setupCommand(ctx, "/bin/sh", "(sleep 2; touch late) & echo started; wait", dir, &syncBuffer{})
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(setupWaitDelay):
+		t.Fatal("Wait blocked on the command's children after cancel")
+	}
+	time.Sleep(3 * time.Second)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("child of the cancelled command kept running")
+	}
+}
@@ -0,0 +1,245 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/google/uuid"
+)
+
+// TempBranchPrefix marks a branch created before the session had a prompt.
+// Such branches are renamed after the first prompt.
+const TempBranchPrefix = "aim/tmp-"
+
+const (
+	agentNameTimeout = 30 * time.Second
+	maxNameAttempts  = 20
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9\s]`)
+
+// needsBranchName reports whether s still has its temporary branch. Caller
+// must hold m.mu.
+func needsBranchName(s *Session) bool {
+	return s.Config.WorktreePath != "" && strings.HasPrefix(s.Config.Branch, TempBranchPrefix)
+}
+
+// nameBranchFromPrompt renames id's temporary branch after prompt, trying
+// numbered variants if the name is taken. It does nothing if the branch was
+// already named.
+func (m *Manager) nameBranchFromPrompt(id string, prompt string) {
+	prompt = strings.TrimSpace(prompt)
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if !ok || prompt == "" || !needsBranchName(s) || m.naming[id] {
+		m.mu.Unlock()
+		return
+	}
+	m.naming[id] = true
+	agent, issue, dirs, workspaceID := s.Config.Agent, s.Config.IssueIdentifier, sessionWorktrees(s), s.Config.WorkspaceID
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		delete(m.naming, id)
+		m.mu.Unlock()
+	}()
+
+	name := branchName(m.namingPolicy(workspaceID), issue, prompt, agent)
+	for i := 1; i <= maxNameAttempts; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", name, i)
+		}
+		if branchExistsIn(dirs, candidate) {
+			continue
+		}
+		if err := m.RenameSessionBranch(id, candidate); err != nil && branchExistsIn(dirs, candidate) {
+			continue // taken between the check and the rename
+		}
+		return
+	}
+}
+
+// SuggestBranchName returns the branch name the naming policy gives prompt,
+// for work that needs its branch named before the agent starts.
+func (m *Manager) SuggestBranchName(workspaceID string, prompt string, issueIdentifier string, agent string) string {
+	return branchName(m.namingPolicy(workspaceID), issueIdentifier, prompt, agent)
+}
+
+// UntitledBranchName names a branch that has no prompt to be named after
+// and won't be renamed later: the naming policy's prefix and issue key
+// followed by a random suffix.
+func (m *Manager) UntitledBranchName(workspaceID string, issueIdentifier string) string {
+	policy := m.namingPolicy(workspaceID)
+	policy.UseAgent = false
+	return branchName(policy, issueIdentifier, "task", "") + "-" + uuid.New().String()[:6]
+}
+
+// namingPolicy returns the branch naming settings, with the workspace's
+// prefix if it sets one.
+func (m *Manager) namingPolicy(workspaceID string) settings.BranchNaming {
+	policy := m.settings.GetSettings().BranchNaming
+	if prefix := m.resolveLaunchOptions(workspaceID).BranchPrefix; prefix != "" {
+		policy.Prefix = prefix
+	}
+	return policy
+}
+
+// branchName builds a branch name for prompt following policy.
+func branchName(policy settings.BranchNaming, issue, prompt, agent string) string {
+	if policy.MaxWords <= 0 {
+		policy.MaxWords = settings.DefaultBranchNaming.MaxWords
+	}
+	if policy.MaxLength <= 0 {
+		policy.MaxLength = settings.DefaultBranchNaming.MaxLength
+	}
+
+	summary := prompt
+	if policy.UseAgent {
+		if s := agentSummary(agent, prompt, policy.MaxWords); s != "" {
+			summary = s
+		}
+	}
+	slug := slugify(summary, policy.MaxWords, policy.MaxLength)
+	if slug == "" {
+		slug = "session"
+	}
+	if policy.IncludeIssue && issue != "" {
+		slug = slugify(strings.ReplaceAll(issue, "-", " "), 3, 20) + "-" + slug
+	}
+	name := policy.Prefix + slug
+	if worktree.ValidateBranchName(name) != nil {
+		return settings.DefaultBranchNaming.Prefix + slug
+	}
+	return name
+}
+
+// slugify keeps the first maxWords words of text, lowercased and joined
+// with dashes, cut to maxLength.
+func slugify(text string, maxWords, maxLength int) string {
+	words := strings.Fields(nonSlugChars.ReplaceAllString(strings.ToLower(text), ""))
+	if len(words) > maxWords {
+		words = words[:maxWords]
+	}
+	slug := strings.Join(words, "-")
+	if len(slug) > maxLength {
+		slug = slug[:maxLength]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// agentSummary asks the agent CLI for a short branch name describing prompt.
+// Only Claude can answer non-interactively; others return "".
+func agentSummary(agent, prompt string, maxWords int) string {
+	if agent != "claude" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), agentNameTimeout)
+	defer cancel()
+	instruction := fmt.Sprintf("Reply with only a git branch name of at most %d lowercase words separated by dashes that summarizes this task. No prefix, no explanation.\n\nTask:\n%s", maxWords, prompt)
+	out, err := exec.CommandContext(ctx, "claude", "-p", instruction).Output()
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.ReplaceAll(lines[len(lines)-1], "-", " ")
+}
+
+// Bracketed paste markers terminals put around pasted text.
+const (
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// capturePrompt collects keystrokes sent to a session until the first Enter,
+// for agents that don't report prompts through hooks.
+func (m *Manager) capturePrompt(ps *ptySession, id string, data string) {
+	if submitted := ps.feedPrompt(data); submitted != "" {
+		go m.nameBranchFromPrompt(id, submitted)
+	}
+}
+
+// feedPrompt adds keystrokes to the prompt being typed and returns it once
+// Enter submits it. Escape sequences such as arrow keys are skipped; pasted
+// text is kept, newlines and all.
+func (ps *ptySession) feedPrompt(data string) string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var submitted string
+	for i := 0; i < len(data) && submitted == ""; {
+		if data[i] == '\x1b' {
+			n := escapeLen(data[i:])
+			switch data[i : i+n] {
+			case pasteStart:
+				ps.pasting = true
+			case pasteEnd:
+				ps.pasting = false
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(data[i:])
+		i += size
+		switch {
+		case (r == '\r' || r == '\n') && ps.pasting:
+			ps.prompt = append(ps.prompt, '\n')
+		case r == '\r' || r == '\n':
+			if strings.TrimSpace(string(ps.prompt)) != "" {
+				submitted = string(ps.prompt)
+			}
+			ps.prompt = nil
+		case r == '\x7f':
+			if len(ps.prompt) > 0 {
+				ps.prompt = ps.prompt[:len(ps.prompt)-1]
+			}
+		case r >= 32:
+			ps.prompt = append(ps.prompt, r)
+		}
+	}
+	return submitted
+}
+
+// escapeLen returns the length of the escape sequence at the start of s:
+// a CSI sequence such as an arrow key or paste marker, an SS3 function key,
+// or Alt with a key.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		// Parameters and intermediates, up to a final byte in 0x40-0x7e.
+		for j := 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j + 1
+			}
+		}
+		return len(s)
+	case 'O':
+		return min(3, len(s))
+	default:
+		_, size := utf8.DecodeRuneInString(s[1:])
+		return 1 + size
+	}
+}
+
+func branchExists(dir, branch string) bool {
+	return exec.Command("git", "-C", dir, "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
+
+// branchExistsIn reports whether branch exists in any of the repositories of dirs.
+func branchExistsIn(dirs []string, branch string) bool {
+	for _, dir := range dirs {
+		if branchExists(dir, branch) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package session
+
+import "testing"
+
+func TestFeedPrompt(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{"typed", []string{"fix ", "the bug\r"}, "fix the bug"},
+		{"backspace", []string{"fixx\x7f it\r"}, "fix it"},
+		{"arrow keys", []string{"fix", "\x1b[D", "\x1b[C", "\x1bOA", " it\r"}, "fix it"},
+		{"escape mid-chunk", []string{"fix\x1b[1;5D it\r"}, "fix it"},
+		{"paste", []string{"\x1b[200~fix the bug\x1b[201~\r"}, "fix the bug"},
+		{"multi-line paste", []string{"\x1b[200~first\rsecond", "\nthird\x1b[201~", "\r"}, "first\nsecond\nthird"},
+		{"empty enter", []string{"\r", "go\r"}, "go"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &ptySession{}
+			var got string
+			for _, c := range tt.chunks {
+				if s := ps.feedPrompt(c); s != "" {
+					got = s
+				}
+			}
+			if got != tt.want {
+				t.Errorf("submitted %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
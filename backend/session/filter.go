@@ -0,0 +1,233 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/storage"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// SessionFilter selects sessions by metadata. Empty fields match everything.
+type SessionFilter struct {
+	Tags          []string   `json:"tags"`          // session must carry every tag
+	Agents        []string   `json:"agents"`        // any of
+	Statuses      []string   `json:"statuses"`      // any of
+	WorkspaceIDs  []string   `json:"workspaceIds"`  // any of
+	Branch        string     `json:"branch"`        // case-insensitive substring
+	Archived      *bool      `json:"archived"`      // nil = archived and active
+	CreatedAfter  *time.Time `json:"createdAfter"`  // inclusive
+	CreatedBefore *time.Time `json:"createdBefore"` // exclusive
+}
+
+// SessionView is a named, saved SessionFilter shown in the sidebar and archive panel.
+type SessionView struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Filter SessionFilter `json:"filter"`
+}
+
+// Matches reports whether s satisfies every criterion in f.
+func (f SessionFilter) Matches(s SessionState) bool {
+	for _, want := range f.Tags {
+		if !containsFold(s.Tags, want) {
+			return false
+		}
+	}
+	if len(f.Agents) > 0 && !containsFold(f.Agents, s.Agent) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsFold(f.Statuses, s.Status) {
+		return false
+	}
+	if len(f.WorkspaceIDs) > 0 && !contains(f.WorkspaceIDs, s.WorkspaceID) {
+		return false
+	}
+	if f.Branch != "" && !strings.Contains(strings.ToLower(s.Branch), strings.ToLower(f.Branch)) {
+		return false
+	}
+	if f.Archived != nil && *f.Archived != s.Archived {
+		return false
+	}
+	if f.CreatedAfter != nil || f.CreatedBefore != nil {
+		// Sessions persisted before creation times were recorded can't be placed in a range.
+		if s.CreatedAt == nil {
+			return false
+		}
+		if f.CreatedAfter != nil && s.CreatedAt.Before(*f.CreatedAfter) {
+			return false
+		}
+		if f.CreatedBefore != nil && !s.CreatedAt.Before(*f.CreatedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// QuerySessions returns the sessions matching filter, newest first.
+func (m *Manager) QuerySessions(filter SessionFilter) []SessionState {
+	var result []SessionState
+	for _, s := range m.ListSessions() {
+		if filter.Matches(s) {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].CreatedAt, result[j].CreatedAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+	return result
+}
+
+// SetSessionTags replaces the tags on a session. Tags are trimmed and de-duplicated.
+func (m *Manager) SetSessionTags(id string, tags []string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if ok {
+		s.Tags = normalizeTags(tags)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	m.persist()
+	return nil
+}
+
+// SetSessionNotes replaces the markdown note on a session.
+func (m *Manager) SetSessionNotes(id string, notes string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if ok {
+		s.Notes = notes
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	m.persist()
+	return nil
+}
+
+// ListSessionTags returns every tag in use across all sessions, sorted.
+func (m *Manager) ListSessionTags() []string {
+	m.mu.RLock()
+	var all []string
+	for _, s := range m.sessions {
+		all = append(all, s.Tags...)
+	}
+	m.mu.RUnlock()
+	return normalizeTags(all)
+}
+
+// ListSessionViews returns all saved views sorted by name.
+func (m *Manager) ListSessionViews() []SessionView {
+	m.mu.RLock()
+	views := make([]SessionView, 0, len(m.views))
+	for _, v := range m.views {
+		views = append(views, *v)
+	}
+	m.mu.RUnlock()
+	sort.Slice(views, func(i, j int) bool {
+		return strings.ToLower(views[i].Name) < strings.ToLower(views[j].Name)
+	})
+	return views
+}
+
+// SaveSessionView creates a view (empty ID) or updates an existing one. Returns the view ID.
+func (m *Manager) SaveSessionView(view SessionView) (string, error) {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" {
+		return "", fmt.Errorf("view name is required")
+	}
+	view.Filter.Tags = normalizeTags(view.Filter.Tags)
+
+	m.mu.Lock()
+	if view.ID == "" {
+		view.ID = uuid.New().String()
+	} else if _, ok := m.views[view.ID]; !ok {
+		m.mu.Unlock()
+		return "", fmt.Errorf("view %s not found", view.ID)
+	}
+	m.views[view.ID] = &view
+	m.mu.Unlock()
+
+	if err := m.persistViews(); err != nil {
+		return "", fmt.Errorf("save views: %w", err)
+	}
+	return view.ID, nil
+}
+
+// DeleteSessionView removes a saved view.
+func (m *Manager) DeleteSessionView(id string) error {
+	m.mu.Lock()
+	_, ok := m.views[id]
+	delete(m.views, id)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("view %s not found", id)
+	}
+	return m.persistViews()
+}
+
+func (m *Manager) loadPersistedViews() {
+	views, rec, err := m.persister.loadViews()
+	if rec != nil {
+		runtime.EventsEmit(m.ctx, storage.RecoveredEvent, rec)
+	}
+	if err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range views {
+		v := views[i]
+		m.views[v.ID] = &v
+	}
+}
+
+func (m *Manager) persistViews() error {
+	return m.persister.saveViews(m.ListSessionViews())
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var result []string
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		key := strings.ToLower(t)
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i]) < strings.ToLower(result[j])
+	})
+	return result
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
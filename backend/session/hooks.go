@@ -0,0 +1,129 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Benbentwo/aim/backend/hooks"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// startHookServer starts listening for Claude hook callbacks. If it can't,
+// sessions fall back to inferring status from PTY output.
+func (m *Manager) startHookServer() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	srv := hooks.NewServer(hooks.RuntimeDir(m.persister.locator.Root()), m.handleHookEvent)
+	if err := srv.Start(); err != nil {
+		runtime.LogWarningf(m.ctx, "agent hooks disabled: %v", err)
+		return
+	}
+	m.mu.Lock()
+	m.hookServer = srv
+	m.hookCommand = hooks.ClientCommand(exe)
+	m.mu.Unlock()
+}
+
+// hookLaunch returns the extra agent arguments and environment that make the
+// agent report hook events for s. Only Claude supports hooks.
+func (m *Manager) hookLaunch(s *Session) ([]string, []string) {
+	m.mu.RLock()
+	srv, command := m.hookServer, m.hookCommand
+	m.mu.RUnlock()
+	if srv == nil || s.Config.Agent != "claude" {
+		return nil, nil
+	}
+
+	data, err := hooks.ClaudeSettings(command)
+	if err != nil {
+		return nil, nil
+	}
+	path := filepath.Join(m.persister.sessionDir(s.ID), "claude-hooks.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, nil
+	}
+	return []string{"--settings", path}, []string{fmt.Sprintf("%s=%s", hooks.SocketEnv, srv.SocketPath())}
+}
+
+// hookSocket returns the hook server's socket path, or "" if hooks are disabled.
+func (m *Manager) hookSocket() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.hookServer == nil {
+		return ""
+	}
+	return m.hookServer.SocketPath()
+}
+
+// handleHookEvent applies an authoritative status update reported by an
+// agent hook. Once a session reports hooks, output heuristics are disabled for it.
+func (m *Manager) handleHookEvent(ev hooks.Event) {
+	m.mu.Lock()
+	ps, active := m.ptySessions[ev.SessionID]
+	s, known := m.sessions[ev.SessionID]
+	agentChanged := false
+	if known && ev.AgentID != "" && s.AgentSessionID != ev.AgentID {
+		s.AgentSessionID = ev.AgentID
+		agentChanged = true
+	}
+	m.mu.Unlock()
+	if !active {
+		return
+	}
+	ps.mu.Lock()
+	ps.hooked = true
+	ps.mu.Unlock()
+	if agentChanged {
+		m.persist()
+	}
+
+	switch ev.Name {
+	case hooks.EventUserPromptSubmit:
+		m.setCurrentTool(ev.SessionID, "")
+		m.updateStatus(ev.SessionID, StatusThinking)
+		go m.nameBranchFromPrompt(ev.SessionID, ev.Prompt)
+	case hooks.EventPreToolUse:
+		m.setCurrentTool(ev.SessionID, ev.ToolName)
+		m.updateStatus(ev.SessionID, StatusThinking)
+	case hooks.EventPostToolUse:
+		m.setCurrentTool(ev.SessionID, "")
+		m.updateStatus(ev.SessionID, StatusThinking)
+	case hooks.EventNotification:
+		// Permission prompts and "waiting for your input" both need the user.
+		m.updateStatus(ev.SessionID, StatusWaiting)
+	case hooks.EventStop:
+		m.setCurrentTool(ev.SessionID, "")
+		m.updateStatus(ev.SessionID, StatusIdle)
+	}
+}
+
+// setCurrentTool records the tool the agent is running and emits an event when it changes.
+func (m *Manager) setCurrentTool(id string, tool string) {
+	m.mu.Lock()
+	prev := m.tools[id]
+	if tool == "" {
+		delete(m.tools, id)
+	} else {
+		m.tools[id] = tool
+	}
+	m.mu.Unlock()
+	if prev != tool {
+		runtime.EventsEmit(m.ctx, fmt.Sprintf("session:tool:%s", id), tool)
+	}
+}
+
+func (m *Manager) stopHookServer() {
+	m.mu.Lock()
+	srv := m.hookServer
+	m.hookServer = nil
+	m.mu.Unlock()
+	if srv != nil {
+		srv.Close()
+	}
+}
@@ -0,0 +1,146 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/sandbox"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// LaunchOptions are per-workspace settings applied when a session's agent starts.
+type LaunchOptions struct {
+	Sandbox   *sandbox.Policy
+	Bootstrap *worktree.Bootstrap // prepares new worktrees before the agent starts
+	Env       map[string]string   // added to the environment of the agent and setup commands
+	// BranchPrefix replaces the branch naming prefix from settings.
+	BranchPrefix string
+	// RepoBootstraps replace Bootstrap for worktrees of the repositories
+	// they are keyed by, the further repositories of a multi-repo
+	// workspace. A nil entry means those worktrees need no setup.
+	RepoBootstraps map[string]*worktree.Bootstrap
+}
+
+// bootstrapFor returns the setup for new worktrees of repoPath.
+func (opts LaunchOptions) bootstrapFor(repoPath string) *worktree.Bootstrap {
+	if b, ok := opts.RepoBootstraps[repoPath]; ok {
+		return b
+	}
+	return opts.Bootstrap
+}
+
+// environ returns opts.Env as KEY=value pairs in a stable order.
+func (opts LaunchOptions) environ() []string {
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, k+"="+opts.Env[k])
+	}
+	return env
+}
+
+// LaunchOptionsFunc resolves the launch options for a workspace.
+type LaunchOptionsFunc func(workspaceID string) LaunchOptions
+
+// SetLaunchOptionsFunc installs the resolver used for every agent launch.
+func (m *Manager) SetLaunchOptionsFunc(fn LaunchOptionsFunc) {
+	m.mu.Lock()
+	m.launchOptions = fn
+	m.mu.Unlock()
+}
+
+func (m *Manager) resolveLaunchOptions(workspaceID string) LaunchOptions {
+	m.mu.RLock()
+	fn := m.launchOptions
+	m.mu.RUnlock()
+	if fn == nil {
+		return LaunchOptions{}
+	}
+	return fn(workspaceID)
+}
+
+// sandboxLaunch wraps the agent command in the workspace's sandbox policy.
+// It returns the command to run and a cleanup func.
+func (m *Manager) sandboxLaunch(s *Session, policy *sandbox.Policy, name string, args []string, hookSocket string) (string, []string, func(), error) {
+	noop := func() {}
+	if policy == nil || !policy.Enabled {
+		return name, args, noop, nil
+	}
+
+	spec := sandbox.Spec{
+		WorkDir: s.WorkDir,
+		Command: name,
+		Args:    args,
+	}
+	// A worktree's commits land in the main repository's git dir, but its
+	// hooks and config run code outside the sandbox, so they stay read-only.
+	addGitDir := func(dir string) {
+		if gitDir := commonGitDir(dir); gitDir != "" {
+			spec.Writable = append(spec.Writable, gitDir)
+			spec.ReadOnly = append(spec.ReadOnly, filepath.Join(gitDir, "hooks"), filepath.Join(gitDir, "config"))
+		}
+	}
+	addGitDir(s.WorkDir)
+	for _, l := range s.Config.LinkedRepos {
+		if l.WorktreePath == "" {
+			continue
+		}
+		spec.Writable = append(spec.Writable, l.WorktreePath)
+		addGitDir(l.WorktreePath)
+	}
+	if hookSocket != "" {
+		spec.Writable = append(spec.Writable, hookSocket)
+	}
+
+	cleanup := noop
+	if policy.Network == sandbox.NetworkAllowlist {
+		proxy := sandbox.NewProxy(policy.AllowedHosts, func(host string) {
+			m.sandboxNotice(s.ID, fmt.Sprintf("blocked network access to %s", host))
+		})
+		spec.ProxySocket = filepath.Join(os.TempDir(), fmt.Sprintf("aim-%d-proxy-%s.sock", os.Getpid(), s.ID))
+		if err := proxy.Start(spec.ProxySocket); err != nil {
+			return "", nil, noop, err
+		}
+		cleanup = proxy.Close
+	}
+
+	cmdName, cmdArgs, err := policy.Wrap(spec)
+	if err != nil {
+		cleanup()
+		return "", nil, noop, err
+	}
+	m.sandboxNotice(s.ID, policy.Describe(spec))
+	return cmdName, cmdArgs, cleanup, nil
+}
+
+// sandboxNotice records a sandbox message in the session log and tells the frontend.
+func (m *Manager) sandboxNotice(id string, msg string) {
+	line := fmt.Sprintf("\r\n[aim sandbox %s] %s\r\n", time.Now().Format("15:04:05"), msg)
+	_ = m.persister.appendScrollback(id, []byte(line))
+	runtime.EventsEmit(m.ctx, fmt.Sprintf("session:sandbox:%s", id), msg)
+}
+
+// reportSandboxViolations notes filesystem writes the sandbox refused.
+func (m *Manager) reportSandboxViolations(id string, chunk []byte) {
+	if strings.Contains(string(chunk), "Read-only file system") {
+		m.sandboxNotice(id, "blocked a write outside the sandbox (read-only file system)")
+	}
+}
+
+func commonGitDir(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Clean(strings.TrimSpace(string(out)))
+}
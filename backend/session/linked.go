@@ -0,0 +1,60 @@
+package session
+
+import (
+	"path/filepath"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// sessionWorktrees returns s's worktree followed by those of its linked
+// repositories. Caller must hold m.mu.
+func sessionWorktrees(s *Session) []string {
+	var dirs []string
+	if s.Config.WorktreePath != "" {
+		dirs = append(dirs, s.Config.WorktreePath)
+	}
+	for _, l := range s.Config.LinkedRepos {
+		if l.WorktreePath != "" {
+			dirs = append(dirs, l.WorktreePath)
+		}
+	}
+	return dirs
+}
+
+// addDirArgs gives the agent access to the session's linked worktrees.
+// Claude and Codex both take --add-dir.
+func addDirArgs(s *Session) []string {
+	var args []string
+	for _, l := range s.Config.LinkedRepos {
+		if l.WorktreePath != "" {
+			args = append(args, "--add-dir", l.WorktreePath)
+		}
+	}
+	return args
+}
+
+// forgetLinkedWorktree records that the linked worktree at path was removed.
+// Caller must hold m.mu.
+func forgetLinkedWorktree(s *Session, path string, backup *worktree.Backup) {
+	for i, l := range s.Config.LinkedRepos {
+		if l.WorktreePath != "" && filepath.Clean(l.WorktreePath) == filepath.Clean(path) {
+			s.Config.LinkedRepos[i].WorktreePath = ""
+			if backup != nil {
+				s.Config.LinkedRepos[i].Backup = backup
+			}
+		}
+	}
+}
+
+// attachLinkedWorktree records the recreated worktree of the linked
+// repository at repoPath. Caller must hold m.mu.
+func attachLinkedWorktree(s *Session, repoPath, path string) bool {
+	for i, l := range s.Config.LinkedRepos {
+		if filepath.Clean(l.RepoPath) == filepath.Clean(repoPath) {
+			s.Config.LinkedRepos[i].WorktreePath = path
+			s.Config.LinkedRepos[i].Backup = nil
+			return true
+		}
+	}
+	return false
+}
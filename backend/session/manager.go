@@ -3,7 +3,6 @@ package session
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,12 +11,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/hooks"
+	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/storage"
+	"github.com/Benbentwo/aim/backend/worktree"
 	"github.com/google/uuid"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // Status constants
 const (
+	StatusStarting = "starting" // worktree setup running before the agent starts
 	StatusIdle     = "idle"
 	StatusThinking = "thinking"
 	StatusWaiting  = "waiting"
@@ -35,6 +40,22 @@ type SessionConfig struct {
 	Branch       string `json:"branch"`       // git branch for worktree
 	WorkspaceID  string `json:"workspaceId"`
 	RepoPath     string `json:"repoPath"` // main git repo root (needed for worktree cleanup)
+	// IssueID and IssueIdentifier link the session to a Linear issue.
+	IssueID         string `json:"issueId,omitempty"`
+	IssueIdentifier string `json:"issueIdentifier,omitempty"` // e.g. "ENG-123"
+	// InitialPrompt is sent to a new claude or codex agent as its first message.
+	// It is not persisted, so resuming never replays it.
+	InitialPrompt string `json:"initialPrompt,omitempty"`
+	// LinkedRepos are further repositories the session works on, each in its
+	// own worktree on the session's branch.
+	LinkedRepos []LinkedRepo `json:"linkedRepos,omitempty"`
+}
+
+// LinkedRepo is a repository worked on alongside a session's main one.
+type LinkedRepo struct {
+	RepoPath     string           `json:"repoPath"`
+	WorktreePath string           `json:"worktreePath"`     // "" once the worktree was removed
+	Backup       *worktree.Backup `json:"backup,omitempty"` // changes saved when the worktree was removed
 }
 
 // Session is the runtime session record.
@@ -44,6 +65,17 @@ type Session struct {
 	WorkDir    string        `json:"workDir"` // actual working directory (worktree or dir)
 	Archived   bool          `json:"archived,omitempty"`
 	ArchivedAt *time.Time    `json:"archivedAt,omitempty"`
+	CreatedAt  *time.Time    `json:"createdAt,omitempty"`
+	Tags       []string      `json:"tags,omitempty"`
+	Notes      string        `json:"notes,omitempty"` // free-form markdown
+	// AgentSessionID is the agent's own conversation ID, used to resume it.
+	AgentSessionID string `json:"agentSessionId,omitempty"`
+	// PullRequestURL is the pull request opened from the session's branch.
+	PullRequestURL string `json:"pullRequestUrl,omitempty"`
+	// SetupLog is the output of a failed worktree setup.
+	SetupLog string `json:"setupLog,omitempty"`
+	// Backup holds uncommitted changes saved when the worktree was removed.
+	Backup *worktree.Backup `json:"backup,omitempty"`
 }
 
 // SessionState is what gets persisted and returned to the frontend.
@@ -59,6 +91,20 @@ type SessionState struct {
 	RepoPath     string     `json:"repoPath"`
 	Archived     bool       `json:"archived,omitempty"`
 	ArchivedAt   *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+	// AgentSessionID is the agent's conversation ID (e.g. Claude's --session-id).
+	AgentSessionID string `json:"agentSessionId,omitempty"`
+	// CurrentTool is the tool the agent is running, when reported by hooks.
+	CurrentTool string `json:"currentTool,omitempty"`
+
+	IssueID         string           `json:"issueId,omitempty"`
+	IssueIdentifier string           `json:"issueIdentifier,omitempty"`
+	PullRequestURL  string           `json:"pullRequestUrl,omitempty"`
+	SetupLog        string           `json:"setupLog,omitempty"` // output of a failed worktree setup
+	Backup          *worktree.Backup `json:"backup,omitempty"`   // changes saved when the worktree was removed
+	LinkedRepos     []LinkedRepo     `json:"linkedRepos,omitempty"`
 }
 
 // Manager manages all active sessions.
@@ -68,45 +114,72 @@ type Manager struct {
 	sessions  map[string]*Session
 	ptySessions map[string]*ptySession
 	statuses  map[string]string
+	views     map[string]*SessionView
 	persister *persister
+	settings  *settings.Manager
+
+	tools       map[string]string // tool each session's agent is running, from hooks
+	hookServer  *hooks.Server
+	hookCommand string
+	listeners   []StatusListener
+
+	launchOptions LaunchOptionsFunc
+	setups        map[string]*setupRun // worktree setups in progress
+	naming        map[string]bool      // sessions whose branch is being renamed
 }
 
-func NewManager() *Manager {
+// StatusListener is called after a session's status changes.
+type StatusListener func(id string, from string, to string)
+
+func NewManager(locator *config.Locator, settingsMgr *settings.Manager) *Manager {
 	return &Manager{
+		settings:    settingsMgr,
 		sessions:    make(map[string]*Session),
 		ptySessions: make(map[string]*ptySession),
 		statuses:    make(map[string]string),
-		persister:   newPersister(),
+		views:       make(map[string]*SessionView),
+		tools:       make(map[string]string),
+		setups:      make(map[string]*setupRun),
+		naming:      make(map[string]bool),
+		persister:   newPersister(locator),
 	}
 }
 
 func (m *Manager) SetContext(ctx context.Context) {
 	m.ctx = ctx
+	m.startHookServer()
 	m.loadPersistedSessions()
+	m.loadPersistedViews()
 	// Read cleanup setting and pass to cleanup function
 	cleanupDays := m.loadCleanupDays()
 	m.cleanupStaleWorktrees(cleanupDays)
 }
 
-// loadCleanupDays reads the archiveWorktreeCleanupDays setting from disk.
-// Returns 7 (the default) if the file is missing, zero, or unparseable.
+// Reload stops every running session and reloads sessions and views from the
+// active profile. Used after switching profiles.
+func (m *Manager) Reload() {
+	m.killAll()
+	m.mu.Lock()
+	m.sessions = make(map[string]*Session)
+	m.ptySessions = make(map[string]*ptySession)
+	m.statuses = make(map[string]string)
+	m.views = make(map[string]*SessionView)
+	m.tools = make(map[string]string)
+	m.mu.Unlock()
+	m.loadPersistedSessions()
+	m.loadPersistedViews()
+}
+
+// loadCleanupDays returns the archiveWorktreeCleanupDays setting. 0 means disabled.
 func (m *Manager) loadCleanupDays() int {
-	confDir, _ := os.UserConfigDir()
-	data, err := os.ReadFile(filepath.Join(confDir, "aim", "settings.json"))
-	if err != nil {
-		return 7 // default: clean up after 7 days
-	}
-	var s struct {
-		ArchiveWorktreeCleanupDays int `json:"archiveWorktreeCleanupDays"`
-	}
-	if err := json.Unmarshal(data, &s); err != nil {
-		return 7
-	}
-	return s.ArchiveWorktreeCleanupDays // caller treats 0 as disabled
+	return m.settings.GetSettings().ArchiveWorktreeCleanupDays
 }
 
 func (m *Manager) loadPersistedSessions() {
-	sessions, err := m.persister.loadSessions()
+	sessions, rec, err := m.persister.loadSessions()
+	if rec != nil {
+		runtime.EventsEmit(m.ctx, storage.RecoveredEvent, rec)
+	}
 	if err != nil {
 		return
 	}
@@ -128,10 +201,22 @@ func (m *Manager) loadPersistedSessions() {
 				WorktreePath: ss.WorktreePath,
 				Branch:       ss.Branch,
 				RepoPath:     ss.RepoPath,
+
+				IssueID:         ss.IssueID,
+				IssueIdentifier: ss.IssueIdentifier,
+				LinkedRepos:     ss.LinkedRepos,
 			},
 			WorkDir:    workDir,
 			Archived:   ss.Archived,
 			ArchivedAt: ss.ArchivedAt,
+			CreatedAt:  ss.CreatedAt,
+			Tags:       ss.Tags,
+			Notes:      ss.Notes,
+
+			AgentSessionID: ss.AgentSessionID,
+			PullRequestURL: ss.PullRequestURL,
+			SetupLog:       ss.SetupLog,
+			Backup:         ss.Backup,
 		}
 		m.statuses[ss.ID] = StatusStopped
 	}
@@ -145,10 +230,12 @@ func (m *Manager) CreateSession(config SessionConfig) (string, error) {
 		workDir = config.WorktreePath
 	}
 
+	now := time.Now()
 	s := &Session{
-		ID:      id,
-		Config:  config,
-		WorkDir: workDir,
+		ID:        id,
+		Config:    config,
+		WorkDir:   workDir,
+		CreatedAt: &now,
 	}
 
 	m.mu.Lock()
@@ -156,7 +243,19 @@ func (m *Manager) CreateSession(config SessionConfig) (string, error) {
 	m.statuses[id] = StatusIdle
 	m.mu.Unlock()
 
-	ps, err := spawnPTY(s, m)
+	if config.InitialPrompt != "" {
+		go m.nameBranchFromPrompt(id, config.InitialPrompt)
+	}
+
+	if config.UseWorktree && config.WorktreePath != "" {
+		if steps := m.setupSteps(s); len(steps) > 0 {
+			m.startWithBootstrap(s, steps)
+			m.persist()
+			return id, nil
+		}
+	}
+
+	ps, err := spawnPTY(s, m, false)
 	if err != nil {
 		m.mu.Lock()
 		delete(m.sessions, id)
@@ -173,16 +272,32 @@ func (m *Manager) CreateSession(config SessionConfig) (string, error) {
 	return id, nil
 }
 
-// ResumeSession re-spawns a stopped session.
+// ResumeSession re-spawns a stopped session. Claude and Codex are relaunched
+// with their resume flags so the agent's conversation picks up where it left off.
 func (m *Manager) ResumeSession(id string) error {
 	m.mu.RLock()
 	s, ok := m.sessions[id]
+	_, settingUp := m.setups[id]
 	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("session %s not found", id)
 	}
+	if settingUp {
+		// The agent starts on its own once the setup finishes.
+		return fmt.Errorf("session %s is still being set up", id)
+	}
+	if s.SetupLog != "" {
+		// The setup failed before the agent ever started; run it again.
+		if steps := m.setupSteps(s); len(steps) > 0 {
+			m.startWithBootstrap(s, steps)
+			return nil
+		}
+		m.mu.Lock()
+		s.SetupLog = ""
+		m.mu.Unlock()
+	}
 
-	ps, err := spawnPTY(s, m)
+	ps, err := spawnPTY(s, m, true)
 	if err != nil {
 		return fmt.Errorf("spawn PTY: %w", err)
 	}
@@ -191,6 +306,7 @@ func (m *Manager) ResumeSession(id string) error {
 	m.ptySessions[id] = ps
 	m.statuses[id] = StatusIdle
 	m.mu.Unlock()
+	m.persist()
 	return nil
 }
 
@@ -198,11 +314,20 @@ func (m *Manager) ResumeSession(id string) error {
 func (m *Manager) WriteToSession(id string, data string) error {
 	m.mu.RLock()
 	ps, ok := m.ptySessions[id]
+	s := m.sessions[id]
+	// Claude reports the prompt itself through hooks.
+	capture := s != nil && needsBranchName(s) && !(s.Config.Agent == "claude" && m.hookServer != nil)
 	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("session %s not active", id)
 	}
-	return ps.write(data)
+	if err := ps.write(data); err != nil {
+		return err
+	}
+	if capture {
+		m.capturePrompt(ps, id, data)
+	}
+	return nil
 }
 
 // ResizeSession resizes the PTY window.
@@ -218,11 +343,13 @@ func (m *Manager) ResizeSession(id string, cols int, rows int) error {
 
 // CloseSession kills the PTY process and removes the session.
 func (m *Manager) CloseSession(id string) error {
+	m.cancelSetup(id)
 	m.mu.Lock()
 	ps, hasPTY := m.ptySessions[id]
 	delete(m.ptySessions, id)
 	delete(m.sessions, id)
 	delete(m.statuses, id)
+	delete(m.tools, id)
 	m.mu.Unlock()
 
 	if hasPTY {
@@ -235,6 +362,7 @@ func (m *Manager) CloseSession(id string) error {
 // ArchiveSession kills the PTY if running, marks the session archived, and persists.
 // The worktree is left on disk.
 func (m *Manager) ArchiveSession(id string) error {
+	m.cancelSetup(id)
 	m.mu.Lock()
 	ps, hasPTY := m.ptySessions[id]
 	if hasPTY {
@@ -314,23 +442,40 @@ func (m *Manager) ListSessions() []SessionState {
 
 	result := make([]SessionState, 0, len(m.sessions))
 	for id, s := range m.sessions {
-		result = append(result, SessionState{
-			ID:           id,
-			WorkspaceID:  s.Config.WorkspaceID,
-			Name:         s.Config.Name,
-			Agent:        s.Config.Agent,
-			Directory:    s.Config.Directory,
-			WorktreePath: s.Config.WorktreePath,
-			Branch:       s.Config.Branch,
-			Status:       m.statuses[id],
-			RepoPath:     s.Config.RepoPath,
-			Archived:     s.Archived,
-			ArchivedAt:   s.ArchivedAt,
-		})
+		result = append(result, m.stateLocked(id, s))
 	}
 	return result
 }
 
+// stateLocked builds the SessionState for s. Caller must hold m.mu.
+func (m *Manager) stateLocked(id string, s *Session) SessionState {
+	return SessionState{
+		ID:           id,
+		WorkspaceID:  s.Config.WorkspaceID,
+		Name:         s.Config.Name,
+		Agent:        s.Config.Agent,
+		Directory:    s.Config.Directory,
+		WorktreePath: s.Config.WorktreePath,
+		Branch:       s.Config.Branch,
+		Status:       m.statuses[id],
+		RepoPath:     s.Config.RepoPath,
+		Archived:     s.Archived,
+		ArchivedAt:   s.ArchivedAt,
+		CreatedAt:    s.CreatedAt,
+		Tags:         s.Tags,
+		Notes:        s.Notes,
+
+		AgentSessionID:  s.AgentSessionID,
+		CurrentTool:     m.tools[id],
+		IssueID:         s.Config.IssueID,
+		IssueIdentifier: s.Config.IssueIdentifier,
+		PullRequestURL:  s.PullRequestURL,
+		SetupLog:        s.SetupLog,
+		Backup:          s.Backup,
+		LinkedRepos:     append([]LinkedRepo(nil), s.Config.LinkedRepos...),
+	}
+}
+
 // RenameSessionBranch renames the git branch of a worktree session.
 // Called after the user types their first message so the branch gets a meaningful name.
 func (m *Manager) RenameSessionBranch(id string, newBranch string) error {
@@ -343,10 +488,22 @@ func (m *Manager) RenameSessionBranch(id string, newBranch string) error {
 	if s.Config.WorktreePath == "" {
 		return nil // plain directory session, nothing to rename
 	}
+	if err := worktree.ValidateBranchName(newBranch); err != nil {
+		return err
+	}
 
-	cmd := exec.Command("git", "-C", s.Config.WorktreePath, "branch", "-m", newBranch)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git branch -m: %s", strings.TrimSpace(string(out)))
+	// Linked worktrees share the branch name, so rename it in all of them or none.
+	m.mu.RLock()
+	dirs := sessionWorktrees(s)
+	m.mu.RUnlock()
+	for i, dir := range dirs {
+		cmd := exec.Command("git", "-C", dir, "branch", "-m", newBranch)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			for _, done := range dirs[:i] {
+				_ = exec.Command("git", "-C", done, "branch", "-m", newBranch, s.Config.Branch).Run()
+			}
+			return fmt.Errorf("git branch -m: %s", strings.TrimSpace(string(out)))
+		}
 	}
 
 	m.mu.Lock()
@@ -372,17 +529,155 @@ func (m *Manager) GetSessionLog(id string) (string, error) {
 	return base64.StdEncoding.EncodeToString(data), nil
 }
 
-// updateStatus updates session status and emits event.
+// AddStatusListener registers fn to be called on every status transition.
+func (m *Manager) AddStatusListener(fn StatusListener) {
+	m.mu.Lock()
+	m.listeners = append(m.listeners, fn)
+	m.mu.Unlock()
+}
+
+// SetSessionPullRequest records the pull request opened from a session.
+func (m *Manager) SetSessionPullRequest(id string, url string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if ok {
+		s.PullRequestURL = url
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	m.persist()
+	return nil
+}
+
+// SetSessionWorktreePath records that a session's worktree moved to path,
+// pointing every session that works in it at the new location. It refuses
+// while any of those sessions is running.
+func (m *Manager) SetSessionWorktreePath(id string, path string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("session %s not found", id)
+	}
+	old := s.Config.WorktreePath
+	if old == "" {
+		m.mu.Unlock()
+		return fmt.Errorf("session %s has no worktree", id)
+	}
+	for otherID, other := range m.sessions {
+		if other.WorkDir != old {
+			continue
+		}
+		if status := m.statuses[otherID]; status != StatusStopped && status != StatusErrored {
+			m.mu.Unlock()
+			return fmt.Errorf("session %s is running in %s", other.Config.Name, old)
+		}
+	}
+	for _, other := range m.sessions {
+		if other.Config.WorktreePath == old {
+			other.Config.WorktreePath = path
+		}
+		if other.Config.Directory == old {
+			other.Config.Directory = path
+		}
+		if other.WorkDir == old {
+			other.WorkDir = path
+		}
+	}
+	m.mu.Unlock()
+	m.persist()
+	return nil
+}
+
+// ForgetSessionWorktree records that the session worktree at path was removed,
+// along with the backup of its uncommitted changes, if any. The session
+// itself is kept.
+func (m *Manager) ForgetSessionWorktree(id string, path string, backup *worktree.Backup) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if ok && filepath.Clean(s.Config.WorktreePath) == filepath.Clean(path) {
+		s.Config.WorktreePath = ""
+		if backup != nil {
+			s.Backup = backup
+		}
+	} else if ok {
+		forgetLinkedWorktree(s, path, backup)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	m.persist()
+	return nil
+}
+
+// AttachSessionWorktree points a session whose worktree was removed at a
+// recreated one, and drops its restored backup.
+func (m *Manager) AttachSessionWorktree(id string, path string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	if ok {
+		s.Config.UseWorktree = true
+		s.Config.WorktreePath = path
+		s.WorkDir = path
+		s.Backup = nil
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	m.persist()
+	return nil
+}
+
+// AttachLinkedWorktree points a linked repository whose worktree was removed
+// at a recreated one, and drops its restored backup.
+func (m *Manager) AttachLinkedWorktree(id, repoPath, path string) error {
+	m.mu.Lock()
+	s, ok := m.sessions[id]
+	found := ok && attachLinkedWorktree(s, repoPath, path)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("session %s not found", id)
+	}
+	if !found {
+		return fmt.Errorf("session %s has no linked repository %s", id, repoPath)
+	}
+	m.persist()
+	return nil
+}
+
+// GetSession returns a single session with its current status.
+func (m *Manager) GetSession(id string) (SessionState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return SessionState{}, fmt.Errorf("session %s not found", id)
+	}
+	return m.stateLocked(id, s), nil
+}
+
+// updateStatus updates session status, emits an event and notifies listeners.
 func (m *Manager) updateStatus(id string, status string) {
 	m.mu.Lock()
+	prev := m.statuses[id]
 	m.statuses[id] = status
 	if ps, ok := m.ptySessions[id]; ok {
 		ps.mu.Lock()
 		ps.status = status
 		ps.mu.Unlock()
 	}
+	listeners := m.listeners
 	m.mu.Unlock()
 	runtime.EventsEmit(m.ctx, fmt.Sprintf("session:status:%s", id), status)
+	if prev != status {
+		for _, fn := range listeners {
+			fn(id, prev, status)
+		}
+	}
 }
 
 // detectStatus infers session status from PTY output chunk.
@@ -414,27 +709,23 @@ func (m *Manager) detectStatus(id string, chunk []byte) {
 	}
 }
 
-// persist saves current session list to disk.
+// persist saves current session list to disk. A failure is logged and
+// emitted so the frontend can warn that changes aren't being saved.
 func (m *Manager) persist() {
 	m.mu.RLock()
 	sessions := make([]SessionState, 0, len(m.sessions))
 	for id, s := range m.sessions {
-		sessions = append(sessions, SessionState{
-			ID:           id,
-			WorkspaceID:  s.Config.WorkspaceID,
-			Name:         s.Config.Name,
-			Agent:        s.Config.Agent,
-			Directory:    s.Config.Directory,
-			WorktreePath: s.Config.WorktreePath,
-			Branch:       s.Config.Branch,
-			Status:       m.statuses[id],
-			RepoPath:     s.Config.RepoPath,
-			Archived:     s.Archived,
-			ArchivedAt:   s.ArchivedAt,
-		})
+		sessions = append(sessions, m.stateLocked(id, s))
 	}
 	m.mu.RUnlock()
-	_ = m.persister.saveSessions(sessions)
+	if err := m.persister.saveSessions(sessions); err != nil {
+		runtime.LogErrorf(m.ctx, "save sessions: %v", err)
+		runtime.EventsEmit(m.ctx, storage.SaveFailedEvent, storage.SaveFailure{
+			File:  m.persister.sessionsFile(),
+			Error: err.Error(),
+			Time:  time.Now(),
+		})
+	}
 }
 
 // cleanupStaleWorktrees removes worktrees for sessions archived longer than
@@ -450,6 +741,7 @@ func (m *Manager) cleanupStaleWorktrees(cleanupDays int) {
 		id           string
 		repoPath     string
 		worktreePath string
+		linked       []LinkedRepo
 	}
 	m.mu.RLock()
 	var candidates []candidate
@@ -457,13 +749,14 @@ func (m *Manager) cleanupStaleWorktrees(cleanupDays int) {
 		if !s.Archived || s.ArchivedAt == nil || s.ArchivedAt.After(cutoff) {
 			continue
 		}
-		if s.Config.WorktreePath == "" || s.Config.RepoPath == "" {
+		if (s.Config.WorktreePath == "" || s.Config.RepoPath == "") && len(s.Config.LinkedRepos) == 0 {
 			continue
 		}
 		candidates = append(candidates, candidate{
 			id:           s.ID,
 			repoPath:     s.Config.RepoPath,
 			worktreePath: s.Config.WorktreePath,
+			linked:       append([]LinkedRepo(nil), s.Config.LinkedRepos...),
 		})
 	}
 	m.mu.RUnlock()
@@ -472,34 +765,66 @@ func (m *Manager) cleanupStaleWorktrees(cleanupDays int) {
 		return
 	}
 
-	// Run git commands outside the lock
-	var cleaned []string
+	// Run git commands outside the lock. Uncommitted changes are backed up
+	// first; a worktree whose backup fails is left in place.
+	cleaned := make(map[string]*worktree.Backup)
+	linked := make(map[string]map[string]*worktree.Backup)
 	for _, c := range candidates {
-		cmd := exec.Command("git", "-C", c.repoPath, "worktree", "remove", "--force", c.worktreePath)
-		if cmd.Run() == nil {
-			cleaned = append(cleaned, c.id)
+		if c.worktreePath != "" && c.repoPath != "" {
+			if backup, err := worktree.RemoveWorktree(c.repoPath, c.worktreePath, false); err == nil {
+				cleaned[c.id] = backup
+			}
+		}
+		for _, l := range c.linked {
+			if l.WorktreePath == "" {
+				continue
+			}
+			if backup, err := worktree.RemoveWorktree(l.RepoPath, l.WorktreePath, false); err == nil {
+				if linked[c.id] == nil {
+					linked[c.id] = make(map[string]*worktree.Backup)
+				}
+				linked[c.id][l.WorktreePath] = backup
+			}
 		}
 	}
 
-	if len(cleaned) == 0 {
+	if len(cleaned) == 0 && len(linked) == 0 {
 		return
 	}
 
 	// Update map entries with lock
 	m.mu.Lock()
-	for _, id := range cleaned {
+	for id, backup := range cleaned {
 		if s, ok := m.sessions[id]; ok {
 			s.Config.WorktreePath = "" // worktree removed; session metadata preserved
+			if backup != nil {
+				s.Backup = backup
+			}
+		}
+	}
+	for id, removed := range linked {
+		if s, ok := m.sessions[id]; ok {
+			for path, backup := range removed {
+				forgetLinkedWorktree(s, path, backup)
+			}
 		}
 	}
 	m.mu.Unlock()
 	m.persist()
 }
 
-// Shutdown kills all active PTY sessions.
+// Shutdown kills all active PTY sessions and stops the hook server.
 func (m *Manager) Shutdown() {
+	m.killAll()
+	m.stopHookServer()
+}
+
+func (m *Manager) killAll() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	for _, run := range m.setups {
+		run.cancel()
+	}
 	for _, ps := range m.ptySessions {
 		_ = ps.kill()
 	}
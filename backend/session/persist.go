@@ -6,61 +6,109 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/storage"
 )
 
 const maxScrollbackLines = 10000
 
+// sessionsSchema versions sessions.json.
+var sessionsSchema = storage.Schema{
+	Name:    "sessions.json",
+	Version: 1,
+	Migrations: []storage.Migration{
+		{
+			From:        0,
+			Description: "timestamp archived sessions that predate archivedAt",
+			Apply: func(data json.RawMessage) (json.RawMessage, error) {
+				// Without archivedAt the worktree cleanup never considers the
+				// session, so start its clock at upgrade time.
+				now := time.Now()
+				return storage.EachObject(data, func(obj map[string]json.RawMessage) error {
+					if string(obj["archived"]) != "true" {
+						return nil
+					}
+					return storage.SetDefault(obj, "archivedAt", now)
+				})
+			},
+		},
+	},
+}
+
+// viewsSchema versions views.json.
+var viewsSchema = storage.Schema{
+	Name:    "views.json",
+	Version: 1,
+	Migrations: []storage.Migration{
+		{From: 0, Description: "add version envelope", Apply: storage.Wrap},
+	},
+}
+
 type persister struct {
 	mu      sync.Mutex
-	baseDir string
+	locator *config.Locator
 }
 
-func newPersister() *persister {
-	confDir, _ := os.UserConfigDir()
+func newPersister(locator *config.Locator) *persister {
 	return &persister{
-		baseDir: filepath.Join(confDir, "aim"),
+		locator: locator,
 	}
 }
 
 func (p *persister) sessionsFile() string {
-	return filepath.Join(p.baseDir, "sessions.json")
+	return p.locator.Path("sessions.json")
+}
+
+func (p *persister) viewsFile() string {
+	return p.locator.Path("views.json")
 }
 
 func (p *persister) sessionDir(id string) string {
-	return filepath.Join(p.baseDir, "sessions", id)
+	return p.locator.Path("sessions", id)
 }
 
 func (p *persister) scrollbackFile(id string) string {
 	return filepath.Join(p.sessionDir(id), "scrollback.log")
 }
 
-func (p *persister) loadSessions() ([]SessionState, error) {
-	data, err := os.ReadFile(p.sessionsFile())
+// loadSessions reads sessions.json, falling back to its backup if the file is
+// corrupt. A non-nil Recovery means the caller should tell the user.
+func (p *persister) loadSessions() ([]SessionState, *storage.Recovery, error) {
+	var sessions []SessionState
+	rec, err := sessionsSchema.Load(p.sessionsFile(), &sessions)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil
+			return nil, nil, nil
 		}
-		return nil, err
-	}
-	var sessions []SessionState
-	if err := json.Unmarshal(data, &sessions); err != nil {
-		return nil, err
+		return nil, rec, err
 	}
-	return sessions, nil
+	return sessions, rec, nil
 }
 
 func (p *persister) saveSessions(sessions []SessionState) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	return sessionsSchema.Save(p.sessionsFile(), sessions)
+}
 
-	if err := os.MkdirAll(p.baseDir, 0755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(sessions, "", "  ")
+func (p *persister) loadViews() ([]SessionView, *storage.Recovery, error) {
+	var views []SessionView
+	rec, err := viewsSchema.Load(p.viewsFile(), &views)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, rec, err
 	}
-	return os.WriteFile(p.sessionsFile(), data, 0644)
+	return views, rec, nil
+}
+
+func (p *persister) saveViews(views []SessionView) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return viewsSchema.Save(p.viewsFile(), views)
 }
 
 func (p *persister) appendScrollback(id string, data []byte) error {
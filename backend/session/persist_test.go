@@ -0,0 +1,45 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionsSchemaFromV0(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	v0 := `[
+		{"id": "a", "name": "active", "status": "stopped"},
+		{"id": "b", "name": "old", "status": "stopped", "archived": true},
+		{"id": "c", "name": "dated", "status": "stopped", "archived": true, "archivedAt": "2024-01-02T03:04:05Z"}
+	]`
+	if err := os.WriteFile(path, []byte(v0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var sessions []SessionState
+	if _, err := sessionsSchema.Load(path, &sessions); err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("loaded %d sessions, want 3", len(sessions))
+	}
+	if sessions[0].ArchivedAt != nil {
+		t.Errorf("active session got archivedAt %v", sessions[0].ArchivedAt)
+	}
+	if sessions[1].ArchivedAt == nil {
+		t.Error("archived session without archivedAt was not timestamped")
+	}
+	if got := sessions[2].ArchivedAt; got == nil || got.Year() != 2024 {
+		t.Errorf("existing archivedAt changed to %v", got)
+	}
+
+	// The rewritten file loads again without further changes.
+	var again []SessionState
+	if _, err := sessionsSchema.Load(path, &again); err != nil {
+		t.Fatal(err)
+	}
+	if again[1].ArchivedAt == nil || !again[1].ArchivedAt.Equal(*sessions[1].ArchivedAt) {
+		t.Errorf("archivedAt not persisted: %v", again[1].ArchivedAt)
+	}
+}
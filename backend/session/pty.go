@@ -38,36 +38,46 @@ type ptySession struct {
 	process   *os.Process
 	lastOutput time.Time
 	status    string
+	hooked    bool // agent reports status via hooks; skip output heuristics
+	prompt    []rune // keystrokes typed since the last Enter, until the branch is named
+	pasting   bool   // inside a bracketed paste, where Enter doesn't submit
+	sandboxed bool
 	persister *persister
 }
 
-func spawnPTY(s *Session, mgr *Manager) (*ptySession, error) {
-	var cmdName string
-	var cmdArgs []string
-
-	switch s.Config.Agent {
-	case "claude":
-		cmdName = "claude"
-		cmdArgs = []string{"--dangerously-skip-permissions"}
-	case "codex":
-		cmdName = "codex"
-	default:
-		// shell
-		cmdName = os.Getenv("SHELL")
-		if cmdName == "" {
-			cmdName = "/bin/zsh"
-		}
+func spawnPTY(s *Session, mgr *Manager, resume bool) (*ptySession, error) {
+	// Work on a copy so transcript lookups don't hold the manager lock.
+	mgr.mu.RLock()
+	launch := *s
+	mgr.mu.RUnlock()
+	launched := time.Now()
+	prepareAgentSession(&launch, resume)
+	cmdName, cmdArgs := agentCommand(&launch, resume)
+	mgr.mu.Lock()
+	if s.AgentSessionID == "" {
+		s.AgentSessionID = launch.AgentSessionID
+	}
+	mgr.mu.Unlock()
+	hookArgs, hookEnv := mgr.hookLaunch(s)
+	cmdArgs = append(cmdArgs, hookArgs...)
+
+	opts := mgr.resolveLaunchOptions(s.Config.WorkspaceID)
+	cmdName, cmdArgs, cleanup, err := mgr.sandboxLaunch(s, opts.Sandbox, cmdName, cmdArgs, mgr.hookSocket())
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: %w", err)
 	}
 
 	cmd := exec.Command(cmdName, cmdArgs...)
 	cmd.Dir = s.WorkDir
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(append(os.Environ(), opts.environ()...),
 		"TERM=xterm-256color",
 		fmt.Sprintf("AIM_SESSION_ID=%s", s.ID),
 	)
+	cmd.Env = append(cmd.Env, hookEnv...)
 
 	ptmx, err := pty.Start(cmd)
 	if err != nil {
+		cleanup()
 		return nil, fmt.Errorf("pty.Start: %w", err)
 	}
 
@@ -77,6 +87,7 @@ func spawnPTY(s *Session, mgr *Manager) (*ptySession, error) {
 		process:   cmd.Process,
 		lastOutput: time.Now(),
 		status:    StatusIdle,
+		sandboxed: opts.Sandbox != nil && opts.Sandbox.Enabled,
 		persister: mgr.persister,
 	}
 
@@ -89,6 +100,7 @@ func spawnPTY(s *Session, mgr *Manager) (*ptySession, error) {
 	// Wait for process exit asynchronously
 	go func() {
 		err := cmd.Wait()
+		cleanup()
 		exitCode := 0
 		if err != nil {
 			if exitErr, ok := err.(*exec.ExitError); ok {
@@ -100,6 +112,7 @@ func spawnPTY(s *Session, mgr *Manager) (*ptySession, error) {
 			status = StatusErrored
 		}
 		mgr.updateStatus(s.ID, status)
+		mgr.captureAgentSession(s.ID, launched)
 		runtime.EventsEmit(mgr.ctx, fmt.Sprintf("session:exit:%s", s.ID), exitCode)
 	}()
 
@@ -116,12 +129,18 @@ func (ps *ptySession) readLoop(mgr *Manager) {
 
 			// Persist scrollback
 			_ = ps.persister.appendScrollback(ps.id, chunk)
+			if ps.sandboxed {
+				mgr.reportSandboxViolations(ps.id, chunk)
+			}
 
 			// Detect status
 			ps.mu.Lock()
 			ps.lastOutput = time.Now()
+			hooked := ps.hooked
 			ps.mu.Unlock()
-			mgr.detectStatus(ps.id, chunk)
+			if !hooked {
+				mgr.detectStatus(ps.id, chunk)
+			}
 
 			// Emit to frontend
 			encoded := base64.StdEncoding.EncodeToString(chunk)
@@ -136,7 +155,8 @@ func (ps *ptySession) readLoop(mgr *Manager) {
 	}
 }
 
-// waitingDetector watches for no-output periods while not idle.
+// waitingDetector watches for no-output periods while not idle. It is the
+// fallback for agents that don't report status through hooks.
 func (ps *ptySession) waitingDetector(mgr *Manager) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
@@ -151,8 +171,12 @@ func (ps *ptySession) waitingDetector(mgr *Manager) {
 		ps.mu.Lock()
 		timeSince := time.Since(ps.lastOutput)
 		currentStatus := ps.status
+		hooked := ps.hooked
 		ps.mu.Unlock()
 
+		if hooked {
+			continue
+		}
 		if timeSince > 5*time.Second && currentStatus == StatusThinking {
 			mgr.updateStatus(ps.id, StatusWaiting)
 		}
@@ -5,8 +5,41 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/storage"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// settingsSchema versions settings.json.
+var settingsSchema = storage.Schema{
+	Name:    "settings.json",
+	Version: 2,
+	Migrations: []storage.Migration{
+		{
+			From:        0,
+			Description: "default archiveWorktreeCleanupDays to 7",
+			Apply: func(data json.RawMessage) (json.RawMessage, error) {
+				// Files saved before the setting existed would otherwise read as
+				// 0, which disables cleanup instead of using the default.
+				return storage.Object(data, func(obj map[string]json.RawMessage) error {
+					return storage.SetDefault(obj, "archiveWorktreeCleanupDays", 7)
+				})
+			},
+		},
+		{
+			From:        1,
+			Description: "default branchNaming",
+			Apply: func(data json.RawMessage) (json.RawMessage, error) {
+				return storage.Object(data, func(obj map[string]json.RawMessage) error {
+					return storage.SetDefault(obj, "branchNaming", DefaultBranchNaming)
+				})
+			},
+		},
+	},
+}
+
 type Settings struct {
 	DefaultAgent               string `json:"defaultAgent"`               // "claude", "codex", "shell"
 	DefaultWorktree            bool   `json:"defaultWorktree"`            // true = create worktree by default
@@ -19,45 +52,91 @@ type Settings struct {
 	LinearClientID             string `json:"linearClientId"`             // custom Linear OAuth client ID
 	ReposBaseDir               string `json:"reposBaseDir"`               // base dir for cloned repos
 	ArchiveWorktreeCleanupDays int    `json:"archiveWorktreeCleanupDays"` // days before stale worktrees are removed
+	WorktreeRoot               string `json:"worktreeRoot"`               // where worktrees are created; "" = inside each repo's .git dir, new installs default to ~/.aim/worktrees
+	CloneCacheDir              string `json:"cloneCacheDir"`              // shared object cache for clones; "" = ~/.aim/cache
+
+	// BranchNaming renames a session's temporary branch after its first prompt.
+	BranchNaming BranchNaming `json:"branchNaming"`
+
+	// ModelPrices overrides DefaultModelPrices, keyed by model name prefix.
+	ModelPrices map[string]ModelPrice `json:"modelPrices,omitempty"`
 }
 
 type Manager struct {
-	ctx      context.Context
-	confPath string
+	ctx       context.Context
+	mu        sync.RWMutex
+	locator   *config.Locator
+	settings  Settings
+	recovered *storage.Recovery // from loading before the context was set
 }
 
-func NewManager() *Manager {
-	confDir, _ := os.UserConfigDir()
-	return &Manager{
-		confPath: filepath.Join(confDir, "aim", "settings.json"),
+// NewManager creates a Manager and loads the active profile's settings, so
+// managers created after it can read them straight away.
+func NewManager(locator *config.Locator) *Manager {
+	m := &Manager{
+		locator: locator,
 	}
+	m.Reload()
+	return m
 }
 
 func (m *Manager) SetContext(ctx context.Context) {
+	m.mu.Lock()
 	m.ctx = ctx
+	rec := m.recovered
+	m.recovered = nil
+	m.mu.Unlock()
+	if rec != nil {
+		runtime.EventsEmit(ctx, storage.RecoveredEvent, rec)
+	}
 }
 
-func (m *Manager) GetSettings() Settings {
-	data, err := os.ReadFile(m.confPath)
+// Reload reads and migrates settings.json from the active profile. Reads are
+// then served from memory until the next Reload.
+func (m *Manager) Reload() {
+	var s Settings
+	rec, err := settingsSchema.Load(m.confPath(), &s)
 	if err != nil {
-		return m.defaults()
+		s = m.defaults()
 	}
-	var s Settings
-	if err := json.Unmarshal(data, &s); err != nil {
-		return m.defaults()
+	m.mu.Lock()
+	m.settings = s
+	ctx := m.ctx
+	if ctx == nil {
+		m.recovered = rec
+	}
+	m.mu.Unlock()
+	if rec != nil && ctx != nil {
+		runtime.EventsEmit(ctx, storage.RecoveredEvent, rec)
+	}
+}
+
+func (m *Manager) GetSettings() Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.settings
+	if s.ModelPrices != nil {
+		// Callers may change their copy.
+		s.ModelPrices = make(map[string]ModelPrice, len(m.settings.ModelPrices))
+		for k, v := range m.settings.ModelPrices {
+			s.ModelPrices[k] = v
+		}
 	}
 	return s
 }
 
 func (m *Manager) SaveSettings(s Settings) error {
-	if err := os.MkdirAll(filepath.Dir(m.confPath), 0755); err != nil {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := settingsSchema.Save(m.confPath(), s); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(s, "", "  ")
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(m.confPath, data, 0644)
+	m.settings = s
+	return nil
+}
+
+func (m *Manager) confPath() string {
+	return m.locator.Path("settings.json")
 }
 
 func (m *Manager) defaults() Settings {
@@ -74,5 +153,7 @@ func (m *Manager) defaults() Settings {
 		DefaultRepoDir:             filepath.Join(home, "Projects"),
 		ReposBaseDir:               filepath.Join(home, ".aim", "repos"),
 		ArchiveWorktreeCleanupDays: 7,
+		WorktreeRoot:               filepath.Join(home, ".aim", "worktrees"),
+		BranchNaming:               DefaultBranchNaming,
 	}
 }
@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Benbentwo/aim/backend/config"
+)
+
+func TestSettingsSchemaFromV0(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"defaultAgent": "codex", "theme": "light"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var s Settings
+	if _, err := settingsSchema.Load(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.DefaultAgent != "codex" || s.Theme != "light" {
+		t.Errorf("existing fields changed: %+v", s)
+	}
+	if s.ArchiveWorktreeCleanupDays != 7 {
+		t.Errorf("archiveWorktreeCleanupDays = %d, want 7", s.ArchiveWorktreeCleanupDays)
+	}
+	if s.BranchNaming != DefaultBranchNaming {
+		t.Errorf("branchNaming = %+v, want the default", s.BranchNaming)
+	}
+}
+
+func TestSettingsSchemaKeepsExplicitValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	v1 := `{"version": 1, "data": {"archiveWorktreeCleanupDays": 0, "branchNaming": {"prefix": "me/"}}}`
+	if err := os.WriteFile(path, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var s Settings
+	if _, err := settingsSchema.Load(path, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.ArchiveWorktreeCleanupDays != 0 {
+		t.Errorf("archiveWorktreeCleanupDays = %d, want the stored 0", s.ArchiveWorktreeCleanupDays)
+	}
+	if s.BranchNaming.Prefix != "me/" {
+		t.Errorf("branchNaming = %+v, want the stored prefix", s.BranchNaming)
+	}
+}
+
+func TestManagerServesSettingsFromMemory(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	m := NewManager(config.NewLocator())
+	if got := m.GetSettings(); got.DefaultAgent != "claude" {
+		t.Fatalf("defaults = %+v", got)
+	}
+
+	s := m.GetSettings()
+	s.DefaultAgent = "codex"
+	if err := m.SaveSettings(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(m.confPath(), []byte(`{"defaultAgent": "shell"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetSettings().DefaultAgent; got != "codex" {
+		t.Errorf("defaultAgent = %q before Reload, want the saved codex", got)
+	}
+	m.Reload()
+	if got := m.GetSettings().DefaultAgent; got != "shell" {
+		t.Errorf("defaultAgent = %q after Reload, want shell", got)
+	}
+}
@@ -0,0 +1,19 @@
+package settings
+
+// BranchNaming is how a session's temporary branch is renamed after the
+// first prompt.
+type BranchNaming struct {
+	Prefix       string `json:"prefix"`       // prepended to every name, e.g. "aim/"
+	IncludeIssue bool   `json:"includeIssue"` // start with the Linear issue key, e.g. aim/eng-123-fix-login
+	MaxWords     int    `json:"maxWords"`     // words of the prompt kept in the slug
+	MaxLength    int    `json:"maxLength"`    // length of the slug
+	UseAgent     bool   `json:"useAgent"`     // ask the agent to summarize the prompt; falls back to the slug
+}
+
+// DefaultBranchNaming matches the names aim has always generated.
+var DefaultBranchNaming = BranchNaming{
+	Prefix:       "aim/",
+	IncludeIssue: true,
+	MaxWords:     6,
+	MaxLength:    50,
+}
@@ -0,0 +1,46 @@
+package settings
+
+import "strings"
+
+// ModelPrice is the USD price per million tokens for a model.
+type ModelPrice struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cacheRead"`
+	CacheWrite float64 `json:"cacheWrite"`
+}
+
+// DefaultModelPrices are list prices keyed by model name prefix. Entries in
+// Settings.ModelPrices override them.
+var DefaultModelPrices = map[string]ModelPrice{
+	"claude-opus-4-5":   {Input: 5, Output: 25, CacheRead: 0.5, CacheWrite: 6.25},
+	"claude-opus-4":     {Input: 15, Output: 75, CacheRead: 1.5, CacheWrite: 18.75},
+	"claude-sonnet-4":   {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-3-5-sonnet": {Input: 3, Output: 15, CacheRead: 0.3, CacheWrite: 3.75},
+	"claude-haiku-4-5":  {Input: 1, Output: 5, CacheRead: 0.1, CacheWrite: 1.25},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, CacheRead: 0.08, CacheWrite: 1},
+	"gpt-5":             {Input: 1.25, Output: 10, CacheRead: 0.125},
+	"o3":                {Input: 2, Output: 8, CacheRead: 0.5},
+	"o4-mini":           {Input: 1.1, Output: 4.4, CacheRead: 0.275},
+}
+
+// PriceFor returns the price for model, matching the longest configured
+// prefix. User entries take precedence over DefaultModelPrices.
+func (s Settings) PriceFor(model string) (ModelPrice, bool) {
+	if p, ok := longestPrefix(s.ModelPrices, model); ok {
+		return p, true
+	}
+	return longestPrefix(DefaultModelPrices, model)
+}
+
+func longestPrefix(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	var best string
+	var price ModelPrice
+	for prefix, p := range prices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, price = prefix, p
+		}
+	}
+	return price, best != ""
+}
@@ -0,0 +1,176 @@
+// Package storage provides crash-safe persistence for aim's JSON state files.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RecoveredEvent is emitted by managers when a state file was restored from
+// its backup. Recoveries during startup happen before the frontend listens,
+// so it also reads Recoveries once loaded.
+const RecoveredEvent = "storage:recovered"
+
+// SaveFailedEvent is emitted by managers, with a SaveFailure, when a state
+// file couldn't be written.
+const SaveFailedEvent = "storage:save-failed"
+
+// SaveFailure describes a state file that couldn't be written.
+type SaveFailure struct {
+	File  string    `json:"file"`
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// Recovery describes a state file that failed to load and how it was handled.
+type Recovery struct {
+	File        string    `json:"file"`
+	Backup      string    `json:"backup"`      // backup the data was restored from; empty if none was usable
+	CorruptCopy string    `json:"corruptCopy"` // where the unreadable file was moved aside
+	Error       string    `json:"error"`
+	Time        time.Time `json:"time"`
+}
+
+var (
+	recoveriesMu sync.Mutex
+	recoveries   []Recovery
+)
+
+// Recoveries returns every recovery since startup that hasn't been dismissed.
+func Recoveries() []Recovery {
+	recoveriesMu.Lock()
+	defer recoveriesMu.Unlock()
+	return append([]Recovery{}, recoveries...)
+}
+
+// DismissRecoveries forgets the recorded recoveries once the user has seen them.
+func DismissRecoveries() {
+	recoveriesMu.Lock()
+	recoveries = nil
+	recoveriesMu.Unlock()
+}
+
+func record(rec *Recovery) {
+	recoveriesMu.Lock()
+	recoveries = append(recoveries, *rec)
+	recoveriesMu.Unlock()
+}
+
+// BackupPath returns the path of the rolling backup kept next to path.
+func BackupPath(path string) string {
+	return path + ".bak"
+}
+
+// WriteFile atomically replaces path with data. The data is written to a temp
+// file in the same directory, fsynced, and renamed over path, so readers see
+// either the old or the new contents, never a partial write. If the current
+// file is valid JSON it is first kept as the rolling backup.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if prev, err := os.ReadFile(path); err == nil && json.Valid(prev) {
+		if err := writeAtomic(BackupPath(path), prev, perm); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+	}
+	return writeAtomic(path, data, perm)
+}
+
+// LoadJSON decodes path into v. A missing file returns an error satisfying
+// os.IsNotExist, and a file that can't be read (say, for lack of permission)
+// is left alone and its error returned. If the file is corrupt, it is moved
+// aside and the rolling backup is decoded and restored instead; the returned
+// Recovery is non-nil whenever that happened, even if the backup was unusable
+// too. Recoveries are also recorded for Recoveries.
+func LoadJSON(path string, v interface{}) (*Recovery, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", filepath.Base(path), err)
+	}
+	if err = json.Unmarshal(data, v); err == nil {
+		return nil, nil
+	}
+
+	rec := &Recovery{File: path, Error: err.Error(), Time: time.Now()}
+	defer record(rec)
+	if moved, mvErr := moveAside(path); mvErr == nil {
+		rec.CorruptCopy = moved
+	}
+
+	backup := BackupPath(path)
+	bdata, berr := os.ReadFile(backup)
+	if berr != nil {
+		return rec, fmt.Errorf("load %s: %w (no usable backup)", filepath.Base(path), err)
+	}
+	// The corrupt file may have been partly decoded into v; start over.
+	fresh := reflect.New(reflect.TypeOf(v).Elem())
+	if berr := json.Unmarshal(bdata, fresh.Interface()); berr != nil {
+		return rec, fmt.Errorf("load %s: %w (backup also corrupt: %v)", filepath.Base(path), err, berr)
+	}
+	reflect.ValueOf(v).Elem().Set(fresh.Elem())
+	rec.Backup = backup
+	// Put the good copy back so the next load doesn't repeat the recovery.
+	_ = writeAtomic(path, bdata, 0644)
+	return rec, nil
+}
+
+// moveAside renames a corrupt file so a later save doesn't overwrite the
+// evidence. Returns the new path.
+func moveAside(path string) (string, error) {
+	dest := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir fsyncs a directory so a completed rename survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Some platforms and filesystems don't support syncing directories.
+	_ = d.Sync()
+	return nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadJSONRestoresBackupIntoFreshValue(t *testing.T) {
+	DismissRecoveries()
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(BackupPath(path), []byte(`{"name": "good"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A type error doesn't stop the decoder filling in the other fields.
+	if err := os.WriteFile(path, []byte(`{"name": "bad", "extra": "x", "count": "many"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		Name  string `json:"name"`
+		Extra string `json:"extra"`
+		Count int    `json:"count"`
+	}
+	rec, err := LoadJSON(path, &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec == nil || rec.Backup != BackupPath(path) || rec.CorruptCopy == "" {
+		t.Fatalf("recovery = %+v", rec)
+	}
+	if v.Name != "good" || v.Extra != "" {
+		t.Errorf("loaded %+v, want only the backup's fields", v)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"name": "good"}` {
+		t.Errorf("file not restored: %s", data)
+	}
+
+	got := Recoveries()
+	if len(got) != 1 || got[0].File != path {
+		t.Errorf("recorded %+v", got)
+	}
+	DismissRecoveries()
+	if len(Recoveries()) != 0 {
+		t.Error("recoveries not dismissed")
+	}
+}
+
+func TestLoadJSONLeavesUnreadableFile(t *testing.T) {
+	DismissRecoveries()
+	// A directory can't be read as a file, even by root.
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(BackupPath(path), []byte(`{"name": "good"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Name string `json:"name"`
+	}
+	rec, err := LoadJSON(path, &v)
+	if err == nil || os.IsNotExist(err) {
+		t.Fatalf("err = %v, want a read error", err)
+	}
+	if rec != nil || len(Recoveries()) != 0 {
+		t.Errorf("recovered %+v from a read error", rec)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file moved aside: %v", err)
+	}
+}
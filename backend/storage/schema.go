@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Migration upgrades a document payload from version From to From+1.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(data json.RawMessage) (json.RawMessage, error)
+}
+
+// Schema describes a versioned state file and how to upgrade older copies.
+// Files are stored as {"version": N, "data": <payload>}; files written before
+// versioning existed hold the bare payload and are treated as version 0.
+type Schema struct {
+	Name       string
+	Version    int
+	Migrations []Migration
+}
+
+type envelope struct {
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Load reads path, upgrades it to the current version and decodes the payload
+// into v. An upgraded file is rewritten immediately; the pre-upgrade copy is
+// kept as the rolling backup. Missing files return an os.IsNotExist error.
+func (s Schema) Load(path string, v interface{}) (*Recovery, error) {
+	var raw json.RawMessage
+	rec, err := LoadJSON(path, &raw)
+	if err != nil {
+		return rec, err
+	}
+	payload, from, err := s.Upgrade(raw)
+	if err != nil {
+		return rec, fmt.Errorf("%s: %w", s.Name, err)
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return rec, fmt.Errorf("%s: decode v%d: %w", s.Name, s.Version, err)
+	}
+	if from < s.Version {
+		if err := WriteFile(path, s.wrap(payload), 0644); err != nil {
+			return rec, fmt.Errorf("%s: save upgraded file: %w", s.Name, err)
+		}
+	}
+	return rec, nil
+}
+
+// Save writes v to path wrapped in the current version envelope. It refuses
+// to overwrite a file written by a newer aim, which would drop whatever that
+// version added and stamp the file with the older version.
+func (s Schema) Save(path string, v interface{}) error {
+	if existing, err := os.ReadFile(path); err == nil {
+		if _, version := unwrap(existing); version > s.Version {
+			return fmt.Errorf("%s: written by a newer aim (v%d, this one knows v%d); not overwriting", s.Name, version, s.Version)
+		}
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, s.wrap(payload), 0644)
+}
+
+// Upgrade runs every migration needed to bring raw to the current version.
+// It returns the upgraded payload and the version raw was stored at. Files
+// from a newer aim are returned as-is; Save then leaves them alone.
+func (s Schema) Upgrade(raw []byte) (json.RawMessage, int, error) {
+	payload, from := unwrap(raw)
+	if from > s.Version {
+		return payload, from, nil
+	}
+	for v := from; v < s.Version; v++ {
+		m, ok := s.migration(v)
+		if !ok {
+			return nil, from, fmt.Errorf("no migration from v%d", v)
+		}
+		next, err := m.Apply(payload)
+		if err != nil {
+			return nil, from, fmt.Errorf("migrate v%d to v%d (%s): %w", v, v+1, m.Description, err)
+		}
+		payload = next
+	}
+	return payload, from, nil
+}
+
+func (s Schema) migration(from int) (Migration, bool) {
+	for _, m := range s.Migrations {
+		if m.From == from {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+func (s Schema) wrap(payload json.RawMessage) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "{\n  \"version\": %d,\n  \"data\": ", s.Version)
+	if err := json.Indent(&buf, bytes.TrimSpace(payload), "  ", "  "); err != nil {
+		buf.Write(bytes.TrimSpace(payload))
+	}
+	buf.WriteString("\n}\n")
+	return buf.Bytes()
+}
+
+// unwrap splits a stored document into its payload and version. Anything that
+// isn't exactly a {"version","data"} envelope is a legacy v0 payload.
+func unwrap(raw []byte) (json.RawMessage, int) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || len(fields) != 2 {
+		return raw, 0
+	}
+	if _, ok := fields["data"]; !ok {
+		return raw, 0
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return raw, 0
+	}
+	return env.Data, env.Version
+}
+
+// Wrap is a Migration for the v0 to v1 step when the payload shape is
+// unchanged; the envelope is added when the upgraded file is written back.
+func Wrap(data json.RawMessage) (json.RawMessage, error) {
+	return data, nil
+}
+
+// EachObject applies fn to every object in a JSON array payload, keeping
+// fields fn doesn't touch byte-for-byte.
+func EachObject(data json.RawMessage, fn func(obj map[string]json.RawMessage) error) (json.RawMessage, error) {
+	if isNull(data) {
+		return data, nil
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	for _, obj := range items {
+		if err := fn(obj); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(items)
+}
+
+// Object applies fn to a JSON object payload.
+func Object(data json.RawMessage, fn func(obj map[string]json.RawMessage) error) (json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		obj = make(map[string]json.RawMessage)
+	}
+	if err := fn(obj); err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// SetDefault sets obj[key] to value when the key is absent or null.
+func SetDefault(obj map[string]json.RawMessage, key string, value interface{}) error {
+	if cur, ok := obj[key]; ok && !isNull(cur) {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	obj[key] = data
+	return nil
+}
+
+func isNull(data json.RawMessage) bool {
+	return len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null"
+}
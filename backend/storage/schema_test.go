@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testSchema = Schema{
+	Name:    "test.json",
+	Version: 2,
+	Migrations: []Migration{
+		{From: 0, Description: "add version envelope", Apply: Wrap},
+		{
+			From:        1,
+			Description: "default color",
+			Apply: func(data json.RawMessage) (json.RawMessage, error) {
+				return Object(data, func(obj map[string]json.RawMessage) error {
+					return SetDefault(obj, "color", "blue")
+				})
+			},
+		},
+	},
+}
+
+type testDoc struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func writeTestFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func storedVersion(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, version := unwrap(data)
+	return version
+}
+
+func TestSchemaLoadUpgrades(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		content string
+	}{
+		{"v0", `{"name": "a"}`},
+		{"v1", `{"version": 1, "data": {"name": "a"}}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, tt.content)
+			var doc testDoc
+			if _, err := testSchema.Load(path, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if doc != (testDoc{Name: "a", Color: "blue"}) {
+				t.Errorf("loaded %+v", doc)
+			}
+			if v := storedVersion(t, path); v != testSchema.Version {
+				t.Errorf("file rewritten at v%d, want v%d", v, testSchema.Version)
+			}
+			backup, err := os.ReadFile(BackupPath(path))
+			if err != nil || string(backup) != tt.content {
+				t.Errorf("backup = %q, %v; want the original file", backup, err)
+			}
+		})
+	}
+}
+
+func TestSchemaNewerVersion(t *testing.T) {
+	content := `{"version": 9, "data": {"name": "a", "shape": "round"}}`
+	path := writeTestFile(t, content)
+
+	var doc testDoc
+	if _, err := testSchema.Load(path, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "a" {
+		t.Errorf("loaded %+v", doc)
+	}
+	if err := testSchema.Save(path, doc); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Save over a newer file = %v, want refusal", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("newer file changed to %s", data)
+	}
+}
+
+func TestSchemaFailedMigrationKeepsFile(t *testing.T) {
+	broken := testSchema
+	broken.Migrations = append([]Migration{}, testSchema.Migrations...)
+	broken.Migrations[1].Apply = func(json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("boom")
+	}
+	content := `{"version": 1, "data": {"name": "a"}}`
+	path := writeTestFile(t, content)
+
+	var doc testDoc
+	if _, err := broken.Load(path, &doc); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Load = %v, want the migration error", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("file changed to %s", data)
+	}
+	if _, err := os.Stat(BackupPath(path)); !os.IsNotExist(err) {
+		t.Errorf("backup written for a failed migration: %v", err)
+	}
+}
+
+func TestSchemaMissingMigration(t *testing.T) {
+	gap := Schema{Name: "test.json", Version: 2, Migrations: testSchema.Migrations[:1]}
+	path := writeTestFile(t, `{"name": "a"}`)
+	var doc testDoc
+	if _, err := gap.Load(path, &doc); err == nil || !strings.Contains(err.Error(), "no migration from v1") {
+		t.Errorf("Load = %v, want missing migration error", err)
+	}
+}
@@ -0,0 +1,391 @@
+package transcript
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// maxResult bounds the tool output kept per call; full output stays on disk.
+const maxResult = 16 * 1024
+
+// Message is a piece of text the agent wrote in a turn.
+type Message struct {
+	Text     string    `json:"text"`
+	Thinking bool      `json:"thinking,omitempty"` // extended-thinking block
+	Model    string    `json:"model,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// ToolCall is a tool the agent invoked and, once reported, its result.
+type ToolCall struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input"`
+	Result  string          `json:"result,omitempty"`
+	IsError bool            `json:"isError,omitempty"`
+	Done    bool            `json:"done"`
+	Time    time.Time       `json:"time"`
+}
+
+// FileEdit is a file the agent changed through a tool call.
+type FileEdit struct {
+	Path       string    `json:"path"`
+	Kind       string    `json:"kind"` // "edit", "write", "delete"
+	Tool       string    `json:"tool"`
+	ToolCallID string    `json:"toolCallId"`
+	Turn       int       `json:"turn"`
+	Time       time.Time `json:"time"`
+}
+
+// Turn is one user prompt and everything the agent did in response.
+type Turn struct {
+	Index     int        `json:"index"`
+	Prompt    string     `json:"prompt"`
+	Time      time.Time  `json:"time"`
+	Messages  []Message  `json:"messages"`
+	ToolCalls []ToolCall `json:"toolCalls"`
+}
+
+// Conversation is the structured form of an agent transcript.
+type Conversation struct {
+	Turns     []Turn     `json:"turns"`
+	FileEdits []FileEdit `json:"fileEdits"`
+}
+
+// ConversationParser builds a Conversation from transcript lines fed in
+// order. It tracks which turns changed so callers can send incremental
+// updates.
+type ConversationParser struct {
+	conv    Conversation
+	calls   map[string][2]int // tool call ID -> turn, call index
+	dirty   int               // lowest turn index changed since the last Changes call, -1 if none
+	editsAt int               // FileEdits already reported by Changes
+}
+
+// NewConversationParser returns an empty parser.
+func NewConversationParser() *ConversationParser {
+	return &ConversationParser{calls: make(map[string][2]int), dirty: -1}
+}
+
+// Conversation returns a copy of everything parsed so far.
+func (p *ConversationParser) Conversation() Conversation {
+	return Conversation{
+		Turns:     cloneTurns(p.conv.Turns),
+		FileEdits: append([]FileEdit(nil), p.conv.FileEdits...),
+	}
+}
+
+// Changes returns copies of the turns modified and file edits added since
+// the previous call.
+func (p *ConversationParser) Changes() ([]Turn, []FileEdit) {
+	var turns []Turn
+	if p.dirty >= 0 {
+		turns = cloneTurns(p.conv.Turns[p.dirty:])
+	}
+	edits := append([]FileEdit(nil), p.conv.FileEdits[p.editsAt:]...)
+	p.dirty = -1
+	p.editsAt = len(p.conv.FileEdits)
+	return turns, edits
+}
+
+// cloneTurns copies turns deeply enough that feeding the parser more lines,
+// which appends messages and fills in tool results of earlier turns, doesn't
+// change the copies.
+func cloneTurns(turns []Turn) []Turn {
+	result := make([]Turn, len(turns))
+	for i, t := range turns {
+		t.Messages = append([]Message(nil), t.Messages...)
+		t.ToolCalls = append([]ToolCall(nil), t.ToolCalls...)
+		result[i] = t
+	}
+	return result
+}
+
+// Feed parses one transcript line in the given format.
+func (p *ConversationParser) Feed(format string, line []byte) {
+	switch format {
+	case FormatClaude:
+		p.feedClaude(line)
+	case FormatCodex:
+		p.feedCodex(line)
+	}
+}
+
+func (p *ConversationParser) startTurn(prompt string, t time.Time) {
+	p.conv.Turns = append(p.conv.Turns, Turn{Index: len(p.conv.Turns), Prompt: prompt, Time: t})
+	p.touch(len(p.conv.Turns) - 1)
+}
+
+// current returns the turn agent output belongs to, starting one if the
+// transcript begins without a prompt (e.g. a resumed conversation).
+func (p *ConversationParser) current(t time.Time) *Turn {
+	if len(p.conv.Turns) == 0 {
+		p.startTurn("", t)
+	}
+	i := len(p.conv.Turns) - 1
+	p.touch(i)
+	return &p.conv.Turns[i]
+}
+
+func (p *ConversationParser) touch(i int) {
+	if p.dirty < 0 || i < p.dirty {
+		p.dirty = i
+	}
+}
+
+func (p *ConversationParser) addMessage(msg Message) {
+	if strings.TrimSpace(msg.Text) == "" {
+		return
+	}
+	turn := p.current(msg.Time)
+	turn.Messages = append(turn.Messages, msg)
+}
+
+func (p *ConversationParser) addCall(call ToolCall) {
+	turn := p.current(call.Time)
+	p.calls[call.ID] = [2]int{turn.Index, len(turn.ToolCalls)}
+	turn.ToolCalls = append(turn.ToolCalls, call)
+	for _, e := range fileEdits(call) {
+		e.Turn = turn.Index
+		p.conv.FileEdits = append(p.conv.FileEdits, e)
+	}
+}
+
+func (p *ConversationParser) setResult(id, result string, isError bool) {
+	pos, ok := p.calls[id]
+	if !ok {
+		return
+	}
+	if len(result) > maxResult {
+		result = result[:maxResult] + "\n… (truncated)"
+	}
+	call := &p.conv.Turns[pos[0]].ToolCalls[pos[1]]
+	call.Result, call.IsError, call.Done = result, isError, true
+	p.touch(pos[0])
+}
+
+// Claude Code writes one line per content block, with user lines carrying
+// either a prompt or the results of the previous tool calls.
+func (p *ConversationParser) feedClaude(line []byte) {
+	var rec struct {
+		Type        string    `json:"type"`
+		IsMeta      bool      `json:"isMeta"`
+		IsSidechain bool      `json:"isSidechain"`
+		Timestamp   time.Time `json:"timestamp"`
+		Message     struct {
+			Model   string          `json:"model"`
+			Content json.RawMessage `json:"content"`
+		} `json:"message"`
+	}
+	if json.Unmarshal(line, &rec) != nil || rec.IsMeta || rec.IsSidechain {
+		return
+	}
+	if rec.Type != "user" && rec.Type != "assistant" {
+		return
+	}
+
+	var text string
+	if json.Unmarshal(rec.Message.Content, &text) == nil {
+		if rec.Type == "user" {
+			p.startTurn(text, rec.Timestamp)
+		} else {
+			p.addMessage(Message{Text: text, Model: rec.Message.Model, Time: rec.Timestamp})
+		}
+		return
+	}
+
+	var blocks []struct {
+		Type      string          `json:"type"`
+		Text      string          `json:"text"`
+		Thinking  string          `json:"thinking"`
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Input     json.RawMessage `json:"input"`
+		ToolUseID string          `json:"tool_use_id"`
+		Content   json.RawMessage `json:"content"`
+		IsError   bool            `json:"is_error"`
+	}
+	if json.Unmarshal(rec.Message.Content, &blocks) != nil {
+		return
+	}
+	var prompt []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if rec.Type == "user" {
+				prompt = append(prompt, b.Text)
+			} else {
+				p.addMessage(Message{Text: b.Text, Model: rec.Message.Model, Time: rec.Timestamp})
+			}
+		case "thinking":
+			p.addMessage(Message{Text: b.Thinking, Thinking: true, Model: rec.Message.Model, Time: rec.Timestamp})
+		case "tool_use":
+			p.addCall(ToolCall{ID: b.ID, Name: b.Name, Input: b.Input, Time: rec.Timestamp})
+		case "tool_result":
+			p.setResult(b.ToolUseID, claudeResultText(b.Content), b.IsError)
+		}
+	}
+	if len(prompt) > 0 {
+		p.startTurn(strings.Join(prompt, "\n"), rec.Timestamp)
+	}
+}
+
+// claudeResultText flattens a tool_result content, which is either a string
+// or a list of blocks.
+func claudeResultText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(raw, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		} else {
+			parts = append(parts, "["+b.Type+"]")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Codex rollouts record prompts and replies as event_msg lines and tool calls
+// as response_item lines.
+func (p *ConversationParser) feedCodex(line []byte) {
+	var rec struct {
+		Timestamp time.Time       `json:"timestamp"`
+		Type      string          `json:"type"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+	if json.Unmarshal(line, &rec) != nil {
+		return
+	}
+	var payload struct {
+		Type      string          `json:"type"`
+		Message   string          `json:"message"`
+		Text      string          `json:"text"`
+		Name      string          `json:"name"`
+		Arguments string          `json:"arguments"`
+		Input     string          `json:"input"`
+		CallID    string          `json:"call_id"`
+		Output    json.RawMessage `json:"output"`
+	}
+	if json.Unmarshal(rec.Payload, &payload) != nil {
+		return
+	}
+	switch rec.Type + "/" + payload.Type {
+	case "event_msg/user_message":
+		p.startTurn(payload.Message, rec.Timestamp)
+	case "event_msg/agent_message":
+		p.addMessage(Message{Text: payload.Message, Time: rec.Timestamp})
+	case "event_msg/agent_reasoning":
+		p.addMessage(Message{Text: payload.Text, Thinking: true, Time: rec.Timestamp})
+	case "response_item/function_call":
+		p.addCall(ToolCall{ID: payload.CallID, Name: payload.Name, Input: rawJSON(payload.Arguments), Time: rec.Timestamp})
+	case "response_item/custom_tool_call":
+		input, _ := json.Marshal(payload.Input)
+		p.addCall(ToolCall{ID: payload.CallID, Name: payload.Name, Input: input, Time: rec.Timestamp})
+	case "response_item/function_call_output", "response_item/custom_tool_call_output":
+		p.setResult(payload.CallID, codexOutputText(payload.Output), false)
+	}
+}
+
+// codexOutputText unwraps a function call output, which is a string that
+// itself may hold {"output": ...} JSON.
+func codexOutputText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		var obj struct {
+			Content string `json:"content"`
+		}
+		if json.Unmarshal(raw, &obj) == nil {
+			return obj.Content
+		}
+		return string(raw)
+	}
+	var wrapped struct {
+		Output *string `json:"output"`
+	}
+	if json.Unmarshal([]byte(s), &wrapped) == nil && wrapped.Output != nil {
+		return *wrapped.Output
+	}
+	return s
+}
+
+func rawJSON(s string) json.RawMessage {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// fileEdits returns the files a tool call modifies.
+func fileEdits(call ToolCall) []FileEdit {
+	edit := func(path, kind string) FileEdit {
+		return FileEdit{Path: path, Kind: kind, Tool: call.Name, ToolCallID: call.ID, Time: call.Time}
+	}
+	switch call.Name {
+	case "Edit", "MultiEdit", "Write", "NotebookEdit":
+		var in struct {
+			FilePath     string `json:"file_path"`
+			NotebookPath string `json:"notebook_path"`
+		}
+		if json.Unmarshal(call.Input, &in) != nil {
+			return nil
+		}
+		path, kind := in.FilePath, "edit"
+		if call.Name == "Write" {
+			kind = "write"
+		}
+		if call.Name == "NotebookEdit" {
+			path = in.NotebookPath
+		}
+		if path == "" {
+			return nil
+		}
+		return []FileEdit{edit(path, kind)}
+	case "apply_patch":
+		var patch string
+		if json.Unmarshal(call.Input, &patch) != nil {
+			var in struct {
+				Input string `json:"input"`
+			}
+			_ = json.Unmarshal(call.Input, &in)
+			patch = in.Input
+		}
+		return patchEdits(patch, edit)
+	case "shell", "container.exec":
+		var in struct {
+			Command []string `json:"command"`
+		}
+		if json.Unmarshal(call.Input, &in) != nil || len(in.Command) < 2 || in.Command[0] != "apply_patch" {
+			return nil
+		}
+		return patchEdits(in.Command[1], edit)
+	}
+	return nil
+}
+
+// patchEdits lists the files touched by a Codex apply_patch envelope.
+func patchEdits(patch string, edit func(path, kind string) FileEdit) []FileEdit {
+	var edits []FileEdit
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*** Add File: "):
+			edits = append(edits, edit(strings.TrimPrefix(line, "*** Add File: "), "write"))
+		case strings.HasPrefix(line, "*** Update File: "):
+			edits = append(edits, edit(strings.TrimPrefix(line, "*** Update File: "), "edit"))
+		case strings.HasPrefix(line, "*** Delete File: "):
+			edits = append(edits, edit(strings.TrimPrefix(line, "*** Delete File: "), "delete"))
+		}
+	}
+	return edits
+}
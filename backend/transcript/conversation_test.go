@@ -0,0 +1,33 @@
+package transcript
+
+import "testing"
+
+func TestConversationChangesAreCopies(t *testing.T) {
+	p := NewConversationParser()
+	for _, line := range []string{
+		`{"type":"user","message":{"content":"list files"}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"ls"}}]}}`,
+	} {
+		p.Feed(FormatClaude, []byte(line))
+	}
+	turns, _ := p.Changes()
+	whole := p.Conversation()
+	if len(turns) != 1 || len(turns[0].ToolCalls) != 1 || turns[0].ToolCalls[0].Done {
+		t.Fatalf("changes = %+v", turns)
+	}
+
+	// The result is attached to the earlier turn; copies handed out before
+	// must not see it.
+	p.Feed(FormatClaude, []byte(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"a.go"}]}}`))
+	p.Feed(FormatClaude, []byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"done"}]}}`))
+	for _, got := range [][]Turn{turns, whole.Turns} {
+		if got[0].ToolCalls[0].Done || got[0].ToolCalls[0].Result != "" || len(got[0].Messages) != 0 {
+			t.Errorf("copy changed by later lines: %+v", got[0])
+		}
+	}
+
+	turns, _ = p.Changes()
+	if len(turns) != 1 || turns[0].ToolCalls[0].Result != "a.go" || len(turns[0].Messages) != 1 {
+		t.Errorf("later changes = %+v", turns)
+	}
+}
@@ -0,0 +1,92 @@
+package transcript
+
+// Line is a transcript line and the file it was read from.
+type Line struct {
+	Path   string
+	Format string
+	Data   []byte
+}
+
+// SessionFiles follows the transcripts one agent session writes. Claude
+// starts a new file on /clear, so there may be several; they are read in the
+// order they were found. It is not safe for concurrent use.
+type SessionFiles struct {
+	tailers   []*Tailer
+	formats   []string
+	codexID   string
+	codexPath string // rollout found for codexID
+	checked   bool
+}
+
+// Update looks for the agent's current transcript, then returns the lines
+// appended to any of the session's files since the last call. running says
+// whether the agent may still be starting a transcript.
+func (f *SessionFiles) Update(agent, workDir, agentSessionID string, running bool) []Line {
+	lookup := true
+	if agent == "codex" {
+		// Resolving a Codex rollout walks its sessions directory, so the path
+		// is kept until the ID changes; a running session without one yet
+		// keeps looking.
+		if agentSessionID != f.codexID {
+			f.codexPath = ""
+		}
+		lookup = !f.checked || agentSessionID != f.codexID || (f.codexPath == "" && running)
+		f.codexID, f.checked = agentSessionID, true
+	}
+	if lookup {
+		path, format := Locate(agent, workDir, agentSessionID)
+		if agent == "codex" {
+			f.codexPath = path
+		}
+		if path != "" && !f.has(path) {
+			f.tailers = append(f.tailers, NewTailer(path))
+			f.formats = append(f.formats, format)
+		}
+	}
+
+	var lines []Line
+	for i, t := range f.tailers {
+		data, err := t.ReadNew()
+		if err != nil {
+			continue
+		}
+		for _, d := range data {
+			lines = append(lines, Line{Path: t.Path, Format: f.formats[i], Data: d})
+		}
+	}
+	return lines
+}
+
+// ReadBack returns every line Update has returned so far, read again from
+// the start of each file.
+func (f *SessionFiles) ReadBack() []Line {
+	var lines []Line
+	for i, t := range f.tailers {
+		data, err := t.ReadBack()
+		if err != nil {
+			continue
+		}
+		for _, d := range data {
+			lines = append(lines, Line{Path: t.Path, Format: f.formats[i], Data: d})
+		}
+	}
+	return lines
+}
+
+// Paths returns the files found so far, oldest first.
+func (f *SessionFiles) Paths() []string {
+	paths := make([]string, 0, len(f.tailers))
+	for _, t := range f.tailers {
+		paths = append(paths, t.Path)
+	}
+	return paths
+}
+
+func (f *SessionFiles) has(path string) bool {
+	for _, t := range f.tailers {
+		if t.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionFilesFollowsNewTranscripts(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	workDir := t.TempDir()
+	dir := ClaudeProjectDirs(workDir)[0]
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	appendLine := func(id, line string) {
+		t.Helper()
+		f, err := os.OpenFile(filepath.Join(dir, id+".jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	text := func(lines []Line) []string {
+		var result []string
+		for _, l := range lines {
+			if l.Format != FormatClaude {
+				t.Errorf("%s: format %q", l.Path, l.Format)
+			}
+			result = append(result, string(l.Data))
+		}
+		return result
+	}
+
+	var f SessionFiles
+	if lines := f.Update("claude", workDir, "one", true); len(lines) != 0 {
+		t.Fatalf("lines before any transcript: %q", text(lines))
+	}
+	appendLine("one", "a")
+	appendLine("one", "b")
+	if got := text(f.Update("claude", workDir, "one", true)); len(got) != 2 || got[1] != "b" {
+		t.Fatalf("first read = %q", got)
+	}
+
+	// After /clear Claude writes to a new file; the old one is still read.
+	appendLine("one", "c")
+	appendLine("two", "d")
+	if got := text(f.Update("claude", workDir, "two", true)); len(got) != 2 || got[0] != "c" || got[1] != "d" {
+		t.Fatalf("after /clear = %q", got)
+	}
+	if paths := f.Paths(); len(paths) != 2 || filepath.Base(paths[0]) != "one.jsonl" {
+		t.Errorf("paths = %q", paths)
+	}
+	if lines := f.Update("claude", workDir, "two", false); len(lines) != 0 {
+		t.Errorf("lines read twice: %q", text(lines))
+	}
+}
+
+func TestSessionFilesKeepsCodexRollout(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("CODEX_HOME", home)
+	rollout := func(day, id string) string {
+		t.Helper()
+		dir := filepath.Join(home, "sessions", "2026", "10", day)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "rollout-2026-10-"+day+"T10-00-00-"+id+".jsonl")
+		if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	var f SessionFiles
+	f.Update("codex", t.TempDir(), "abc", true)
+	if len(f.Paths()) != 0 {
+		t.Fatalf("paths before the rollout exists: %q", f.Paths())
+	}
+	first := rollout("17", "abc")
+	f.Update("codex", t.TempDir(), "abc", true)
+	if paths := f.Paths(); len(paths) != 1 || paths[0] != first {
+		t.Fatalf("running session without a rollout didn't find it: %q", paths)
+	}
+
+	// Found once, the rollout isn't looked up again for the same ID.
+	if err := os.RemoveAll(filepath.Join(home, "sessions", "2026", "10", "17")); err != nil {
+		t.Fatal(err)
+	}
+	rollout("18", "abc")
+	f.Update("codex", t.TempDir(), "abc", true)
+	if paths := f.Paths(); len(paths) != 1 {
+		t.Errorf("rollout resolved again: %q", paths)
+	}
+
+	next := rollout("18", "def")
+	f.Update("codex", t.TempDir(), "def", true)
+	if paths := f.Paths(); len(paths) != 2 || paths[1] != next {
+		t.Errorf("new ID not resolved: %q", paths)
+	}
+}
@@ -0,0 +1,147 @@
+package transcript
+
+import (
+	"sync"
+	"time"
+)
+
+// followInterval is how often followed transcripts are checked for new
+// lines.
+const followInterval = 2 * time.Second
+
+// Source is where an agent session writes its transcripts.
+type Source struct {
+	Agent          string `json:"agent"`
+	WorkDir        string `json:"workDir"`
+	AgentSessionID string `json:"agentSessionId"`
+	Running        bool   `json:"running"` // the agent may still start a transcript
+}
+
+// Follower follows the transcripts of every agent session and hands the new
+// lines to its subscribers, so each file is read once however many of them
+// there are.
+type Follower struct {
+	mu          sync.Mutex
+	sources     func() map[string]Source
+	sessions    map[string]*SessionFiles
+	subscribers []subscriber
+	stopCh      chan struct{}
+	running     bool
+}
+
+type subscriber struct {
+	lines func(id string, lines []Line)
+	gone  func(id string)
+}
+
+// NewFollower returns a Follower of the sessions sources returns, keyed by
+// session ID.
+func NewFollower(sources func() map[string]Source) *Follower {
+	return &Follower{
+		sources:  sources,
+		sessions: make(map[string]*SessionFiles),
+	}
+}
+
+// Subscribe registers lines to receive the lines read from each session's
+// transcripts, in order, and gone to learn when a session is no longer
+// followed. Both are called with the follower's lock held and must not call
+// back into it.
+func (f *Follower) Subscribe(lines func(id string, lines []Line), gone func(id string)) {
+	f.mu.Lock()
+	f.subscribers = append(f.subscribers, subscriber{lines: lines, gone: gone})
+	f.mu.Unlock()
+}
+
+// Start starts following transcripts.
+func (f *Follower) Start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running {
+		return
+	}
+	f.stopCh = make(chan struct{})
+	f.running = true
+	stop := f.stopCh
+	go func() {
+		f.Poll()
+		ticker := time.NewTicker(followInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				f.Poll()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops following transcripts.
+func (f *Follower) Shutdown() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.running {
+		close(f.stopCh)
+		f.running = false
+	}
+}
+
+// Poll reads the lines appended to every session's transcripts and stops
+// following sessions that are gone.
+func (f *Follower) Poll() {
+	sources := f.sources()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, src := range sources {
+		f.readLocked(id, src)
+	}
+	for id := range f.sessions {
+		if _, ok := sources[id]; !ok {
+			delete(f.sessions, id)
+			for _, s := range f.subscribers {
+				s.gone(id)
+			}
+		}
+	}
+}
+
+// Update brings session id up to date from src, as Poll would, and returns
+// its transcript files, oldest first.
+func (f *Follower) Update(id string, src Source) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readLocked(id, src)
+	return f.sessions[id].Paths()
+}
+
+// Replay brings session id up to date from src, then calls fn with every
+// line read from its transcripts so far, from the start of each file, and
+// returns the files. Later lines reach subscribers as usual; none are missed
+// or repeated in between.
+func (f *Follower) Replay(id string, src Source, fn func(lines []Line)) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readLocked(id, src)
+	files := f.sessions[id]
+	fn(files.ReadBack())
+	return files.Paths()
+}
+
+// readLocked reads session id's new lines and hands them to subscribers.
+// Caller must hold f.mu.
+func (f *Follower) readLocked(id string, src Source) {
+	files, ok := f.sessions[id]
+	if !ok {
+		files = &SessionFiles{}
+		f.sessions[id] = files
+	}
+	lines := files.Update(src.Agent, src.WorkDir, src.AgentSessionID, src.Running)
+	if len(lines) == 0 {
+		return
+	}
+	for _, s := range f.subscribers {
+		s.lines(id, lines)
+	}
+}
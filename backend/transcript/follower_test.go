@@ -0,0 +1,70 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFollowerSharesAndReplaysLines(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	workDir := t.TempDir()
+	dir := ClaudeProjectDirs(workDir)[0]
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	appendLine := func(line string) {
+		t.Helper()
+		f, err := os.OpenFile(filepath.Join(dir, "one.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	text := func(lines []Line) string {
+		var parts []string
+		for _, l := range lines {
+			parts = append(parts, string(l.Data))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	sources := map[string]Source{"s1": {Agent: "claude", WorkDir: workDir, AgentSessionID: "one", Running: true}}
+	f := NewFollower(func() map[string]Source { return sources })
+	var first, second []string
+	var gone []string
+	f.Subscribe(func(id string, lines []Line) { first = append(first, text(lines)) }, func(id string) { gone = append(gone, id) })
+	f.Subscribe(func(id string, lines []Line) { second = append(second, text(lines)) }, func(string) {})
+
+	appendLine("a")
+	appendLine("b")
+	f.Poll()
+	appendLine("c")
+	var replayed string
+	paths := f.Replay("s1", sources["s1"], func(lines []Line) { replayed = text(lines) })
+	if replayed != "a,b,c" {
+		t.Errorf("replayed %q, want a,b,c", replayed)
+	}
+	if len(paths) != 1 || filepath.Base(paths[0]) != "one.jsonl" {
+		t.Errorf("paths = %q", paths)
+	}
+	appendLine("d")
+	f.Poll()
+	f.Poll()
+	if got := strings.Join(first, "|"); got != "a,b|c|d" {
+		t.Errorf("first subscriber got %q, want a,b|c|d", got)
+	}
+	if got := strings.Join(second, "|"); got != strings.Join(first, "|") {
+		t.Errorf("second subscriber got %q, first %q", got, strings.Join(first, "|"))
+	}
+
+	sources = map[string]Source{}
+	f.Poll()
+	if len(gone) != 1 || gone[0] != "s1" {
+		t.Errorf("gone = %q, want [s1]", gone)
+	}
+}
@@ -0,0 +1,212 @@
+// Package transcript locates and reads the conversation logs that agent CLIs
+// write to disk.
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxCodexScan bounds how many recent Codex rollout files are inspected when
+// looking for a session by working directory.
+const maxCodexScan = 200
+
+var nonAlnum = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// ClaudeHome returns Claude Code's config directory ($CLAUDE_CONFIG_DIR or ~/.claude).
+func ClaudeHome() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude")
+}
+
+// CodexHome returns Codex's config directory ($CODEX_HOME or ~/.codex).
+func CodexHome() string {
+	if dir := os.Getenv("CODEX_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".codex")
+}
+
+// ClaudeProjectDirs returns the directories Claude Code may use for
+// transcripts of sessions started in workDir. Claude names the directory
+// after the path with every non-alphanumeric character replaced by '-';
+// the symlink-resolved path is included because that's what Claude sees as
+// its cwd on systems like macOS where /var links to /private/var.
+func ClaudeProjectDirs(workDir string) []string {
+	dirs := []string{claudeProjectDir(workDir)}
+	if real, err := filepath.EvalSymlinks(workDir); err == nil && real != workDir {
+		dirs = append(dirs, claudeProjectDir(real))
+	}
+	return dirs
+}
+
+func claudeProjectDir(workDir string) string {
+	return filepath.Join(ClaudeHome(), "projects", nonAlnum.ReplaceAllString(filepath.Clean(workDir), "-"))
+}
+
+// ClaudeTranscriptPath returns the transcript file for a Claude session, or
+// "" if it doesn't exist yet (Claude creates it on the first prompt).
+func ClaudeTranscriptPath(workDir, sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	for _, dir := range ClaudeProjectDirs(workDir) {
+		p := filepath.Join(dir, sessionID+".jsonl")
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// LatestClaudeSession returns the ID of the most recently written Claude
+// transcript for workDir.
+func LatestClaudeSession(workDir string) (string, error) {
+	var newest string
+	var newestMod int64
+	for _, dir := range ClaudeProjectDirs(workDir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			if mod := info.ModTime().UnixNano(); mod > newestMod {
+				newestMod = mod
+				newest = strings.TrimSuffix(e.Name(), ".jsonl")
+			}
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no Claude transcripts for %s", workDir)
+	}
+	return newest, nil
+}
+
+// LatestCodexSession returns the ID and rollout file of the most recent Codex
+// session whose working directory was workDir and that started no earlier
+// than since. A zero since accepts any session.
+func LatestCodexSession(workDir string, since time.Time) (string, string, error) {
+	type rollout struct {
+		path string
+		mod  int64
+	}
+	var files []rollout
+	root := filepath.Join(CodexHome(), "sessions")
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".jsonl") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, rollout{path: path, mod: info.ModTime().UnixNano()})
+		}
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].mod > files[j].mod })
+	if len(files) > maxCodexScan {
+		files = files[:maxCodexScan]
+	}
+
+	want := filepath.Clean(workDir)
+	real, _ := filepath.EvalSymlinks(workDir)
+	for _, f := range files {
+		if f.mod < since.UnixNano() {
+			break // last written before since, so started before it too
+		}
+		// Older Codex versions don't record a start time; the write time
+		// checked above has to do.
+		id, cwd, started, err := readCodexMeta(f.path)
+		if err != nil || id == "" || (!started.IsZero() && started.Before(since)) {
+			continue
+		}
+		cwd = filepath.Clean(cwd)
+		if cwd == want || (real != "" && cwd == real) {
+			return id, f.path, nil
+		}
+	}
+	return "", "", fmt.Errorf("no Codex sessions for %s", workDir)
+}
+
+// readCodexMeta reads the session_meta record at the top of a Codex rollout file.
+func readCodexMeta(path string) (id, cwd string, started time.Time, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for i := 0; i < 5 && sc.Scan(); i++ {
+		var rec struct {
+			Type    string `json:"type"`
+			Payload struct {
+				ID        string    `json:"id"`
+				Cwd       string    `json:"cwd"`
+				Timestamp time.Time `json:"timestamp"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(sc.Bytes(), &rec) != nil || rec.Type != "session_meta" {
+			continue
+		}
+		return rec.Payload.ID, rec.Payload.Cwd, rec.Payload.Timestamp, nil
+	}
+	return "", "", time.Time{}, fmt.Errorf("no session_meta in %s", path)
+}
+
+// CodexRolloutPath returns the rollout file for a Codex session ID, or "" if
+// none is found. Codex names rollouts rollout-<timestamp>-<id>.jsonl.
+func CodexRolloutPath(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	suffix := "-" + sessionID + ".jsonl"
+	var found string
+	_ = filepath.WalkDir(filepath.Join(CodexHome(), "sessions"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || found != "" {
+			return nil
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), suffix) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// Locate returns the transcript currently being written for an agent session
+// and its format, or "" if it can't be found. A Codex session's ID is only
+// known once it exits, so running Codex sessions fall back to the newest
+// rollout for workDir.
+func Locate(agent, workDir, agentSessionID string) (path, format string) {
+	switch agent {
+	case "claude":
+		return ClaudeTranscriptPath(workDir, agentSessionID), FormatClaude
+	case "codex":
+		if agentSessionID != "" {
+			return CodexRolloutPath(agentSessionID), FormatCodex
+		}
+		_, path, _ = LatestCodexSession(workDir, time.Time{})
+		return path, FormatCodex
+	}
+	return "", ""
+}
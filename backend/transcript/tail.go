@@ -0,0 +1,95 @@
+package transcript
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// maxLine bounds a single buffered JSONL record.
+const maxLine = 16 * 1024 * 1024
+
+// Tailer reads complete lines appended to a file since the previous call.
+type Tailer struct {
+	Path    string
+	offset  int64
+	partial []byte
+}
+
+// NewTailer returns a Tailer positioned at the start of path.
+func NewTailer(path string) *Tailer {
+	return &Tailer{Path: path}
+}
+
+// ReadNew returns the complete lines written since the last call. A trailing
+// line without a newline is held back until it is finished. If the file
+// shrank it is re-read from the start.
+func (t *Tailer) ReadNew() ([][]byte, error) {
+	f, err := os.Open(t.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < t.offset {
+		t.offset = 0
+		t.partial = nil
+	}
+	if info.Size() == t.offset {
+		return nil, nil
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, info.Size()-t.offset))
+	if err != nil {
+		return nil, err
+	}
+	t.offset += int64(len(data))
+
+	lines, rest := splitLines(append(t.partial, data...))
+	t.partial = nil
+	if len(rest) > 0 && len(rest) < maxLine {
+		t.partial = append([]byte(nil), rest...)
+	}
+	return lines, nil
+}
+
+// ReadBack returns the complete lines ReadNew has returned so far, read
+// again from the start of the file.
+func (t *Tailer) ReadBack() ([][]byte, error) {
+	end := t.offset - int64(len(t.partial))
+	if end <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(t.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, end))
+	if err != nil {
+		return nil, err
+	}
+	lines, _ := splitLines(data)
+	return lines, nil
+}
+
+// splitLines returns the non-blank lines of data and what follows the last
+// newline.
+func splitLines(data []byte) (lines [][]byte, rest []byte) {
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return lines, data
+		}
+		if line := bytes.TrimSpace(data[:i]); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		data = data[i+1:]
+	}
+}
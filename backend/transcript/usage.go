@@ -0,0 +1,143 @@
+package transcript
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Transcript formats.
+const (
+	FormatClaude = "claude"
+	FormatCodex  = "codex"
+)
+
+// Usage counts tokens billed for one or more model calls.
+type Usage struct {
+	InputTokens      int64 `json:"inputTokens"`
+	OutputTokens     int64 `json:"outputTokens"`
+	CacheReadTokens  int64 `json:"cacheReadTokens"`
+	CacheWriteTokens int64 `json:"cacheWriteTokens"`
+}
+
+// Add accumulates o into u.
+func (u *Usage) Add(o Usage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheReadTokens += o.CacheReadTokens
+	u.CacheWriteTokens += o.CacheWriteTokens
+}
+
+// Total returns the sum of all token kinds.
+func (u Usage) Total() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheReadTokens + u.CacheWriteTokens
+}
+
+// UsageRecord is the usage of a single model turn.
+type UsageRecord struct {
+	ID    string // dedupe key; Claude repeats usage on every block of a message and copies records into new transcripts
+	Model string
+	Usage Usage
+}
+
+// UsageParser extracts per-turn usage from transcript lines. Codex reports
+// the model separately from token counts, so a parser carries state and must
+// be used for one file at a time.
+type UsageParser struct {
+	Format string
+	Path   string // file being parsed; Codex record IDs are unique only within it
+	model  string
+	seq    int
+}
+
+// Parse returns the usage recorded on line, if any.
+func (p *UsageParser) Parse(line []byte) (UsageRecord, bool) {
+	switch p.Format {
+	case FormatClaude:
+		return parseClaudeUsage(line)
+	case FormatCodex:
+		return p.parseCodexUsage(line)
+	}
+	return UsageRecord{}, false
+}
+
+func parseClaudeUsage(line []byte) (UsageRecord, bool) {
+	var rec struct {
+		Type      string `json:"type"`
+		RequestID string `json:"requestId"`
+		UUID      string `json:"uuid"`
+		Message   struct {
+			ID    string `json:"id"`
+			Model string `json:"model"`
+			Usage *struct {
+				InputTokens              int64 `json:"input_tokens"`
+				OutputTokens             int64 `json:"output_tokens"`
+				CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+				CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			} `json:"usage"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil || rec.Type != "assistant" || rec.Message.Usage == nil {
+		return UsageRecord{}, false
+	}
+	id := rec.Message.ID + "|" + rec.RequestID
+	if rec.Message.ID == "" {
+		id = rec.UUID
+	}
+	u := rec.Message.Usage
+	return UsageRecord{
+		ID:    id,
+		Model: rec.Message.Model,
+		Usage: Usage{
+			InputTokens:      u.InputTokens,
+			OutputTokens:     u.OutputTokens,
+			CacheReadTokens:  u.CacheReadInputTokens,
+			CacheWriteTokens: u.CacheCreationInputTokens,
+		},
+	}, true
+}
+
+func (p *UsageParser) parseCodexUsage(line []byte) (UsageRecord, bool) {
+	var rec struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return UsageRecord{}, false
+	}
+	switch rec.Type {
+	case "turn_context":
+		var ctx struct {
+			Model string `json:"model"`
+		}
+		if json.Unmarshal(rec.Payload, &ctx) == nil && ctx.Model != "" {
+			p.model = ctx.Model
+		}
+	case "event_msg":
+		var ev struct {
+			Type string `json:"type"`
+			Info *struct {
+				Last *struct {
+					InputTokens       int64 `json:"input_tokens"`
+					CachedInputTokens int64 `json:"cached_input_tokens"`
+					OutputTokens      int64 `json:"output_tokens"`
+				} `json:"last_token_usage"`
+			} `json:"info"`
+		}
+		if json.Unmarshal(rec.Payload, &ev) != nil || ev.Type != "token_count" || ev.Info == nil || ev.Info.Last == nil {
+			return UsageRecord{}, false
+		}
+		p.seq++
+		last := ev.Info.Last
+		// Codex counts cached tokens inside input_tokens.
+		return UsageRecord{
+			ID:    "codex|" + p.Path + "|" + strconv.Itoa(p.seq),
+			Model: p.model,
+			Usage: Usage{
+				InputTokens:     last.InputTokens - last.CachedInputTokens,
+				OutputTokens:    last.OutputTokens,
+				CacheReadTokens: last.CachedInputTokens,
+			},
+		}, true
+	}
+	return UsageRecord{}, false
+}
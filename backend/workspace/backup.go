@@ -0,0 +1,73 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// RestoreSessionBackup brings back the uncommitted changes saved when a
+// session's worktrees were removed, its own and those of its linked
+// repositories. Each worktree is recreated on the session's branch if it's
+// gone. Returns the session's worktree path.
+func (m *Manager) RestoreSessionBackup(id string) (string, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return "", err
+	}
+	linked := false
+	for _, l := range s.LinkedRepos {
+		linked = linked || l.Backup != nil
+	}
+	if s.Backup == nil && !linked {
+		return "", fmt.Errorf("session %s has no backup", id)
+	}
+	if s.Status != session.StatusStopped && s.Status != session.StatusErrored {
+		return "", fmt.Errorf("stop the session before restoring its changes")
+	}
+
+	path := s.WorktreePath
+	if s.Backup != nil {
+		if path, err = m.restoreBackup(s.RepoPath, path, s.Branch, *s.Backup); err != nil {
+			return "", err
+		}
+		if err := m.sessionManager.AttachSessionWorktree(id, path); err != nil {
+			return "", err
+		}
+		_ = m.worktreeManager.DeleteBackup(s.RepoPath, *s.Backup)
+	}
+	for _, l := range s.LinkedRepos {
+		if l.Backup == nil {
+			continue
+		}
+		linkedPath, err := m.restoreBackup(l.RepoPath, l.WorktreePath, s.Branch, *l.Backup)
+		if err != nil {
+			return path, fmt.Errorf("%s: %w", filepath.Base(l.RepoPath), err)
+		}
+		if err := m.sessionManager.AttachLinkedWorktree(id, l.RepoPath, linkedPath); err != nil {
+			return path, err
+		}
+		_ = m.worktreeManager.DeleteBackup(l.RepoPath, *l.Backup)
+	}
+	return path, nil
+}
+
+// restoreBackup applies b to the worktree of repoPath at path, recreating it
+// on the backed-up branch (or branch) if it's gone, and returns its path.
+func (m *Manager) restoreBackup(repoPath, path, branch string, b worktree.Backup) (string, error) {
+	if _, err := os.Stat(path); path == "" || err != nil {
+		if b.Branch != "" && b.Branch != "HEAD" {
+			branch = b.Branch
+		}
+		if path, err = m.worktreeManager.CreateWorktree(repoPath, branch); err != nil {
+			return "", fmt.Errorf("recreate worktree: %w", err)
+		}
+	}
+	if err := m.worktreeManager.RestoreBackup(path, b); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,191 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Clone job states.
+const (
+	CloneRunning   = "running"
+	CloneDone      = "done"
+	CloneFailed    = "failed"
+	CloneCancelled = "cancelled"
+)
+
+// CloneJob is a clone running in the background. Updates are emitted on
+// clone:job:<id>.
+type CloneJob struct {
+	ID          string                 `json:"id"`
+	RepoURL     string                 `json:"repoUrl"`
+	Path        string                 `json:"path"`
+	Status      string                 `json:"status"`
+	Progress    worktree.CloneProgress `json:"progress"`
+	WorkspaceID string                 `json:"workspaceId,omitempty"` // set once the clone is registered
+	Error       string                 `json:"error,omitempty"`
+	StartedAt   time.Time              `json:"startedAt"`
+
+	config AddWorkspaceConfig
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartClone clones config.RepoURL in the background and registers it as a
+// workspace when done. It returns the job ID to follow and cancel it.
+func (m *Manager) StartClone(config AddWorkspaceConfig) (string, error) {
+	if config.RepoURL == "" {
+		return "", fmt.Errorf("repoUrl is required")
+	}
+	if err := config.Clone.Validate(); err != nil {
+		return "", fmt.Errorf("invalid clone options: %w", err)
+	}
+	destPath, err := m.worktreeManager.CloneDestPath(config.RepoURL, config.ReposBaseDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve clone path: %w", err)
+	}
+	if _, err := os.Stat(destPath); err == nil {
+		return "", fmt.Errorf("%s already exists", destPath)
+	}
+
+	m.mu.Lock()
+	for _, j := range m.clones {
+		if j.Path == destPath && j.Status == CloneRunning {
+			m.mu.Unlock()
+			return "", fmt.Errorf("%s is already being cloned", destPath)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &CloneJob{
+		ID:        uuid.New().String(),
+		RepoURL:   config.RepoURL,
+		Path:      destPath,
+		Status:    CloneRunning,
+		StartedAt: time.Now(),
+		config:    config,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	m.clones[job.ID] = job
+	m.mu.Unlock()
+
+	go m.runClone(ctx, job)
+	return job.ID, nil
+}
+
+// CancelClone stops a running clone and removes its partial directory.
+func (m *Manager) CancelClone(jobID string) error {
+	m.mu.RLock()
+	job, ok := m.clones[jobID]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("clone %s not found", jobID)
+	}
+	job.cancel()
+	<-job.done
+	return nil
+}
+
+// ListCloneJobs returns clones that are running or finished this session.
+func (m *Manager) ListCloneJobs() []CloneJob {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]CloneJob, 0, len(m.clones))
+	for _, j := range m.clones {
+		result = append(result, *j)
+	}
+	return result
+}
+
+// CloneAndAddWorkspace clones a git repo then registers it as a workspace,
+// blocking until done.
+func (m *Manager) CloneAndAddWorkspace(config AddWorkspaceConfig) (string, error) {
+	jobID, err := m.StartClone(config)
+	if err != nil {
+		return "", err
+	}
+	m.mu.RLock()
+	job := m.clones[jobID]
+	m.mu.RUnlock()
+	<-job.done
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if job.Status != CloneDone {
+		return "", fmt.Errorf("%s", job.Error)
+	}
+	return job.WorkspaceID, nil
+}
+
+func (m *Manager) runClone(ctx context.Context, job *CloneJob) {
+	defer close(job.done)
+	defer job.cancel()
+
+	err := os.MkdirAll(filepath.Dir(job.Path), 0755)
+	if err == nil {
+		err = worktree.Clone(ctx, job.RepoURL, job.Path, m.cloneOptions(job.config), func(p worktree.CloneProgress) {
+			m.mu.Lock()
+			changed := p != job.Progress
+			job.Progress = p
+			m.mu.Unlock()
+			if changed {
+				m.emitClone(job)
+			}
+		})
+	}
+
+	var wsID string
+	if err == nil {
+		config := job.config
+		config.Path = job.Path
+		config.RepoURL = ""
+		if wsID, err = m.AddWorkspace(config); err == nil {
+			m.mu.Lock()
+			if w, ok := m.workspaces[wsID]; ok {
+				w.Cloned = true
+			}
+			m.mu.Unlock()
+			m.save()
+		}
+	}
+
+	m.mu.Lock()
+	switch {
+	case ctx.Err() != nil && err != nil:
+		job.Status, job.Error = CloneCancelled, "clone cancelled"
+	case err != nil:
+		job.Status, job.Error = CloneFailed, err.Error()
+	default:
+		job.Status, job.WorkspaceID = CloneDone, wsID
+	}
+	m.mu.Unlock()
+	m.emitClone(job)
+}
+
+// cloneOptions fills in the object cache location from settings.
+func (m *Manager) cloneOptions(config AddWorkspaceConfig) worktree.CloneOptions {
+	opts := config.Clone
+	if opts.UseCache {
+		opts.CacheDir = m.settingsManager.GetSettings().CloneCacheDir
+		if opts.CacheDir == "" {
+			home, _ := os.UserHomeDir()
+			opts.CacheDir = filepath.Join(home, ".aim", "cache")
+		}
+	}
+	return opts
+}
+
+func (m *Manager) emitClone(job *CloneJob) {
+	m.mu.RLock()
+	snapshot := *job
+	m.mu.RUnlock()
+	if m.ctx != nil {
+		runtime.EventsEmit(m.ctx, "clone:job:"+job.ID, snapshot)
+	}
+}
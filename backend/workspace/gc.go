@@ -0,0 +1,278 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// Reasons a worktree can be reclaimed.
+const (
+	GCOrphaned = "orphaned" // aim created it but no session uses it
+	GCPrunable = "prunable" // git still lists it but its directory is gone
+	GCMerged   = "merged"   // an archived session's branch is already on its base
+	GCLeftover = "leftover" // a directory in aim's worktree location git doesn't know about
+)
+
+// GCItem is a worktree (or leftover directory) that can be reclaimed.
+type GCItem struct {
+	Path      string `json:"path"` // identifies the item when collecting
+	RepoPath  string `json:"repoPath"`
+	Branch    string `json:"branch,omitempty"`
+	Reason    string `json:"reason"`
+	Detail    string `json:"detail,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	Bytes     int64  `json:"bytes"`
+	Dirty     bool   `json:"dirty"` // has uncommitted changes, backed up before removal
+	Merged    bool   `json:"merged"`
+}
+
+// GCReport is the result of a dry run.
+type GCReport struct {
+	Items      []GCItem `json:"items"`
+	TotalBytes int64    `json:"totalBytes"`
+}
+
+// GCResult is the outcome of collecting one item.
+type GCResult struct {
+	Path    string           `json:"path"`
+	Removed bool             `json:"removed"`
+	Freed   int64            `json:"freed"`
+	Backup  *worktree.Backup `json:"backup,omitempty"` // uncommitted changes saved before removal
+	Trash   string           `json:"trash,omitempty"`  // where a leftover directory was moved
+	Error   string           `json:"error,omitempty"`
+}
+
+// ScanWorktrees finds reclaimable worktrees across every known repository
+// without changing anything. Worktrees aim didn't create, locked worktrees
+// and those of live sessions are never reported.
+func (m *Manager) ScanWorktrees() GCReport {
+	sessions := m.sessionManager.ListSessions()
+	used := make(map[string]session.SessionState)
+	for _, s := range sessions {
+		paths := []string{s.WorktreePath, s.Directory}
+		for _, l := range s.LinkedRepos {
+			paths = append(paths, l.WorktreePath)
+		}
+		for _, p := range paths {
+			if p != "" {
+				used[filepath.Clean(p)] = s
+			}
+		}
+	}
+
+	// Clones of one remote shared a worktree directory before it was keyed
+	// by repository path, so every repository's worktrees count as registered.
+	trees := make(map[string][]worktree.WorktreeInfo)
+	registered := make(map[string]bool)
+	for _, repo := range m.knownRepos(sessions) {
+		list, err := m.worktreeManager.ListWorktrees(repo)
+		if err != nil {
+			continue
+		}
+		trees[repo] = list
+		for _, t := range list {
+			registered[filepath.Clean(t.Path)] = true
+		}
+	}
+
+	report := GCReport{Items: []GCItem{}}
+	for repo, list := range trees {
+		for _, item := range m.scanRepo(repo, list, used, registered) {
+			report.Items = append(report.Items, item)
+			report.TotalBytes += item.Bytes
+		}
+	}
+	sort.Slice(report.Items, func(i, j int) bool { return report.Items[i].Bytes > report.Items[j].Bytes })
+	return report
+}
+
+// CollectWorktrees removes the items at paths found by a fresh scan.
+// Uncommitted changes are backed up first. Merged branches are deleted
+// along with their worktrees. Leftover directories aren't git worktrees, so
+// nothing can back them up; they are moved to the trash instead.
+func (m *Manager) CollectWorktrees(paths []string) []GCResult {
+	items := make(map[string]GCItem)
+	for _, item := range m.ScanWorktrees().Items {
+		items[item.Path] = item
+	}
+
+	results := make([]GCResult, 0, len(paths))
+	for _, path := range paths {
+		r := GCResult{Path: path}
+		item, ok := items[filepath.Clean(path)]
+		if !ok {
+			r.Error = "no longer reclaimable"
+			results = append(results, r)
+			continue
+		}
+		var err error
+		switch {
+		case item.Reason == GCPrunable:
+			err = m.worktreeManager.PruneWorktree(item.RepoPath, item.Path)
+		case item.Reason == GCLeftover:
+			r.Trash, err = m.moveToTrash(item.Path)
+		default:
+			r.Backup, err = m.worktreeManager.RemoveWorktree(item.RepoPath, item.Path)
+			if err == nil && item.Merged && !item.Dirty && item.Branch != "" {
+				_ = m.worktreeManager.DeleteBranch(item.RepoPath, item.Branch)
+			}
+		}
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Removed, r.Freed = true, item.Bytes
+			if item.SessionID != "" {
+				_ = m.sessionManager.ForgetSessionWorktree(item.SessionID, item.Path, r.Backup)
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// moveToTrash moves path into the trash directory under aim's root, where
+// the user can recover or delete it.
+func (m *Manager) moveToTrash(path string) (string, error) {
+	dir := filepath.Join(m.locator.Root(), "trash")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create trash: %w", err)
+	}
+	dest := filepath.Join(dir, time.Now().Format("20060102-150405")+"-"+filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("move to trash: %w", err)
+	}
+	return dest, nil
+}
+
+// knownRepos returns every repository registered as a workspace or used by a session.
+func (m *Manager) knownRepos(sessions []session.SessionState) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	add := func(p string) {
+		if p == "" {
+			return
+		}
+		p = filepath.Clean(p)
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		if m.worktreeManager.IsGitRepo(p) {
+			repos = append(repos, p)
+		}
+	}
+	m.mu.RLock()
+	for _, ws := range m.workspaces {
+		add(ws.Path)
+		for _, repo := range ws.Repos {
+			add(repo)
+		}
+	}
+	m.mu.RUnlock()
+	for _, s := range sessions {
+		add(s.RepoPath)
+		for _, l := range s.LinkedRepos {
+			add(l.RepoPath)
+		}
+	}
+	return repos
+}
+
+func (m *Manager) scanRepo(repo string, trees []worktree.WorktreeInfo, used map[string]session.SessionState, registered map[string]bool) []GCItem {
+	managed := m.worktreeManager.ManagedDirs(repo)
+	var items []GCItem
+	for i, t := range trees {
+		path := filepath.Clean(t.Path)
+		if i == 0 || t.Locked {
+			continue // the main checkout, or locked on purpose
+		}
+		if !within(path, managed) {
+			continue
+		}
+		item := GCItem{Path: path, RepoPath: repo, Branch: t.Branch}
+		if t.Prunable != "" {
+			item.Reason, item.Detail = GCPrunable, t.Prunable
+			items = append(items, item)
+			continue
+		}
+		s, inUse := used[path]
+		if inUse && !s.Archived {
+			continue
+		}
+		item.Bytes = worktree.DirSize(path)
+		if st, err := m.worktreeManager.GetGitStatus(path); err == nil {
+			item.Dirty = st.Dirty
+		}
+		if base := worktree.ResolveBase(path, t.Branch); base != "" {
+			item.Merged, _ = worktree.IsMerged(path, worktree.SyncRef(path, base))
+			if item.Merged {
+				item.Detail = "merged into " + worktree.SyncRef(path, base)
+			}
+		}
+		switch {
+		case !inUse:
+			item.Reason = GCOrphaned
+		case item.Merged:
+			item.Reason, item.SessionID = GCMerged, s.ID
+		default:
+			continue // archived but unmerged; left to the archive cleanup
+		}
+		items = append(items, item)
+	}
+
+	for _, dir := range managed {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if !e.IsDir() || registered[path] || linkedWorktree(path) {
+				continue
+			}
+			if _, inUse := used[path]; inUse {
+				continue
+			}
+			items = append(items, GCItem{
+				Path:     path,
+				RepoPath: repo,
+				Reason:   GCLeftover,
+				Detail:   "not a registered worktree",
+				Bytes:    worktree.DirSize(path),
+			})
+		}
+	}
+	return items
+}
+
+// within reports whether path is inside one of dirs.
+func within(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkedWorktree reports whether path is a worktree of a repository that
+// still exists, even one aim no longer knows about.
+func linkedWorktree(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return false
+	}
+	_, err = os.Stat(gitDir)
+	return err == nil
+}
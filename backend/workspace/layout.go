@@ -0,0 +1,100 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Benbentwo/aim/backend/session"
+)
+
+// WorktreeMigration is the outcome of moving one worktree to its current location.
+type WorktreeMigration struct {
+	SessionID string `json:"sessionId"`
+	Branch    string `json:"branch"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Moved     bool   `json:"moved"`
+	Skipped   string `json:"skipped,omitempty"` // why the worktree was left in place
+	Error     string `json:"error,omitempty"`
+}
+
+// SetWorkspaceWorktreeRoot sets where new worktrees of a workspace are
+// created. Pass "" to use the global setting. Existing worktrees stay where
+// they are until MigrateWorktrees is run.
+func (m *Manager) SetWorkspaceWorktreeRoot(id string, root string) error {
+	if root != "" && !filepath.IsAbs(root) && !strings.HasPrefix(root, "~") {
+		return fmt.Errorf("worktree root must be an absolute path")
+	}
+	m.mu.Lock()
+	ws, ok := m.workspaces[id]
+	if ok {
+		ws.WorktreeRoot = root
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	m.save()
+	return nil
+}
+
+// MigrateWorktrees moves session worktrees that aren't where the current
+// worktree root and naming scheme put them. Worktrees in use by a running
+// session are skipped; stop the session and run the migration again.
+func (m *Manager) MigrateWorktrees() []WorktreeMigration {
+	results := []WorktreeMigration{}
+	moved := make(map[string]string) // old path -> new path
+	for _, s := range m.sessionManager.ListSessions() {
+		if s.WorktreePath == "" || s.RepoPath == "" {
+			continue
+		}
+		if _, ok := moved[s.WorktreePath]; ok {
+			continue // shares a worktree already moved with another session
+		}
+		if _, err := os.Stat(s.WorktreePath); err != nil {
+			continue
+		}
+		to := m.worktreeManager.WorktreePath(s.RepoPath, s.Branch)
+		if filepath.Clean(to) == filepath.Clean(s.WorktreePath) {
+			continue
+		}
+		r := WorktreeMigration{SessionID: s.ID, Branch: s.Branch, From: s.WorktreePath, To: to}
+		if s.Status != session.StatusStopped && s.Status != session.StatusErrored {
+			r.Skipped = "session is running"
+			results = append(results, r)
+			continue
+		}
+		if err := m.worktreeManager.MoveWorktree(s.RepoPath, s.WorktreePath, to); err != nil {
+			r.Error = err.Error()
+			results = append(results, r)
+			continue
+		}
+		if err := m.sessionManager.SetSessionWorktreePath(s.ID, to); err != nil {
+			// Another session started in the worktree meanwhile; put it back.
+			_ = m.worktreeManager.MoveWorktree(s.RepoPath, to, s.WorktreePath)
+			r.Skipped = err.Error()
+			results = append(results, r)
+			continue
+		}
+		moved[s.WorktreePath] = to
+		r.Moved = true
+		results = append(results, r)
+	}
+	return results
+}
+
+// worktreeRoot resolves the worktree root for the repository at repoPath:
+// the owning workspace's override, else the global setting.
+func (m *Manager) worktreeRoot(repoPath string) string {
+	m.mu.RLock()
+	for _, ws := range m.workspaces {
+		if ws.WorktreeRoot != "" && filepath.Clean(ws.Path) == filepath.Clean(repoPath) {
+			m.mu.RUnlock()
+			return ws.WorktreeRoot
+		}
+	}
+	m.mu.RUnlock()
+	return m.settingsManager.GetSettings().WorktreeRoot
+}
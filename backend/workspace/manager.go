@@ -8,24 +8,68 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/Benbentwo/aim/backend/config"
+	"github.com/Benbentwo/aim/backend/sandbox"
 	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/settings"
+	"github.com/Benbentwo/aim/backend/storage"
 	"github.com/Benbentwo/aim/backend/worktree"
 	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// workspacesSchema versions workspaces.json.
+var workspacesSchema = storage.Schema{
+	Name:    "workspaces.json",
+	Version: 1,
+	Migrations: []storage.Migration{
+		{
+			From:        0,
+			Description: "default missing agent to claude",
+			Apply: func(data json.RawMessage) (json.RawMessage, error) {
+				return storage.EachObject(data, func(obj map[string]json.RawMessage) error {
+					if string(obj["agent"]) == `""` {
+						delete(obj, "agent")
+					}
+					return storage.SetDefault(obj, "agent", "claude")
+				})
+			},
+		},
+	},
+}
+
 // Workspace represents a git repository registered with aim.
 type Workspace struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Path   string `json:"path"`
-	Agent  string `json:"agent"`
-	Cloned bool   `json:"cloned"`
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Path    string          `json:"path"`
+	Agent   string          `json:"agent"`
+	Cloned  bool            `json:"cloned"`
+	Sandbox *sandbox.Policy `json:"sandbox,omitempty"`
+
+	// WorktreeRoot overrides the global worktree root for this workspace.
+	WorktreeRoot string `json:"worktreeRoot,omitempty"`
+	// Bootstrap prepares each new worktree before its agent starts.
+	Bootstrap *worktree.Bootstrap `json:"bootstrap,omitempty"`
+	// Repos are further repositories grouped with Path in a multi-repo
+	// workspace. Tasks get a worktree in each, all on the same branch.
+	Repos []string `json:"repos,omitempty"`
+	// Overrides take precedence over the repository's checked-in config.
+	Overrides *RepoConfig `json:"overrides,omitempty"`
+	// TrustedConfig is the hash of the repository config the user allowed to
+	// run setup commands and set env.
+	TrustedConfig string `json:"trustedConfig,omitempty"`
+	// TrustedRepoConfigs holds the same for each of Repos, by path.
+	TrustedRepoConfigs map[string]string `json:"trustedRepoConfigs,omitempty"`
 }
 
 // WorkspaceWithSessions is returned to the frontend.
 type WorkspaceWithSessions struct {
 	Workspace
 	Sessions []session.SessionState `json:"sessions"`
+	// ConfigNeedsTrust is set while the repository config has setup
+	// commands or env the user hasn't approved.
+	ConfigNeedsTrust bool `json:"configNeedsTrust"`
 }
 
 // AddWorkspaceConfig is sent from the frontend.
@@ -35,6 +79,11 @@ type AddWorkspaceConfig struct {
 	ReposBaseDir string `json:"reposBaseDir"`
 	Name         string `json:"name"`
 	Agent        string `json:"agent"`
+
+	// Clone tunes the clone when RepoURL is set.
+	Clone worktree.CloneOptions `json:"clone"`
+	// Repos makes a multi-repo workspace grouping these repositories with Path.
+	Repos []string `json:"repos,omitempty"`
 }
 
 // Manager manages workspaces (registered repositories).
@@ -42,19 +91,27 @@ type Manager struct {
 	mu              sync.RWMutex
 	ctx             context.Context
 	workspaces      map[string]*Workspace
-	confPath        string
+	locator         *config.Locator
 	sessionManager  *session.Manager
 	worktreeManager *worktree.Manager
+	settingsManager *settings.Manager
+	issueLinker     IssueLinker
+	clones          map[string]*CloneJob
+	repoConfigs     repoConfigCache
 }
 
-func NewManager(sessionMgr *session.Manager, worktreeMgr *worktree.Manager) *Manager {
-	confDir, _ := os.UserConfigDir()
-	return &Manager{
+func NewManager(locator *config.Locator, sessionMgr *session.Manager, worktreeMgr *worktree.Manager, settingsMgr *settings.Manager) *Manager {
+	m := &Manager{
 		workspaces:      make(map[string]*Workspace),
-		confPath:        filepath.Join(confDir, "aim", "workspaces.json"),
+		locator:         locator,
 		sessionManager:  sessionMgr,
 		worktreeManager: worktreeMgr,
+		settingsManager: settingsMgr,
+		clones:          make(map[string]*CloneJob),
 	}
+	sessionMgr.SetLaunchOptionsFunc(m.launchOptions)
+	worktreeMgr.SetRootFunc(m.worktreeRoot)
+	return m
 }
 
 func (m *Manager) SetContext(ctx context.Context) {
@@ -62,6 +119,14 @@ func (m *Manager) SetContext(ctx context.Context) {
 	m.load()
 }
 
+// Reload discards in-memory workspaces and reloads them from the active profile.
+func (m *Manager) Reload() {
+	m.mu.Lock()
+	m.workspaces = make(map[string]*Workspace)
+	m.mu.Unlock()
+	m.load()
+}
+
 // AddWorkspace registers a local directory as a workspace and creates its initial session.
 func (m *Manager) AddWorkspace(config AddWorkspaceConfig) (string, error) {
 	if config.Path == "" {
@@ -76,16 +141,30 @@ func (m *Manager) AddWorkspace(config AddWorkspaceConfig) (string, error) {
 	if name == "" {
 		name = filepath.Base(config.Path)
 	}
+	// An agent picked when adding the workspace wins over the repository's.
+	var overrides *RepoConfig
+	repoConfig, _, _, _ := m.repoConfigs.load(config.Path)
 	agent := config.Agent
-	if agent == "" {
+	switch {
+	case repoConfig != nil && repoConfig.Agent != "" && agent == "":
+		agent = repoConfig.Agent
+	case repoConfig != nil && repoConfig.Agent != "" && agent != repoConfig.Agent:
+		overrides = &RepoConfig{Agent: agent}
+	case agent == "":
 		agent = "claude"
 	}
+	repos, err := m.memberRepos(config.Path, config.Repos)
+	if err != nil {
+		return "", err
+	}
 
 	ws := &Workspace{
 		ID:    id,
 		Name:  name,
 		Path:  config.Path,
-		Agent: agent,
+		Agent:     agent,
+		Repos:     repos,
+		Overrides: overrides,
 	}
 
 	m.mu.Lock()
@@ -93,7 +172,7 @@ func (m *Manager) AddWorkspace(config AddWorkspaceConfig) (string, error) {
 	m.mu.Unlock()
 
 	// Create the initial session for this workspace
-	_, err := m.sessionManager.CreateSession(session.SessionConfig{
+	_, err = m.sessionManager.CreateSession(session.SessionConfig{
 		Name:        name,
 		Agent:       agent,
 		Directory:   config.Path,
@@ -110,48 +189,15 @@ func (m *Manager) AddWorkspace(config AddWorkspaceConfig) (string, error) {
 	return id, nil
 }
 
-// CloneAndAddWorkspace clones a git repo then registers it as a workspace.
-func (m *Manager) CloneAndAddWorkspace(config AddWorkspaceConfig) (string, error) {
-	if config.RepoURL == "" {
-		return "", fmt.Errorf("repoUrl is required")
-	}
-
-	destPath, err := m.worktreeManager.CloneDestPath(config.RepoURL, config.ReposBaseDir)
-	if err != nil {
-		return "", fmt.Errorf("resolve clone path: %w", err)
-	}
-
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
-		return "", fmt.Errorf("create parent dir: %w", err)
-	}
-
-	if err := m.worktreeManager.CloneRepo(config.RepoURL, destPath); err != nil {
-		return "", err
-	}
-
-	config.Path = destPath
-	config.RepoURL = ""
-
-	wsID, err := m.AddWorkspace(config)
-	if err != nil {
-		return "", err
-	}
-
-	// Mark as cloned
-	m.mu.Lock()
-	if w, ok := m.workspaces[wsID]; ok {
-		w.Cloned = true
-	}
-	m.mu.Unlock()
-	m.save()
-
-	return wsID, nil
-}
-
-// ListWorkspaces returns all workspaces with their sessions.
+// ListWorkspaces returns all workspaces with their sessions. Agent is the
+// effective agent, which the repository config may set.
 func (m *Manager) ListWorkspaces() []WorkspaceWithSessions {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	snapshots := make([]Workspace, 0, len(m.workspaces))
+	for _, ws := range m.workspaces {
+		snapshots = append(snapshots, *ws)
+	}
+	m.mu.RUnlock()
 
 	allSessions := m.sessionManager.ListSessions()
 	byWorkspace := make(map[string][]session.SessionState)
@@ -159,11 +205,14 @@ func (m *Manager) ListWorkspaces() []WorkspaceWithSessions {
 		byWorkspace[s.WorkspaceID] = append(byWorkspace[s.WorkspaceID], s)
 	}
 
-	result := make([]WorkspaceWithSessions, 0, len(m.workspaces))
-	for _, ws := range m.workspaces {
+	result := make([]WorkspaceWithSessions, 0, len(snapshots))
+	for _, w := range snapshots {
+		eff := m.effectiveConfig(w)
+		w.Agent = eff.Agent
 		result = append(result, WorkspaceWithSessions{
-			Workspace: *ws,
-			Sessions:  byWorkspace[ws.ID],
+			Workspace:        w,
+			Sessions:         byWorkspace[w.ID],
+			ConfigNeedsTrust: eff.needsTrust(),
 		})
 	}
 	return result
@@ -184,18 +233,115 @@ func (m *Manager) RemoveWorkspace(id string) error {
 	return nil
 }
 
+// SetWorkspaceSandbox sets the sandbox policy for sessions launched in a
+// workspace. Pass nil to remove it. Running sessions keep their current policy.
+func (m *Manager) SetWorkspaceSandbox(id string, policy *sandbox.Policy) error {
+	if policy != nil && policy.Enabled {
+		if err := sandbox.Available(); err != nil {
+			return err
+		}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	ws, ok := m.workspaces[id]
+	if ok {
+		ws.Sandbox = policy
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	m.save()
+	return nil
+}
+
+// SetWorkspaceBootstrap sets the files copied or linked into new worktrees
+// of a workspace and the setup commands run before the agent starts. Pass
+// nil to remove it.
+func (m *Manager) SetWorkspaceBootstrap(id string, b *worktree.Bootstrap) error {
+	if b != nil {
+		if err := b.Validate(); err != nil {
+			return err
+		}
+		if b.Empty() {
+			b = nil
+		}
+	}
+	m.mu.Lock()
+	ws, ok := m.workspaces[id]
+	if ok {
+		ws.Bootstrap = b
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	m.save()
+	return nil
+}
+
+// SandboxSupport returns why sandboxing is unavailable on this machine, or "" if it can be used.
+func (m *Manager) SandboxSupport() string {
+	if err := sandbox.Available(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// launchOptions resolves per-workspace launch settings for the session manager.
+func (m *Manager) launchOptions(workspaceID string) session.LaunchOptions {
+	m.mu.RLock()
+	ws, ok := m.workspaces[workspaceID]
+	var snapshot Workspace
+	if ok {
+		snapshot = *ws
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return session.LaunchOptions{}
+	}
+	var opts session.LaunchOptions
+	if snapshot.Sandbox != nil {
+		policy := *snapshot.Sandbox
+		opts.Sandbox = &policy
+	}
+	eff := m.effectiveConfig(snapshot)
+	if eff.Setup != nil {
+		b := *eff.Setup
+		opts.Bootstrap = &b
+	}
+	for _, r := range eff.Repos {
+		if opts.RepoBootstraps == nil {
+			opts.RepoBootstraps = make(map[string]*worktree.Bootstrap)
+		}
+		opts.RepoBootstraps[r.Path] = r.Setup
+	}
+	opts.Env = eff.Env
+	if eff.TestCommand != "" {
+		opts.Env = mergeMap(eff.Env, map[string]string{"AIM_TEST_COMMAND": eff.TestCommand})
+	}
+	opts.BranchPrefix = eff.BranchPrefix
+	return opts
+}
+
 // CloneDestPreview returns the expected clone destination path without cloning.
 func (m *Manager) CloneDestPreview(repoURL string, reposBaseDir string) (string, error) {
 	return m.worktreeManager.CloneDestPath(repoURL, reposBaseDir)
 }
 
+func (m *Manager) confPath() string {
+	return m.locator.Path("workspaces.json")
+}
+
 func (m *Manager) load() {
-	data, err := os.ReadFile(m.confPath)
-	if err != nil {
-		return
-	}
 	var workspaces []Workspace
-	if err := json.Unmarshal(data, &workspaces); err != nil {
+	rec, err := workspacesSchema.Load(m.confPath(), &workspaces)
+	if rec != nil {
+		runtime.EventsEmit(m.ctx, storage.RecoveredEvent, rec)
+	}
+	if err != nil {
 		return
 	}
 	m.mu.Lock()
@@ -214,12 +360,5 @@ func (m *Manager) save() {
 	}
 	m.mu.RUnlock()
 
-	if err := os.MkdirAll(filepath.Dir(m.confPath), 0755); err != nil {
-		return
-	}
-	data, err := json.MarshalIndent(workspaces, "", "  ")
-	if err != nil {
-		return
-	}
-	_ = os.WriteFile(m.confPath, data, 0644)
+	_ = workspacesSchema.Save(m.confPath(), workspaces)
 }
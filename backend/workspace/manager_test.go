@@ -0,0 +1,30 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspacesSchemaFromV0(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workspaces.json")
+	v0 := `[
+		{"id": "a", "name": "a", "path": "/a"},
+		{"id": "b", "name": "b", "path": "/b", "agent": ""},
+		{"id": "c", "name": "c", "path": "/c", "agent": "codex"}
+	]`
+	if err := os.WriteFile(path, []byte(v0), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var list []Workspace
+	if _, err := workspacesSchema.Load(path, &list); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"claude", "claude", "codex"}
+	for i, ws := range list {
+		if ws.Agent != want[i] {
+			t.Errorf("%s: agent = %q, want %q", ws.ID, ws.Agent, want[i])
+		}
+	}
+}
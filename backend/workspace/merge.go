@@ -0,0 +1,166 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+)
+
+// MergeResult reports the outcome of MergeSessionBranch.
+type MergeResult struct {
+	Merged    bool                `json:"merged"`
+	Branch    string              `json:"branch"`
+	Base      string              `json:"base"`
+	Strategy  string              `json:"strategy"`
+	Commit    string              `json:"commit,omitempty"` // new tip of base when merged
+	Conflicts []worktree.Conflict `json:"conflicts"`
+}
+
+// MergeSessionBranch lands a session's branch on the branch it was created
+// from using strategy ("merge", "squash" or "rebase"). A dry run runs first;
+// if it finds conflicts nothing is changed and they are returned with
+// Merged false, so the caller can offer StartConflictResolution.
+func (m *Manager) MergeSessionBranch(id string, strategy string) (MergeResult, error) {
+	if strategy == "" {
+		strategy = worktree.StrategyMerge
+	}
+	// Merge and squash only write objects and refs; a rebase rewrites the
+	// session's worktree.
+	s, base, err := m.mergeTarget(id, strategy == worktree.StrategyRebase)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	result := MergeResult{Branch: s.Branch, Base: base, Strategy: strategy}
+
+	check, err := m.worktreeManager.CheckMerge(s.RepoPath, s.Branch, base)
+	if err != nil {
+		return result, err
+	}
+	if !check.Clean() {
+		result.Conflicts = check.Conflicts
+		return result, nil
+	}
+	if check.Commits == 0 {
+		return result, fmt.Errorf("%s has no commits that aren't on %s", s.Branch, base)
+	}
+
+	result.Commit, err = m.worktreeManager.MergeBranch(s.RepoPath, s.WorktreePath, s.Branch, base, strategy, mergeMessage(s, base, strategy))
+	if err != nil {
+		return result, err
+	}
+	result.Merged = true
+	return result, nil
+}
+
+// StartConflictResolution brings the base branch into the session's worktree
+// (merging it, or starting a rebase onto it) so the conflicts appear there,
+// and starts a new agent session in that worktree to resolve them. It
+// returns the new session's ID.
+func (m *Manager) StartConflictResolution(id string, strategy string) (string, error) {
+	s, base, err := m.mergeTarget(id, true)
+	if err != nil {
+		return "", err
+	}
+	// The new session works in the same worktree, so the old one must not.
+	if s.Status != session.StatusStopped && s.Status != session.StatusErrored {
+		return "", fmt.Errorf("stop session %s before resolving its conflicts in another", s.Name)
+	}
+	check, err := m.worktreeManager.CheckMerge(s.RepoPath, s.Branch, base)
+	if err != nil {
+		return "", err
+	}
+	if check.Clean() {
+		return "", fmt.Errorf("%s merges cleanly into %s", s.Branch, base)
+	}
+
+	// Both commands fail when they stop with conflicts in the working tree,
+	// which is the point; any other failure leaves nothing to resolve.
+	finish := "commit the merge"
+	want := worktree.OpMerge
+	if strategy == worktree.StrategyRebase {
+		err = m.worktreeManager.StartRebase(s.WorktreePath, base)
+		finish = "run `git rebase --continue` after each step until the rebase completes"
+		want = worktree.OpRebase
+	} else {
+		err = m.worktreeManager.StartMerge(s.WorktreePath, base)
+	}
+	if err != nil && m.worktreeManager.InProgress(s.WorktreePath) != want {
+		return "", err
+	}
+
+	var files []string
+	for _, c := range check.Conflicts {
+		files = append(files, fmt.Sprintf("- %s (%s)", c.Path, c.Kind))
+	}
+	prompt := fmt.Sprintf("Bringing %s into branch %s produced conflicts in:\n%s\n\n"+
+		"Resolve each conflict, keeping the intent of both sides, then %s. Don't push.",
+		base, s.Branch, strings.Join(files, "\n"), finish)
+	if test := m.launchOptions(s.WorkspaceID).Env["AIM_TEST_COMMAND"]; test != "" {
+		prompt += fmt.Sprintf(" Run `%s` to check the result before you finish.", test)
+	}
+
+	return m.sessionManager.CreateSession(session.SessionConfig{
+		Name:            "Resolve conflicts: " + s.Name,
+		Agent:           s.Agent,
+		Directory:       s.WorktreePath,
+		Branch:          s.Branch,
+		WorkspaceID:     s.WorkspaceID,
+		IssueID:         s.IssueID,
+		IssueIdentifier: s.IssueIdentifier,
+		InitialPrompt:   prompt,
+	})
+}
+
+// mergeTarget returns a session with a clean worktree and the local branch
+// it should be merged into. inWorktree says the caller runs git in the
+// worktree, which the agent must not be using.
+func (m *Manager) mergeTarget(id string, inWorktree bool) (session.SessionState, string, error) {
+	s, err := m.sessionManager.GetSession(id)
+	if err != nil {
+		return s, "", err
+	}
+	if s.WorktreePath == "" || s.RepoPath == "" || s.Branch == "" {
+		return s, "", fmt.Errorf("session %s has no worktree branch", id)
+	}
+	// Running git in the worktree while the agent works there would race it.
+	if inWorktree && !worktreeQuiet(s.Status) {
+		return s, "", fmt.Errorf("session %s is %s; wait until it is idle or stop it first", s.Name, s.Status)
+	}
+	if !m.worktreeManager.IsClean(s.WorktreePath) {
+		return s, "", fmt.Errorf("%s has uncommitted changes; commit or discard them first", s.Branch)
+	}
+	base := worktree.ResolveBase(s.WorktreePath, s.Branch)
+	// Land on the local branch even when the base was recorded as remote-tracking.
+	if i := strings.Index(base, "/"); i >= 0 && !m.worktreeManager.BranchExists(s.RepoPath, base) {
+		base = base[i+1:]
+	}
+	if base == "" || !m.worktreeManager.BranchExists(s.RepoPath, base) {
+		return s, "", fmt.Errorf("no local base branch found for %s", s.Branch)
+	}
+	return s, base, nil
+}
+
+// worktreeQuiet reports whether an agent with status is not using its
+// worktree: it has exited, or finished its turn and waits for a prompt.
+func worktreeQuiet(status string) bool {
+	switch status {
+	case session.StatusStopped, session.StatusErrored, session.StatusIdle:
+		return true
+	}
+	return false
+}
+
+func mergeMessage(s session.SessionState, base, strategy string) string {
+	switch strategy {
+	case worktree.StrategySquash:
+		title := s.Name
+		if s.IssueIdentifier != "" && !strings.Contains(title, s.IssueIdentifier) {
+			title = s.IssueIdentifier + ": " + title
+		}
+		return fmt.Sprintf("%s\n\nSquashed from branch %s.", title, s.Branch)
+	default:
+		return fmt.Sprintf("Merge branch '%s' into %s", s.Branch, base)
+	}
+}
@@ -0,0 +1,192 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Benbentwo/aim/backend/session"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/google/uuid"
+)
+
+// Task modes for multi-repo workspaces.
+const (
+	TaskShared  = "shared"   // one session, with the other repositories added to the agent
+	TaskPerRepo = "per-repo" // one session per repository
+)
+
+// TaskConfig starts work in a workspace, with a worktree on the same branch
+// in each of its repositories.
+type TaskConfig struct {
+	WorkspaceID string `json:"workspaceId"`
+	Mode        string `json:"mode"`             // TaskShared (default) or TaskPerRepo
+	Branch      string `json:"branch,omitempty"` // "" = a temporary branch named after the first prompt
+	// InitialPrompt is sent to each new agent as its first message.
+	InitialPrompt   string `json:"initialPrompt,omitempty"`
+	IssueID         string `json:"issueId,omitempty"`
+	IssueIdentifier string `json:"issueIdentifier,omitempty"`
+}
+
+// SetWorkspaceRepos sets the repositories grouped with a workspace's own.
+// Pass none to make it a single-repo workspace again.
+func (m *Manager) SetWorkspaceRepos(id string, repos []string) error {
+	m.mu.RLock()
+	ws, ok := m.workspaces[id]
+	var path string
+	if ok {
+		path = ws.Path
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	repos, err := m.memberRepos(path, repos)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	ws.Repos = repos
+	m.mu.Unlock()
+	m.save()
+	return nil
+}
+
+// StartTask creates a worktree on the same branch in every repository of a
+// workspace and launches sessions in them: one session with access to all
+// worktrees, or one per repository. It returns the new session IDs.
+//
+// In shared mode a temporary branch is renamed in every repository after
+// the first prompt. Per-repo sessions would each pick their own name, so
+// theirs is named up front: after the initial prompt if there is one,
+// otherwise by the naming policy with a random suffix.
+func (m *Manager) StartTask(config TaskConfig) ([]string, error) {
+	m.mu.RLock()
+	ws, ok := m.workspaces[config.WorkspaceID]
+	var snapshot Workspace
+	if ok {
+		snapshot = *ws
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("workspace %s not found", config.WorkspaceID)
+	}
+	repos := append([]string{snapshot.Path}, snapshot.Repos...)
+	for _, repo := range repos {
+		if !m.worktreeManager.IsGitRepo(repo) {
+			return nil, fmt.Errorf("%s is not a git repository", repo)
+		}
+	}
+
+	agent := m.effectiveConfig(snapshot).Agent
+	mode := config.Mode
+	if mode == "" {
+		mode = TaskShared
+	}
+	branch := config.Branch
+	switch {
+	case mode != TaskShared && mode != TaskPerRepo:
+		return nil, fmt.Errorf("unknown task mode %q", mode)
+	case branch == "" && mode == TaskPerRepo && config.InitialPrompt == "":
+		branch = m.sessionManager.UntitledBranchName(snapshot.ID, config.IssueIdentifier)
+	case branch == "" && mode == TaskPerRepo:
+		branch = m.sessionManager.SuggestBranchName(snapshot.ID, config.InitialPrompt, config.IssueIdentifier, agent)
+	case branch == "":
+		branch = session.TempBranchPrefix + uuid.New().String()[:6]
+	}
+
+	// Create every worktree first so a failure leaves nothing half set up.
+	worktrees := make([]string, 0, len(repos))
+	created := make([]bool, 0, len(repos)) // whether the branch is new in each repository
+	for _, repo := range repos {
+		existed := m.worktreeManager.BranchExists(repo, branch)
+		path, err := m.worktreeManager.CreateWorktree(repo, branch)
+		if err != nil {
+			m.removeTaskWorktrees(repos, worktrees, branch, created)
+			return nil, fmt.Errorf("create worktree in %s: %w", filepath.Base(repo), err)
+		}
+		worktrees = append(worktrees, path)
+		created = append(created, !existed)
+	}
+
+	base := session.SessionConfig{
+		Name:            branch,
+		Agent:           agent,
+		UseWorktree:     true,
+		Branch:          branch,
+		WorkspaceID:     snapshot.ID,
+		IssueID:         config.IssueID,
+		IssueIdentifier: config.IssueIdentifier,
+		InitialPrompt:   config.InitialPrompt,
+	}
+	var configs []session.SessionConfig
+	if mode == TaskShared {
+		c := base
+		c.Directory, c.WorktreePath, c.RepoPath = worktrees[0], worktrees[0], repos[0]
+		for i := 1; i < len(repos); i++ {
+			c.LinkedRepos = append(c.LinkedRepos, session.LinkedRepo{RepoPath: repos[i], WorktreePath: worktrees[i]})
+		}
+		configs = append(configs, c)
+	} else {
+		for i := range repos {
+			c := base
+			c.Directory, c.WorktreePath, c.RepoPath = worktrees[i], worktrees[i], repos[i]
+			if len(repos) > 1 {
+				c.Name = fmt.Sprintf("%s — %s", branch, filepath.Base(repos[i]))
+			}
+			configs = append(configs, c)
+		}
+	}
+
+	var ids []string
+	for _, c := range configs {
+		id, err := m.sessionManager.CreateSession(c)
+		if err != nil {
+			for _, created := range ids {
+				_ = m.sessionManager.CloseSession(created)
+			}
+			m.removeTaskWorktrees(repos, worktrees, branch, created)
+			return nil, fmt.Errorf("create session: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// removeTaskWorktrees undoes the worktrees created for a task that failed,
+// and deletes branch where it was created for them.
+func (m *Manager) removeTaskWorktrees(repos []string, worktrees []string, branch string, created []bool) {
+	for i, path := range worktrees {
+		if _, err := worktree.RemoveWorktree(repos[i], path, true); err == nil && created[i] {
+			_ = m.worktreeManager.DeleteBranch(repos[i], branch)
+		}
+	}
+}
+
+// memberRepos validates the repositories grouped with primary, dropping
+// duplicates and primary itself.
+func (m *Manager) memberRepos(primary string, repos []string) ([]string, error) {
+	if len(repos) == 0 {
+		return nil, nil
+	}
+	if !m.worktreeManager.IsGitRepo(primary) {
+		return nil, fmt.Errorf("%s is not a git repository", primary)
+	}
+	seen := map[string]bool{filepath.Clean(primary): true}
+	var result []string
+	for _, repo := range repos {
+		repo = filepath.Clean(repo)
+		if seen[repo] {
+			continue
+		}
+		seen[repo] = true
+		if _, err := os.Stat(repo); err != nil {
+			return nil, fmt.Errorf("directory not found: %s", repo)
+		}
+		if !m.worktreeManager.IsGitRepo(repo) {
+			return nil, fmt.Errorf("%s is not a git repository", repo)
+		}
+		result = append(result, repo)
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Benbentwo/aim/backend/forge"
+	"github.com/Benbentwo/aim/backend/worktree"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// IssueLinker attaches links to issues in an issue tracker.
+type IssueLinker interface {
+	AttachLink(issueID, url, title string) error
+}
+
+// PullRequestOptions is sent from the frontend to open a pull request.
+type PullRequestOptions struct {
+	Remote string `json:"remote"` // default "origin"
+	Base   string `json:"base"`   // default: the branch the worktree was created from
+	Title  string `json:"title"`  // default: the session name
+	Body   string `json:"body"`
+	Draft  bool   `json:"draft"`
+}
+
+// SetIssueLinker sets where pull requests for issue-linked sessions are reported.
+func (m *Manager) SetIssueLinker(l IssueLinker) {
+	m.mu.Lock()
+	m.issueLinker = l
+	m.mu.Unlock()
+}
+
+// CreatePullRequest pushes a session's branch and opens a pull request for
+// it. The URL is stored on the session and, if the session belongs to an
+// issue, attached to that issue.
+func (m *Manager) CreatePullRequest(sessionID string, opts PullRequestOptions) (forge.PullRequest, error) {
+	s, err := m.sessionManager.GetSession(sessionID)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	if s.WorktreePath == "" {
+		return forge.PullRequest{}, fmt.Errorf("session %s has no worktree", sessionID)
+	}
+	if opts.Remote == "" {
+		opts.Remote = "origin"
+	}
+
+	remoteURL, err := worktree.RemoteURL(s.WorktreePath, opts.Remote)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	provider, err := forge.Resolve(remoteURL)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	branch, err := m.worktreeManager.PushBranch(s.WorktreePath, opts.Remote)
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+
+	base := opts.Base
+	if base == "" {
+		base = worktree.ResolveBase(s.WorktreePath, branch)
+		// The forge only knows branch names, not remote-tracking refs.
+		base = strings.TrimPrefix(base, opts.Remote+"/")
+	}
+	if base == "" {
+		return forge.PullRequest{}, fmt.Errorf("no base branch found; choose one")
+	}
+	title := opts.Title
+	if title == "" {
+		title = s.Name
+		if s.IssueIdentifier != "" && !strings.Contains(title, s.IssueIdentifier) {
+			title = s.IssueIdentifier + ": " + title
+		}
+	}
+
+	pr, err := provider.CreatePullRequest(forge.PullRequestSpec{
+		Dir:       s.WorktreePath,
+		RemoteURL: remoteURL,
+		Branch:    branch,
+		Base:      base,
+		Title:     title,
+		Body:      opts.Body,
+		Draft:     opts.Draft,
+	})
+	if err != nil {
+		return forge.PullRequest{}, err
+	}
+	if err := m.sessionManager.SetSessionPullRequest(sessionID, pr.URL); err != nil {
+		return pr, err
+	}
+
+	m.mu.RLock()
+	linker := m.issueLinker
+	m.mu.RUnlock()
+	if s.IssueID != "" && linker != nil {
+		// The PR exists either way, so a failed link is only reported.
+		if err := linker.AttachLink(s.IssueID, pr.URL, title); err != nil && m.ctx != nil {
+			runtime.LogWarningf(m.ctx, "link pull request to %s: %v", s.IssueIdentifier, err)
+			runtime.EventsEmit(m.ctx, "workspace:pr-link-failed", map[string]string{
+				"sessionId": sessionID,
+				"url":       pr.URL,
+				"error":     err.Error(),
+			})
+		}
+	}
+	return pr, nil
+}
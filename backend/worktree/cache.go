@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// The object cache keeps one bare repository per server and repository
+// name, so clones of a remote and of its forks reuse its objects. Each remote
+// URL is fetched into its own ref namespace so every object stays reachable.
+// Clones copy what they need from the cache (git clone --dissociate) rather
+// than borrowing it, so they never depend on it afterwards.
+
+// CachePath returns the cache repository used for repoURL under cacheDir.
+func CachePath(cacheDir string, repoURL string) (string, error) {
+	parsed, err := ParseRepoURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(expandHome(cacheDir), parsed.hostDir(), safeName(parsed.Repo)+".git"), nil
+}
+
+// useCache updates the object cache for repoURL and returns it as a clone
+// reference, or "" if the cache is off or unusable. A broken cache only
+// costs speed, so errors are reported as progress and the clone goes ahead.
+func useCache(ctx context.Context, repoURL string, opts CloneOptions, progress func(CloneProgress)) string {
+	// Shallow and partial clones want less than the cache would fetch.
+	if !opts.UseCache || opts.CacheDir == "" || opts.Depth > 0 || opts.Filter != "" {
+		return ""
+	}
+	parsed, err := ParseRepoURL(repoURL)
+	if err != nil || parsed.Host == "" {
+		return "" // local repositories are cheap to clone already
+	}
+	cache, err := CachePath(opts.CacheDir, repoURL)
+	if err != nil {
+		return ""
+	}
+	if err := updateCache(ctx, cache, repoURL, progress); err != nil {
+		if progress != nil && ctx.Err() == nil {
+			progress(CloneProgress{Phase: fmt.Sprintf("Object cache unavailable (%v)", err)})
+		}
+		return ""
+	}
+	return cache
+}
+
+// updateCache fetches repoURL into the cache repository, creating it first.
+func updateCache(ctx context.Context, cache string, repoURL string, progress func(CloneProgress)) error {
+	if _, err := os.Stat(cache); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(cache), 0755); err != nil {
+			return err
+		}
+		if out, err := exec.CommandContext(ctx, "git", "init", "--bare", "--quiet", cache).CombinedOutput(); err != nil {
+			_ = os.RemoveAll(cache)
+			return fmt.Errorf("git init: %s", out)
+		}
+		// Keep objects a clone may be copying while the cache is updated.
+		for _, kv := range [][2]string{{"gc.pruneExpire", "never"}, {"gc.auto", "0"}} {
+			if _, err := gitOutput(cache, "config", kv[0], kv[1]); err != nil {
+				return fmt.Errorf("git config: %w", err)
+			}
+		}
+	}
+	refspec := fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", shortHash(repoURL))
+	cmd := exec.CommandContext(ctx, "git", "-C", cache, "fetch", "--progress", "--no-tags", repoURL, refspec)
+	return runProgress(ctx, cmd, "Updating cache: ", progress)
+}
@@ -0,0 +1,176 @@
+package worktree
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CloneOptions tune how a repository is cloned.
+type CloneOptions struct {
+	Depth             int    `json:"depth,omitempty"`             // shallow clone with this many commits; 0 = full history
+	Filter            string `json:"filter,omitempty"`            // partial clone filter, e.g. "blob:none"
+	Branch            string `json:"branch,omitempty"`            // branch to check out instead of the remote's default
+	SingleBranch      bool   `json:"singleBranch,omitempty"`      // fetch only Branch (or the default branch)
+	RecurseSubmodules bool   `json:"recurseSubmodules,omitempty"` // clone submodules too
+	UseCache          bool   `json:"useCache,omitempty"`          // copy objects from aim's shared object cache instead of fetching them
+
+	// CacheDir is where the object cache lives, set by the caller when UseCache is on.
+	CacheDir string `json:"-"`
+}
+
+var cloneFilter = regexp.MustCompile(`^(blob:none|blob:limit=\d+[kmg]?|tree:\d+)$`)
+
+// Validate checks the options before anything is run.
+func (o CloneOptions) Validate() error {
+	if o.Depth < 0 {
+		return fmt.Errorf("depth can't be negative")
+	}
+	if o.Filter != "" && !cloneFilter.MatchString(o.Filter) {
+		return fmt.Errorf("unsupported clone filter %q", o.Filter)
+	}
+	if o.Branch != "" {
+		if err := ValidateBranchName(o.Branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// args returns the git clone flags for o.
+func (o CloneOptions) args() []string {
+	var args []string
+	if o.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(o.Depth))
+	}
+	if o.Filter != "" {
+		args = append(args, "--filter="+o.Filter)
+	}
+	if o.Branch != "" {
+		args = append(args, "--branch", o.Branch)
+	}
+	if o.SingleBranch {
+		args = append(args, "--single-branch")
+	} else if o.Depth > 0 {
+		args = append(args, "--no-single-branch") // --depth implies a single branch otherwise
+	}
+	if o.RecurseSubmodules {
+		args = append(args, "--recurse-submodules")
+		if o.Depth > 0 {
+			args = append(args, "--shallow-submodules")
+		}
+	}
+	return args
+}
+
+// CloneProgress is one progress report parsed from git clone.
+type CloneProgress struct {
+	Phase   string `json:"phase"`   // e.g. "Receiving objects", "Resolving deltas"
+	Percent int    `json:"percent"` // 0-100 within the phase
+	Current int64  `json:"current"` // objects done in the phase
+	Total   int64  `json:"total"`   // objects in the phase
+}
+
+var progressLine = regexp.MustCompile(`^(?:remote: )?([A-Za-z][A-Za-z ]*):\s+(\d+)% \((\d+)/(\d+)\)`)
+
+// CloneRepo runs git clone <url> <destPath>.
+func (m *Manager) CloneRepo(repoURL string, destPath string) error {
+	return Clone(context.Background(), repoURL, destPath, CloneOptions{}, nil)
+}
+
+// Clone clones repoURL into destPath, calling progress as git reports it.
+// Cancelling ctx kills git. On failure or cancellation whatever was written
+// to destPath is removed.
+func Clone(ctx context.Context, repoURL string, destPath string, opts CloneOptions, progress func(CloneProgress)) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	_, statErr := os.Stat(destPath)
+	existed := statErr == nil
+
+	args := append([]string{"clone", "--progress"}, opts.args()...)
+	if reference := useCache(ctx, repoURL, opts, progress); reference != "" {
+		args = append(args, "--reference-if-able", reference, "--dissociate")
+	}
+	args = append(args, "--", repoURL, destPath)
+	err := runProgress(ctx, exec.CommandContext(ctx, "git", args...), "", progress)
+	if err != nil && !existed {
+		_ = os.RemoveAll(destPath)
+	}
+	return err
+}
+
+// runProgress runs a git command that reports progress on stderr, prefixing
+// each phase with label.
+func runProgress(ctx context.Context, cmd *exec.Cmd, label string, progress func(CloneProgress)) error {
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("clone cancelled")
+		}
+		return fmt.Errorf("git clone: %w", err)
+	}
+
+	// git redraws progress lines with \r; keep the non-progress lines for errors.
+	var messages []string
+	scanner := bufio.NewScanner(stderr)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Cloning into ") {
+			continue
+		}
+		if p, ok := parseProgress(line); ok {
+			if progress != nil {
+				p.Phase = label + p.Phase
+				progress(p)
+			}
+			continue
+		}
+		messages = append(messages, line)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("clone cancelled")
+		}
+		return fmt.Errorf("git %s failed: %s", cmd.Args[1], strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// parseProgress parses a line like "Receiving objects:  45% (450/1000), 1.2 MiB".
+func parseProgress(line string) (CloneProgress, bool) {
+	match := progressLine.FindStringSubmatch(line)
+	if match == nil {
+		return CloneProgress{}, false
+	}
+	p := CloneProgress{Phase: match[1]}
+	p.Percent, _ = strconv.Atoi(match[2])
+	p.Current, _ = strconv.ParseInt(match[3], 10, 64)
+	p.Total, _ = strconv.ParseInt(match[4], 10, 64)
+	return p, true
+}
+
+// scanProgressLines splits on \n and \r.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,74 @@
+package worktree
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Remote is a configured git remote.
+type Remote struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// CommitWorktree stages every change in worktreePath, including untracked
+// files, and commits it with message. It returns the new commit hash.
+func (m *Manager) CommitWorktree(worktreePath string, message string) (string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", fmt.Errorf("commit message is required")
+	}
+	if out, err := exec.Command("git", "-C", worktreePath, "add", "-A").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git add: %s", out)
+	}
+	if exec.Command("git", "-C", worktreePath, "diff", "--cached", "--quiet").Run() == nil {
+		return "", fmt.Errorf("nothing to commit")
+	}
+	if out, err := exec.Command("git", "-C", worktreePath, "commit", "-m", message).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git commit: %s", out)
+	}
+	return gitOutput(worktreePath, "rev-parse", "HEAD")
+}
+
+// PushBranch pushes the branch checked out in worktreePath to remote
+// ("origin" if empty) and sets it as the upstream. It returns the branch name.
+func (m *Manager) PushBranch(worktreePath string, remote string) (string, error) {
+	if remote == "" {
+		remote = "origin"
+	}
+	branch, err := gitOutput(worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("resolve branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return "", fmt.Errorf("%s has no branch checked out", worktreePath)
+	}
+	cmd := exec.Command("git", "-C", worktreePath, "push", "--set-upstream", remote, "HEAD:refs/heads/"+branch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git push: %s", out)
+	}
+	return branch, nil
+}
+
+// ListRemotes returns the remotes configured for the repository at path.
+func (m *Manager) ListRemotes(path string) ([]Remote, error) {
+	out, err := gitOutput(path, "remote")
+	if err != nil {
+		return nil, fmt.Errorf("git remote: %w", err)
+	}
+	var remotes []Remote
+	for _, name := range strings.Fields(out) {
+		url, _ := RemoteURL(path, name)
+		remotes = append(remotes, Remote{Name: name, URL: url})
+	}
+	return remotes, nil
+}
+
+// RemoteURL returns the fetch URL of remote in the repository at path.
+func RemoteURL(path, remote string) (string, error) {
+	url, err := gitOutput(path, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("git remote get-url %s: %w", remote, err)
+	}
+	return url, nil
+}
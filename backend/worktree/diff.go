@@ -0,0 +1,291 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// baseConfigKey is the per-branch git config entry recording the branch a
+// worktree branch was created from. git branch -m carries it along on rename.
+const baseConfigKey = "aim-base"
+
+// maxPatchBytes bounds the unified diff returned per file.
+const maxPatchBytes = 256 * 1024
+
+// FileChange is one changed file in a worktree diff.
+type FileChange struct {
+	Path      string `json:"path"`
+	OldPath   string `json:"oldPath,omitempty"` // renames and copies
+	Status    string `json:"status"`            // "added", "modified", "deleted", "renamed", "copied", "typechange", "untracked"
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary"`
+	Patch     string `json:"patch"`
+	Truncated bool   `json:"truncated"` // Patch was cut at maxPatchBytes
+}
+
+// WorktreeDiff is everything a worktree changed relative to its base.
+type WorktreeDiff struct {
+	Base       string       `json:"base"`       // ref compared against
+	BaseCommit string       `json:"baseCommit"` // merge-base of Base and HEAD
+	Branch     string       `json:"branch"`
+	Files      []FileChange `json:"files"`     // committed and uncommitted changes to tracked files
+	Untracked  []FileChange `json:"untracked"` // new files not yet added
+	Additions  int          `json:"additions"`
+	Deletions  int          `json:"deletions"`
+}
+
+// GetWorktreeDiff returns the changes in worktreePath since it branched from
+// base, including uncommitted and untracked files. An empty base uses the
+// branch the worktree was created from, falling back to the remote's default
+// branch, then main or master.
+func (m *Manager) GetWorktreeDiff(worktreePath string, base string) (WorktreeDiff, error) {
+	branch, _ := gitOutput(worktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+	if base == "" {
+		base = ResolveBase(worktreePath, branch)
+		if base == "" {
+			return WorktreeDiff{}, fmt.Errorf("no base branch found for %s", worktreePath)
+		}
+	}
+	mergeBase, err := gitOutput(worktreePath, "merge-base", base, "HEAD")
+	if err != nil {
+		return WorktreeDiff{}, fmt.Errorf("git merge-base %s: %w", base, err)
+	}
+
+	d := WorktreeDiff{Base: base, BaseCommit: mergeBase, Branch: branch}
+	d.Files, err = trackedChanges(worktreePath, mergeBase)
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	d.Untracked, err = untrackedChanges(worktreePath)
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	for _, list := range [][]FileChange{d.Files, d.Untracked} {
+		for _, f := range list {
+			d.Additions += f.Additions
+			d.Deletions += f.Deletions
+		}
+	}
+	return d, nil
+}
+
+// ResolveBase returns the ref branch in dir should be compared against, or
+// "" if none can be found.
+func ResolveBase(dir, branch string) string {
+	if branch != "" && branch != "HEAD" {
+		if base, err := gitOutput(dir, "config", "--get", "branch."+branch+"."+baseConfigKey); err == nil && base != "" && refExists(dir, base) {
+			return base
+		}
+	}
+	if ref, err := gitOutput(dir, "rev-parse", "--abbrev-ref", "origin/HEAD"); err == nil && ref != "" {
+		return ref
+	}
+	for _, ref := range []string{"main", "master", "origin/main", "origin/master"} {
+		if refExists(dir, ref) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// recordBase remembers the branch a new worktree branch was created from.
+func recordBase(repoPath, branch, base string) {
+	if base == "" || base == "HEAD" {
+		return
+	}
+	_ = exec.Command("git", "-C", repoPath, "config", "branch."+branch+"."+baseConfigKey, base).Run()
+}
+
+func trackedChanges(dir, mergeBase string) ([]FileChange, error) {
+	status, err := gitRaw(dir, "diff", "-M", "--name-status", "-z", mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("git diff --name-status: %w", err)
+	}
+	numstat, err := gitRaw(dir, "diff", "-M", "--numstat", "-z", mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("git diff --numstat: %w", err)
+	}
+	patch, err := gitRaw(dir, "diff", "-M", "--no-color", "--no-ext-diff", mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	files := parseNameStatus(status)
+	stats := parseNumstat(numstat)
+	patches := splitPatch(patch)
+	for i := range files {
+		f := &files[i]
+		if s, ok := stats[f.Path]; ok {
+			f.Additions, f.Deletions, f.Binary = s.additions, s.deletions, s.binary
+		}
+		// git emits patches in the same order as --name-status.
+		if i < len(patches) {
+			f.Patch, f.Truncated = capPatch(patches[i])
+		}
+	}
+	return files, nil
+}
+
+func untrackedChanges(dir string) ([]FileChange, error) {
+	out, err := gitRaw(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	var files []FileChange
+	for _, path := range strings.Split(string(out), "\x00") {
+		if path == "" {
+			continue
+		}
+		f := FileChange{Path: path, Status: "untracked"}
+		// --no-index exits 1 when the files differ, which they always do here.
+		cmd := exec.Command("git", "-C", dir, "diff", "--no-index", "--no-color", "--numstat", "--", "/dev/null", path)
+		if stat, _ := cmd.Output(); len(stat) > 0 {
+			fields := strings.Fields(string(stat))
+			if len(fields) >= 2 {
+				if fields[0] == "-" {
+					f.Binary = true
+				} else {
+					f.Additions, _ = strconv.Atoi(fields[0])
+				}
+			}
+		}
+		if !f.Binary {
+			cmd = exec.Command("git", "-C", dir, "diff", "--no-index", "--no-color", "--", "/dev/null", path)
+			p, _ := cmd.Output()
+			f.Patch, f.Truncated = capPatch(string(p))
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+var statusNames = map[byte]string{
+	'A': "added",
+	'M': "modified",
+	'D': "deleted",
+	'R': "renamed",
+	'C': "copied",
+	'T': "typechange",
+}
+
+// parseNameStatus parses git diff --name-status -z output.
+func parseNameStatus(out []byte) []FileChange {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	var files []FileChange
+	for i := 0; i < len(fields); i++ {
+		code := fields[i]
+		if code == "" || i+1 >= len(fields) {
+			continue
+		}
+		status, ok := statusNames[code[0]]
+		if !ok {
+			status = "modified"
+		}
+		f := FileChange{Status: status}
+		if code[0] == 'R' || code[0] == 'C' {
+			if i+2 >= len(fields) {
+				break
+			}
+			f.OldPath, f.Path = fields[i+1], fields[i+2]
+			i += 2
+		} else {
+			f.Path = fields[i+1]
+			i++
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+type lineStat struct {
+	additions, deletions int
+	binary               bool
+}
+
+// parseNumstat parses git diff --numstat -z output, keyed by new path.
+func parseNumstat(out []byte) map[string]lineStat {
+	stats := make(map[string]lineStat)
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if path == "" {
+			// Renames put the old and new path in the next two fields.
+			if i+2 >= len(fields) {
+				break
+			}
+			path = fields[i+2]
+			i += 2
+		}
+		var s lineStat
+		if parts[0] == "-" {
+			s.binary = true
+		} else {
+			s.additions, _ = strconv.Atoi(parts[0])
+			s.deletions, _ = strconv.Atoi(parts[1])
+		}
+		stats[path] = s
+	}
+	return stats
+}
+
+// splitPatch splits a multi-file unified diff into one patch per file.
+func splitPatch(out []byte) []string {
+	var patches []string
+	for _, chunk := range bytes.Split(out, []byte("\ndiff --git ")) {
+		if len(chunk) == 0 {
+			continue
+		}
+		p := string(chunk)
+		if !strings.HasPrefix(p, "diff --git ") {
+			p = "diff --git " + p
+		}
+		patches = append(patches, strings.TrimSuffix(p, "\n")+"\n")
+	}
+	return patches
+}
+
+// capPatch cuts p to at most maxPatchBytes, backing up to the start of a
+// rune so a multi-byte character isn't split.
+func capPatch(p string) (string, bool) {
+	if len(p) <= maxPatchBytes {
+		return p, false
+	}
+	n := maxPatchBytes
+	for n > 0 && !utf8.RuneStart(p[n]) {
+		n--
+	}
+	return p[:n], true
+}
+
+func refExists(dir, ref string) bool {
+	return exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	out, err := gitRaw(dir, args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+func gitRaw(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
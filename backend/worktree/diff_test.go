@@ -0,0 +1,217 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []FileChange
+	}{
+		{"empty", "", nil},
+		{
+			"simple",
+			"M\x00a.go\x00A\x00new.go\x00D\x00gone.go\x00T\x00link\x00",
+			[]FileChange{
+				{Path: "a.go", Status: "modified"},
+				{Path: "new.go", Status: "added"},
+				{Path: "gone.go", Status: "deleted"},
+				{Path: "link", Status: "typechange"},
+			},
+		},
+		{
+			"rename and copy",
+			"R087\x00old.go\x00new.go\x00C100\x00src.go\x00copy.go\x00M\x00after.go\x00",
+			[]FileChange{
+				{Path: "new.go", OldPath: "old.go", Status: "renamed"},
+				{Path: "copy.go", OldPath: "src.go", Status: "copied"},
+				{Path: "after.go", Status: "modified"},
+			},
+		},
+		{
+			"tabs and newlines in paths",
+			"M\x00a\tb\x00R100\x00line\none\x00line\ntwo\x00",
+			[]FileChange{
+				{Path: "a\tb", Status: "modified"},
+				{Path: "line\ntwo", OldPath: "line\none", Status: "renamed"},
+			},
+		},
+		{"truncated rename", "R100\x00old.go\x00", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNameStatus([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNameStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string]lineStat
+	}{
+		{"empty", "", map[string]lineStat{}},
+		{
+			"simple and binary",
+			"3\t1\ta.go\x00-\t-\timage.png\x00",
+			map[string]lineStat{
+				"a.go":      {additions: 3, deletions: 1},
+				"image.png": {binary: true},
+			},
+		},
+		{
+			// A rename leaves the path field empty and puts the old and new
+			// paths in the next two fields; the one after is a new record.
+			"rename shifts fields",
+			"2\t0\t\x00old.go\x00new.go\x005\t5\tafter.go\x00",
+			map[string]lineStat{
+				"new.go":   {additions: 2},
+				"after.go": {additions: 5, deletions: 5},
+			},
+		},
+		{
+			"binary copy",
+			"-\t-\t\x00src.bin\x00copy.bin\x00",
+			map[string]lineStat{"copy.bin": {binary: true}},
+		},
+		{
+			"tabs and newlines in paths",
+			"1\t1\ta\tb\x000\t2\t\x00line\none\x00line\ntwo\x00",
+			map[string]lineStat{
+				"a\tb":      {additions: 1, deletions: 1},
+				"line\ntwo": {deletions: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNumstat([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNumstat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPatch(t *testing.T) {
+	first := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+diff --git looks like a header\n"
+	binary := "diff --git a/image.png b/image.png\nBinary files a/image.png and b/image.png differ\n"
+	rename := "diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n"
+	tests := []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"one", first, []string{first}},
+		{"several", first + binary + rename, []string{first, binary, rename}},
+		{"no trailing newline", strings.TrimSuffix(binary, "\n"), []string{binary}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitPatch([]byte(tt.out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapPatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantLen   int
+		truncated bool
+	}{
+		{"short", "diff\n", 5, false},
+		{"exactly the limit", strings.Repeat("a", maxPatchBytes), maxPatchBytes, false},
+		{"ascii over the limit", strings.Repeat("a", maxPatchBytes+10), maxPatchBytes, true},
+		// "é" is two bytes; the limit falls inside the one straddling it.
+		{"splits a rune", strings.Repeat("a", maxPatchBytes-1) + "é" + "tail", maxPatchBytes - 1, true},
+		// "€" is three bytes; the limit falls after its first two.
+		{"splits a wide rune", strings.Repeat("a", maxPatchBytes-2) + "€", maxPatchBytes - 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := capPatch(tt.in)
+			if len(got) != tt.wantLen || truncated != tt.truncated {
+				t.Errorf("capPatch() = %d bytes, truncated %v; want %d, %v", len(got), truncated, tt.wantLen, tt.truncated)
+			}
+			if !utf8.ValidString(got) {
+				t.Error("capPatch() split a rune")
+			}
+		})
+	}
+}
+
+func TestTrackedChangesAwkwardPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "aim")
+	t.Setenv("GIT_AUTHOR_EMAIL", "aim@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "aim")
+	t.Setenv("GIT_COMMITTER_EMAIL", "aim@example.com")
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body := strings.Repeat("unchanged line\n", 20)
+	run("init", "--quiet", "--initial-branch=main")
+	write("tab\there", body)
+	write("plain.txt", "one\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "base")
+	base, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run("mv", "tab\there", "new\nline")
+	write("new\nline", body+"added\n")
+	write("plain.txt", "one\ntwo\n")
+	write("image.bin", "\x00\x01\x02")
+	run("add", ".")
+
+	files, err := trackedChanges(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]FileChange)
+	for _, f := range files {
+		got[f.Path] = f
+	}
+	if len(files) != 3 {
+		t.Fatalf("files = %+v", files)
+	}
+	if f := got["new\nline"]; f.Status != "renamed" || f.OldPath != "tab\there" || f.Additions != 1 || !strings.Contains(f.Patch, "+added") {
+		t.Errorf("rename = %+v", f)
+	}
+	if f := got["plain.txt"]; f.Status != "modified" || f.Additions != 1 || !strings.Contains(f.Patch, "+two") {
+		t.Errorf("modified = %+v", f)
+	}
+	if f := got["image.bin"]; f.Status != "added" || !f.Binary || !strings.Contains(f.Patch, "Binary files") {
+		t.Errorf("binary = %+v", f)
+	}
+}
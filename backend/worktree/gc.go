@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PruneWorktree drops git's record of the worktree at worktreePath, whose
+// directory is gone. Unlike git worktree prune it leaves every other stale
+// record alone.
+func (m *Manager) PruneWorktree(repoPath, worktreePath string) error {
+	if _, err := os.Stat(worktreePath); err == nil {
+		return fmt.Errorf("%s still exists", worktreePath)
+	}
+	common, err := gitOutput(repoPath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(repoPath, common)
+	}
+	admin := filepath.Join(common, "worktrees")
+	entries, err := os.ReadDir(admin)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", admin, err)
+	}
+	for _, e := range entries {
+		// gitdir holds the path of the worktree's .git file.
+		data, err := os.ReadFile(filepath.Join(admin, e.Name(), "gitdir"))
+		if err != nil {
+			continue
+		}
+		if filepath.Dir(filepath.Clean(strings.TrimSpace(string(data)))) == filepath.Clean(worktreePath) {
+			if err := os.RemoveAll(filepath.Join(admin, e.Name())); err != nil {
+				return fmt.Errorf("remove worktree record: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no worktree record for %s", worktreePath)
+}
+
+// IsMerged reports whether every commit on the branch checked out at path
+// is already on ref, including commits that were rebased or cherry-picked
+// onto it. Squash merges aren't detected.
+func IsMerged(path, ref string) (bool, error) {
+	out, err := gitOutput(path, "cherry", ref, "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("git cherry: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "+") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// DeleteBranch deletes a local branch that is fully merged; git refuses
+// otherwise.
+func (m *Manager) DeleteBranch(repoPath, branch string) error {
+	if out, err := exec.Command("git", "-C", repoPath, "branch", "-d", branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("git branch -d: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DirSize returns the bytes used by the files under path, without following
+// symlinks. Unreadable entries are skipped.
+func DirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,56 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPruneWorktreeLeavesOtherRecords(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "aim")
+	t.Setenv("GIT_AUTHOR_EMAIL", "aim@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "aim")
+	t.Setenv("GIT_COMMITTER_EMAIL", "aim@example.com")
+
+	repo := t.TempDir()
+	trees := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	run("init", "--quiet", "--initial-branch=main")
+	run("commit", "--quiet", "--allow-empty", "-m", "base")
+	a, b := filepath.Join(trees, "a"), filepath.Join(trees, "b")
+	run("worktree", "add", "--quiet", "-b", "a", a)
+	run("worktree", "add", "--quiet", "-b", "b", b)
+	for _, p := range []string{a, b} {
+		if err := os.RemoveAll(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewManager()
+	if err := m.PruneWorktree(repo, a); err != nil {
+		t.Fatal(err)
+	}
+	list, err := m.ListWorktrees(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, w := range list[1:] {
+		paths = append(paths, w.Path)
+	}
+	if len(paths) != 1 || filepath.Base(paths[0]) != "b" {
+		t.Errorf("worktrees after pruning a = %v, want only b", paths)
+	}
+	if err := m.PruneWorktree(repo, a); err == nil {
+		t.Error("pruning a twice succeeded")
+	}
+}
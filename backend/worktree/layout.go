@@ -0,0 +1,153 @@
+package worktree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxSlugLen bounds the readable part of a worktree directory name.
+const maxSlugLen = 40
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// RootFunc returns the directory worktrees for repoPath are created under,
+// or "" to keep them inside the repository's .git directory.
+type RootFunc func(repoPath string) string
+
+// SetRootFunc sets how the worktree root is chosen for each repository.
+func (m *Manager) SetRootFunc(fn RootFunc) {
+	m.mu.Lock()
+	m.rootFunc = fn
+	m.mu.Unlock()
+}
+
+// WorktreePath returns where the worktree for branch of repoPath belongs.
+// Under a configured root the layout is <root>/<host>/<namespace>/<repo>-<hash>/<name>;
+// otherwise it is <repo>/.git/aim-worktrees/<name>. The name is a readable
+// slug of the branch plus a hash of it, so branches like a/b and a-b never
+// share a directory.
+func (m *Manager) WorktreePath(repoPath string, branch string) string {
+	m.mu.RLock()
+	fn := m.rootFunc
+	m.mu.RUnlock()
+	var root string
+	if fn != nil {
+		root = expandHome(fn(repoPath))
+	}
+	if root == "" {
+		return filepath.Join(repoPath, ".git", "aim-worktrees", worktreeDirName(branch))
+	}
+	return filepath.Join(root, m.repoDirName(repoPath), worktreeDirName(branch))
+}
+
+// repoDirName returns repoDirName(repoPath), remembering it so paths don't
+// cost a git run each.
+func (m *Manager) repoDirName(repoPath string) string {
+	m.mu.RLock()
+	dir, ok := m.repoDirs[repoPath]
+	m.mu.RUnlock()
+	if ok {
+		return dir
+	}
+	dir = repoDirName(repoPath)
+	m.mu.Lock()
+	m.repoDirs[repoPath] = dir
+	m.mu.Unlock()
+	return dir
+}
+
+// ManagedDirs returns the directories aim creates worktrees of repoPath in:
+// the legacy location inside .git and, if configured, the worktree root.
+func (m *Manager) ManagedDirs(repoPath string) []string {
+	dirs := []string{filepath.Join(repoPath, ".git", "aim-worktrees")}
+	if dir := filepath.Dir(m.WorktreePath(repoPath, "_")); dir != dirs[0] {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// ValidateBranchName reports whether name can be used for a new branch.
+func (m *Manager) ValidateBranchName(name string) error {
+	return ValidateBranchName(name)
+}
+
+// ValidateBranchName checks name against git's branch naming rules.
+func ValidateBranchName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("branch name is required")
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if err := exec.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
+
+// MoveWorktree moves a worktree with git worktree move, so git's own
+// bookkeeping follows it.
+func (m *Manager) MoveWorktree(repoPath string, from string, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(to), err)
+	}
+	if out, err := exec.Command("git", "-C", repoPath, "worktree", "move", from, to).CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree move: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// worktreeDirName returns a deterministic, collision-free directory name for branch.
+func worktreeDirName(branch string) string {
+	slug := strings.Trim(unsafeChars.ReplaceAllString(branch, "-"), "-.")
+	if len(slug) > maxSlugLen {
+		slug = strings.TrimRight(slug[:maxSlugLen], "-.")
+	}
+	if slug == "" {
+		slug = "branch"
+	}
+	return slug + "-" + shortHash(branch)
+}
+
+// repoDirName returns <host>/<namespace>/<repo>-<hash> from the origin
+// remote, or local/<name>-<hash> for repositories without one. The hash of
+// the repository's path keeps separate clones of one remote apart.
+func repoDirName(repoPath string) string {
+	abs, _ := filepath.Abs(repoPath)
+	if url, err := RemoteURL(repoPath, "origin"); err == nil {
+		if parsed, err := ParseRepoURL(url); err == nil && parsed.Host != "" {
+			return parsed.RelPath() + "-" + shortHash(abs)
+		}
+	}
+	return filepath.Join("local", safeName(filepath.Base(abs))+"-"+shortHash(abs))
+}
+
+func safeName(s string) string {
+	s = strings.Trim(unsafeChars.ReplaceAllString(s, "-"), "-.")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
@@ -0,0 +1,50 @@
+package worktree
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreePathSeparatesClones(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	base := t.TempDir()
+	var clones []string
+	for _, name := range []string{"one", "two"} {
+		dir := filepath.Join(base, name, "aim")
+		for _, args := range [][]string{
+			{"init", "--quiet", dir},
+			{"-C", dir, "remote", "add", "origin", "git@github.com:Benbentwo/aim.git"},
+		} {
+			if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %s", args, out)
+			}
+		}
+		clones = append(clones, dir)
+	}
+
+	m := NewManager()
+	root := filepath.Join(base, "worktrees")
+	m.SetRootFunc(func(string) string { return root })
+
+	a := m.WorktreePath(clones[0], "aim/feature")
+	b := m.WorktreePath(clones[1], "aim/feature")
+	if a == b {
+		t.Fatalf("both clones use %s", a)
+	}
+	for _, p := range []string{a, b} {
+		if !strings.HasPrefix(p, filepath.Join(root, "github.com", "Benbentwo", "aim-")) {
+			t.Errorf("%s is not under the remote's directory", p)
+		}
+	}
+	// The remote is looked up once per repository, not on every call.
+	if out, err := exec.Command("git", "-C", clones[0], "remote", "set-url", "origin", "git@gitlab.com:other/aim.git").CombinedOutput(); err != nil {
+		t.Fatalf("git remote set-url: %s", out)
+	}
+	if again := m.WorktreePath(clones[0], "aim/feature"); again != a {
+		t.Errorf("path not stable: %s then %s", a, again)
+	}
+}
@@ -3,31 +3,32 @@ package worktree
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 type WorktreeInfo struct {
-	Path   string `json:"path"`
-	Branch string `json:"branch"`
-	Hash   string `json:"hash"`
-}
-
-// RepoURL holds parsed components of a Git remote URL.
-type RepoURL struct {
-	Host string `json:"host"`
-	Org  string `json:"org"`
-	Repo string `json:"repo"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch"`
+	Hash     string `json:"hash"`
+	Locked   bool   `json:"locked,omitempty"`
+	Prunable string `json:"prunable,omitempty"` // why git considers it stale, e.g. its directory is gone
 }
 
 type Manager struct {
-	ctx context.Context
+	ctx      context.Context
+	mu       sync.RWMutex
+	rootFunc RootFunc
+	repoDirs map[string]string // repository path -> its directory under a worktree root
 }
 
 func NewManager() *Manager {
-	return &Manager{}
+	return &Manager{
+		repoDirs: make(map[string]string),
+	}
 }
 
 func (m *Manager) SetContext(ctx context.Context) {
@@ -39,18 +40,27 @@ func (m *Manager) IsGitRepo(path string) bool {
 	return cmd.Run() == nil
 }
 
+// CreateWorktree checks out branch (creating it from the current HEAD if it
+// doesn't exist) in a new worktree at WorktreePath.
 func (m *Manager) CreateWorktree(repoPath string, branch string) (string, error) {
-	safe := strings.ReplaceAll(branch, "/", "-")
-	worktreePath := repoPath + "/.git/aim-worktrees/" + safe
+	if err := ValidateBranchName(branch); err != nil {
+		return "", err
+	}
+	worktreePath := m.WorktreePath(repoPath, branch)
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		return "", fmt.Errorf("create worktree root: %w", err)
+	}
 
 	cmd := exec.Command("git", "-C", repoPath, "worktree", "add", worktreePath, branch)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
+		base, _ := gitOutput(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
 		cmd2 := exec.Command("git", "-C", repoPath, "worktree", "add", "-b", branch, worktreePath)
 		out2, err2 := cmd2.CombinedOutput()
 		if err2 != nil {
 			return "", fmt.Errorf("git worktree add failed: %s\n%s", string(out), string(out2))
 		}
+		recordBase(repoPath, branch, base)
 	}
 	return worktreePath, nil
 }
@@ -64,71 +74,36 @@ func (m *Manager) ListWorktrees(repoPath string) ([]WorktreeInfo, error) {
 	return parseWorktreeList(string(out)), nil
 }
 
-func (m *Manager) RemoveWorktree(repoPath string, worktreePath string) error {
-	cmd := exec.Command("git", "-C", repoPath, "worktree", "remove", "--force", worktreePath)
-	out, err := cmd.CombinedOutput()
+// CloneDestPath returns the expected destination for a URL given a base dir.
+// Nested namespaces such as GitLab subgroups become nested directories. A
+// clone of the same repository made before the host was part of the layout
+// is found where it is, at <base>/<org>/<repo>.
+func (m *Manager) CloneDestPath(repoURL string, baseDir string) (string, error) {
+	parsed, err := ParseRepoURL(repoURL)
 	if err != nil {
-		return fmt.Errorf("git worktree remove: %s", string(out))
+		return "", err
 	}
-	return nil
-}
-
-// ParseRepoURL is the exported method bound to Wails.
-func (m *Manager) ParseRepoURL(rawURL string) (RepoURL, error) {
-	return ParseRepoURL(rawURL)
-}
-
-// ParseRepoURL is the package-level helper (also used by workspace manager).
-func ParseRepoURL(rawURL string) (RepoURL, error) {
-	rawURL = strings.TrimSpace(rawURL)
-
-	// Handle SSH format: git@github.com:org/repo.git
-	if strings.HasPrefix(rawURL, "git@") {
-		withoutPrefix := strings.TrimPrefix(rawURL, "git@")
-		parts := strings.SplitN(withoutPrefix, ":", 2)
-		if len(parts) != 2 {
-			return RepoURL{}, fmt.Errorf("invalid SSH URL: %s", rawURL)
-		}
-		host := parts[0]
-		path := strings.TrimSuffix(parts[1], ".git")
-		pathParts := strings.SplitN(path, "/", 2)
-		if len(pathParts) != 2 {
-			return RepoURL{}, fmt.Errorf("invalid SSH URL path: %s", rawURL)
+	if parsed.Host != "" {
+		legacy := filepath.Join(baseDir, parsed.Org, parsed.Repo)
+		if isCloneOf(legacy, parsed) {
+			return legacy, nil
 		}
-		return RepoURL{Host: host, Org: pathParts[0], Repo: pathParts[1]}, nil
-	}
-
-	// Handle HTTPS format: https://github.com/org/repo.git
-	u, err := url.Parse(rawURL)
-	if err != nil {
-		return RepoURL{}, fmt.Errorf("invalid URL: %w", err)
-	}
-	path := strings.TrimPrefix(u.Path, "/")
-	path = strings.TrimSuffix(path, ".git")
-	parts := strings.SplitN(path, "/", 2)
-	if len(parts) != 2 {
-		return RepoURL{}, fmt.Errorf("URL must include org/repo path: %s", rawURL)
 	}
-	return RepoURL{Host: u.Host, Org: parts[0], Repo: parts[1]}, nil
+	return filepath.Join(baseDir, parsed.RelPath()), nil
 }
 
-// CloneRepo runs git clone <url> <destPath>.
-func (m *Manager) CloneRepo(repoURL string, destPath string) error {
-	cmd := exec.Command("git", "clone", "--progress", repoURL, destPath)
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git clone failed: %s", string(out))
+// isCloneOf reports whether dir is a checkout whose origin is the same
+// repository as r.
+func isCloneOf(dir string, r RepoURL) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return false
 	}
-	return nil
-}
-
-// CloneDestPath returns the expected destination for a URL given a base dir.
-func (m *Manager) CloneDestPath(repoURL string, baseDir string) (string, error) {
-	parsed, err := ParseRepoURL(repoURL)
+	origin, err := gitOutput(dir, "remote", "get-url", "origin")
 	if err != nil {
-		return "", err
+		return false
 	}
-	return filepath.Join(baseDir, parsed.Org, parsed.Repo), nil
+	o, err := ParseRepoURL(origin)
+	return err == nil && o.Host == r.Host && o.Port == r.Port && o.Namespace == r.Namespace && o.Repo == r.Repo
 }
 
 func parseWorktreeList(output string) []WorktreeInfo {
@@ -151,6 +126,13 @@ func parseWorktreeList(output string) []WorktreeInfo {
 			b := strings.TrimPrefix(line, "branch ")
 			b = strings.TrimPrefix(b, "refs/heads/")
 			current.Branch = b
+		} else if line == "locked" || strings.HasPrefix(line, "locked ") {
+			current.Locked = true
+		} else if line == "prunable" || strings.HasPrefix(line, "prunable ") {
+			current.Prunable = strings.TrimSpace(strings.TrimPrefix(line, "prunable"))
+			if current.Prunable == "" {
+				current.Prunable = "prunable"
+			}
 		}
 	}
 	if current.Path != "" {
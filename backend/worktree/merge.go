@@ -0,0 +1,247 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Merge strategies.
+const (
+	StrategyMerge  = "merge"  // merge commit with both parents
+	StrategySquash = "squash" // single commit on the base
+	StrategyRebase = "rebase" // replay the branch onto the base, then fast-forward
+)
+
+// Conflict is a file that can't be merged automatically.
+type Conflict struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"` // git's conflict type, e.g. "contents", "modify/delete"
+}
+
+// MergeCheck is the result of a dry-run merge.
+type MergeCheck struct {
+	Branch    string     `json:"branch"`
+	Base      string     `json:"base"`
+	Commits   int        `json:"commits"` // commits on branch not on base
+	Conflicts []Conflict `json:"conflicts"`
+	tree      string
+}
+
+// Clean reports whether the merge would succeed without conflicts.
+func (c MergeCheck) Clean() bool {
+	return len(c.Conflicts) == 0
+}
+
+// CheckMerge merges branch into base in memory with git merge-tree and
+// reports conflicting files without touching any working tree. A rebase
+// replays commits one at a time and may stop on conflicts this check
+// resolves, but never succeeds where it finds some.
+func (m *Manager) CheckMerge(repoPath string, branch string, base string) (MergeCheck, error) {
+	check := MergeCheck{Branch: branch, Base: base}
+	count, err := gitOutput(repoPath, "rev-list", "--count", base+".."+branch)
+	if err != nil {
+		return check, fmt.Errorf("git rev-list: %w", err)
+	}
+	if check.Commits, err = strconv.Atoi(count); err != nil {
+		return check, fmt.Errorf("git rev-list: unexpected output %q", count)
+	}
+
+	cmd := exec.Command("git", "-C", repoPath, "merge-tree", "--write-tree", "--name-only", "-z", base, branch)
+	out, err := cmd.Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); !ok || exit.ExitCode() != 1 {
+			return check, fmt.Errorf("git merge-tree: %s", exitStderr(err))
+		}
+	}
+	check.tree, check.Conflicts = parseMergeTree(string(out))
+	return check, nil
+}
+
+// parseMergeTree parses git merge-tree --write-tree --name-only -z output:
+// the tree ID and the conflicted paths, an empty field, then git's messages,
+// each as a path count, the paths, a type such as "CONFLICT (contents)" and
+// the text.
+func parseMergeTree(out string) (string, []Conflict) {
+	fields := strings.Split(out, "\x00")
+	tree := fields[0]
+
+	var paths []string
+	i := 1
+	for ; i < len(fields) && fields[i] != ""; i++ {
+		paths = append(paths, fields[i])
+	}
+
+	kinds := make(map[string]string)
+	for i++; i < len(fields); {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 || i+n+2 >= len(fields) {
+			break
+		}
+		kind, conflict := strings.CutPrefix(fields[i+n+1], "CONFLICT (")
+		kind = strings.TrimSuffix(kind, ")")
+		for _, path := range fields[i+1 : i+n+1] {
+			if _, ok := kinds[path]; conflict && !ok {
+				kinds[path] = kind
+			}
+		}
+		i += n + 3
+	}
+
+	var conflicts []Conflict
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		kind := kinds[path]
+		if kind == "" {
+			kind = "contents"
+		}
+		conflicts = append(conflicts, Conflict{Path: path, Kind: kind})
+	}
+	return tree, conflicts
+}
+
+// MergeBranch lands branch (checked out at worktreePath) on base using
+// strategy and returns the new tip of base. It fails without changing
+// anything if the merge conflicts. If base is checked out somewhere, that
+// checkout must be clean and is fast-forwarded to the result.
+func (m *Manager) MergeBranch(repoPath, worktreePath, branch, base, strategy, message string) (string, error) {
+	baseCheckout, err := m.checkoutOf(repoPath, base)
+	if err != nil {
+		return "", err
+	}
+	if baseCheckout != "" {
+		if dirty, _ := gitOutput(baseCheckout, "status", "--porcelain", "--untracked-files=no"); dirty != "" {
+			return "", fmt.Errorf("%s is checked out at %s with uncommitted changes", base, baseCheckout)
+		}
+	}
+	oldBase, err := gitOutput(repoPath, "rev-parse", "refs/heads/"+base)
+	if err != nil {
+		return "", fmt.Errorf("base branch %s not found", base)
+	}
+
+	var result string
+	switch strategy {
+	case StrategyMerge, StrategySquash:
+		check, err := m.CheckMerge(repoPath, branch, base)
+		if err != nil {
+			return "", err
+		}
+		if !check.Clean() {
+			return "", fmt.Errorf("merging %s into %s conflicts in %d files", branch, base, len(check.Conflicts))
+		}
+		args := []string{"-C", repoPath, "commit-tree", check.tree, "-p", oldBase}
+		if strategy == StrategyMerge {
+			args = append(args, "-p", "refs/heads/"+branch)
+		}
+		args = append(args, "-m", message)
+		out, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return "", fmt.Errorf("git commit-tree: %s", exitStderr(err))
+		}
+		result = strings.TrimSpace(string(out))
+	case StrategyRebase:
+		if out, err := exec.Command("git", "-C", worktreePath, "rebase", base).CombinedOutput(); err != nil {
+			_ = exec.Command("git", "-C", worktreePath, "rebase", "--abort").Run()
+			return "", fmt.Errorf("git rebase %s: %s", base, strings.TrimSpace(string(out)))
+		}
+		if result, err = gitOutput(worktreePath, "rev-parse", "HEAD"); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unknown merge strategy %q", strategy)
+	}
+
+	if baseCheckout != "" {
+		if out, err := exec.Command("git", "-C", baseCheckout, "merge", "--ff-only", result).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("fast-forward %s: %s", base, strings.TrimSpace(string(out)))
+		}
+	} else if out, err := exec.Command("git", "-C", repoPath, "update-ref", "refs/heads/"+base, result, oldBase).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git update-ref: %s", strings.TrimSpace(string(out)))
+	}
+	return result, nil
+}
+
+// checkoutOf returns the worktree that has branch checked out, or "".
+func (m *Manager) checkoutOf(repoPath, branch string) (string, error) {
+	trees, err := m.ListWorktrees(repoPath)
+	if err != nil {
+		return "", err
+	}
+	for _, t := range trees {
+		if t.Branch == branch {
+			return t.Path, nil
+		}
+	}
+	return "", nil
+}
+
+func exitStderr(err error) string {
+	if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+		return strings.TrimSpace(string(exit.Stderr))
+	}
+	return err.Error()
+}
+
+// StartMerge merges base into the branch checked out at worktreePath,
+// leaving any conflicts in the working tree.
+func (m *Manager) StartMerge(worktreePath, base string) error {
+	if out, err := exec.Command("git", "-C", worktreePath, "merge", "--no-edit", base).CombinedOutput(); err != nil {
+		return fmt.Errorf("git merge %s: %s", base, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// StartRebase rebases the branch checked out at worktreePath onto base,
+// stopping at the first conflicting commit.
+func (m *Manager) StartRebase(worktreePath, base string) error {
+	if out, err := exec.Command("git", "-C", worktreePath, "rebase", base).CombinedOutput(); err != nil {
+		return fmt.Errorf("git rebase %s: %s", base, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Operations git can stop in the middle of, leaving conflicts to resolve.
+const (
+	OpMerge  = "merge"
+	OpRebase = "rebase"
+)
+
+// InProgress returns the merge or rebase stopped in the working tree at
+// path, or "" if there is none.
+func (m *Manager) InProgress(path string) string {
+	for _, op := range []struct{ name, file string }{
+		{OpRebase, "rebase-merge"},
+		{OpRebase, "rebase-apply"},
+		{OpMerge, "MERGE_HEAD"},
+	} {
+		p, err := gitOutput(path, "rev-parse", "--git-path", op.file)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(path, p)
+		}
+		if _, err := os.Stat(p); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+// IsClean reports whether path has no uncommitted changes to tracked files.
+func (m *Manager) IsClean(path string) bool {
+	out, err := gitOutput(path, "status", "--porcelain", "--untracked-files=no")
+	return err == nil && out == ""
+}
+
+// BranchExists reports whether the repository at path has a local branch.
+func (m *Manager) BranchExists(path, branch string) bool {
+	return exec.Command("git", "-C", path, "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
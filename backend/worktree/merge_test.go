@@ -0,0 +1,76 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckMergeConflictPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "aim")
+	t.Setenv("GIT_AUTHOR_EMAIL", "aim@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "aim")
+	t.Setenv("GIT_COMMITTER_EMAIL", "aim@example.com")
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// "a" is deleted on one side; "a b" names it as a substring and conflicts
+	// in content.
+	run("init", "--quiet", "--initial-branch=main")
+	write("a", "base\n")
+	write("a b", "base\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "base")
+	run("checkout", "--quiet", "-b", "feature")
+	write("a b", "feature\n")
+	run("rm", "--quiet", "a")
+	run("commit", "--quiet", "-am", "feature")
+	run("checkout", "--quiet", "main")
+	write("a", "main\n")
+	write("a b", "main\n")
+	run("commit", "--quiet", "-am", "main")
+
+	m := NewManager()
+	check, err := m.CheckMerge(dir, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Commits != 1 {
+		t.Errorf("commits = %d, want 1", check.Commits)
+	}
+	want := map[string]string{"a": "modify/delete", "a b": "contents"}
+	if len(check.Conflicts) != len(want) {
+		t.Fatalf("conflicts = %+v", check.Conflicts)
+	}
+	for _, c := range check.Conflicts {
+		if want[c.Path] != c.Kind {
+			t.Errorf("%q: kind %q, want %q", c.Path, c.Kind, want[c.Path])
+		}
+	}
+
+	if op := m.InProgress(dir); op != "" {
+		t.Errorf("in progress before merging: %q", op)
+	}
+	if err := m.StartMerge(dir, "feature"); err == nil {
+		t.Fatal("conflicting merge succeeded")
+	}
+	if op := m.InProgress(dir); op != OpMerge {
+		t.Errorf("in progress = %q, want %q", op, OpMerge)
+	}
+}
@@ -0,0 +1,153 @@
+package worktree
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RepoURL holds parsed components of a Git remote URL.
+type RepoURL struct {
+	Host      string `json:"host"`           // lowercased; "" for local repositories
+	Port      string `json:"port,omitempty"` // only when given explicitly
+	Namespace string `json:"namespace"`      // owner path, e.g. "org" or "group/subgroup"
+	Org       string `json:"org"`            // first namespace segment
+	Repo      string `json:"repo"`
+	Local     string `json:"local,omitempty"` // filesystem path for file:// URLs and local paths
+}
+
+var (
+	// scpLike matches git's [user@]host:path form, which has no slash before the colon.
+	scpLike = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.*)$`)
+	// driveLetter matches Windows paths, which git treats as local.
+	driveLetter = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+)
+
+// ParseRepoURL is the exported method bound to Wails.
+func (m *Manager) ParseRepoURL(rawURL string) (RepoURL, error) {
+	return ParseRepoURL(rawURL)
+}
+
+// ParseRepoURL is the package-level helper (also used by workspace manager).
+// It accepts every URL form git does: scheme URLs (https, http, ssh,
+// git+ssh, git, ftp, file), scp-like [user@]host:path and local paths.
+func ParseRepoURL(rawURL string) (RepoURL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return RepoURL{}, fmt.Errorf("empty repository URL")
+	}
+
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return RepoURL{}, fmt.Errorf("invalid URL: %w", err)
+		}
+		switch strings.ToLower(u.Scheme) {
+		case "file":
+			return parseLocal(rawURL, u.Path)
+		case "https", "http", "ssh", "git+ssh", "ssh+git", "git", "ftp", "ftps":
+		default:
+			return RepoURL{}, fmt.Errorf("unsupported URL scheme %q: %s", u.Scheme, rawURL)
+		}
+		if u.Hostname() == "" {
+			return RepoURL{}, fmt.Errorf("URL has no host: %s", rawURL)
+		}
+		r, err := splitRepoPath(strings.ToLower(u.Hostname()), u.Path)
+		if err != nil {
+			return RepoURL{}, fmt.Errorf("%w: %s", err, rawURL)
+		}
+		r.Port = u.Port()
+		return r, nil
+	}
+
+	if m := scpLike.FindStringSubmatch(rawURL); m != nil && !driveLetter.MatchString(rawURL) {
+		r, err := splitRepoPath(strings.ToLower(m[1]), m[2])
+		if err != nil {
+			return RepoURL{}, fmt.Errorf("%w: %s", err, rawURL)
+		}
+		return r, nil
+	}
+
+	return parseLocal(rawURL, rawURL)
+}
+
+// splitRepoPath splits a remote path into namespace and repository,
+// dropping hosting-specific segments such as Azure DevOps' "_git".
+func splitRepoPath(host, repoPath string) (RepoURL, error) {
+	var segments []string
+	for _, s := range strings.Split(strings.ReplaceAll(repoPath, "\\", "/"), "/") {
+		switch s {
+		case "", ".":
+			continue
+		case "..":
+			return RepoURL{}, fmt.Errorf("URL path must not contain ..")
+		case "_git":
+			continue // dev.azure.com/org/project/_git/repo
+		}
+		segments = append(segments, s)
+	}
+	azure := strings.HasSuffix(host, "dev.azure.com") || strings.HasSuffix(host, ".visualstudio.com")
+	if azure && len(segments) > 0 && segments[0] == "v3" {
+		segments = segments[1:] // ssh.dev.azure.com:v3/org/project/repo
+	} else if org, ok := strings.CutSuffix(host, ".visualstudio.com"); ok && org != "vs-ssh" {
+		segments = append([]string{org}, segments...) // org.visualstudio.com/project/_git/repo
+	}
+	if len(segments) > 0 {
+		segments[0] = strings.TrimPrefix(segments[0], "~") // ssh://host/~user/repo
+	}
+	if len(segments) < 2 {
+		return RepoURL{}, fmt.Errorf("URL must include org/repo path")
+	}
+
+	last := len(segments) - 1
+	repo := strings.TrimSuffix(segments[last], ".git")
+	if repo == "" {
+		return RepoURL{}, fmt.Errorf("URL has no repository name")
+	}
+	return RepoURL{
+		Host:      host,
+		Namespace: strings.Join(segments[:last], "/"),
+		Org:       segments[0],
+		Repo:      repo,
+	}, nil
+}
+
+// parseLocal handles file:// URLs and paths to repositories on disk, bare
+// ("repo.git") or not ("repo/.git" or "repo").
+func parseLocal(rawURL, p string) (RepoURL, error) {
+	p = filepath.Clean(expandHome(p))
+	clean := strings.TrimSuffix(path.Clean(strings.ReplaceAll(p, "\\", "/")), "/.git")
+	repo := strings.TrimSuffix(path.Base(clean), ".git")
+	if repo == "" || repo == "." || repo == "/" {
+		return RepoURL{}, fmt.Errorf("path has no repository name: %s", rawURL)
+	}
+	return RepoURL{Repo: repo, Local: p}, nil
+}
+
+// RelPath returns the directory r is stored under relative to a base
+// directory: its host, namespace and name for remotes, local/<name>
+// otherwise. The host keeps same-named repositories on different servers
+// apart.
+func (r RepoURL) RelPath() string {
+	if r.Host == "" {
+		return filepath.Join("local", safeName(r.Repo))
+	}
+	parts := []string{r.hostDir()}
+	for _, s := range strings.Split(r.Namespace, "/") {
+		parts = append(parts, safeName(s))
+	}
+	return filepath.Join(append(parts, safeName(r.Repo))...)
+}
+
+// hostDir returns the directory name for r's server: its host, and its port
+// when one was given.
+func (r RepoURL) hostDir() string {
+	host := r.Host
+	if r.Port != "" {
+		host += "-" + r.Port
+	}
+	return safeName(host)
+}
@@ -0,0 +1,144 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GitStatus summarizes the state of a working tree.
+type GitStatus struct {
+	Path        string `json:"path"`
+	Branch      string `json:"branch"` // "" when HEAD is detached
+	Head        string `json:"head"`   // commit hash, "" before the first commit
+	HeadSubject string `json:"headSubject"`
+	Upstream    string `json:"upstream"`
+	Ahead       int    `json:"ahead"`  // commits not on upstream
+	Behind      int    `json:"behind"` // upstream commits not in HEAD
+	Staged      int    `json:"staged"`
+	Unstaged    int    `json:"unstaged"`
+	Untracked   int    `json:"untracked"`
+	Conflicted  int    `json:"conflicted"`
+	Operation   string `json:"operation"` // "merge", "rebase", "cherry-pick", "revert" or "" when none is in progress
+	Dirty       bool   `json:"dirty"`     // any uncommitted changes
+}
+
+// GetGitStatus returns the working tree status of path.
+func (m *Manager) GetGitStatus(path string) (GitStatus, error) {
+	out, err := gitRaw(path, "status", "--porcelain=v2", "--branch", "-z")
+	if err != nil {
+		return GitStatus{}, fmt.Errorf("git status: %w", err)
+	}
+	st := parseStatusV2(string(out))
+	st.Path = path
+	st.Dirty = st.Staged+st.Unstaged+st.Untracked+st.Conflicted > 0
+	if st.Head != "" {
+		st.HeadSubject, _ = gitOutput(path, "log", "-1", "--format=%s")
+	}
+	if gitDir, err := gitOutput(path, "rev-parse", "--absolute-git-dir"); err == nil {
+		st.Operation = operationInProgress(gitDir)
+	}
+	return st, nil
+}
+
+// WatchDirs returns the directories whose changes can change the status of
+// the checkout at path: path itself, its git dir, where the index and HEAD
+// live, and every directory below path that holds tracked files.
+func WatchDirs(path string) ([]string, error) {
+	path = filepath.Clean(path)
+	gitDir, err := gitOutput(path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse: %w", err)
+	}
+	out, err := gitRaw(path, "ls-files", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	dirs := []string{path, gitDir}
+	seen := map[string]bool{path: true}
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f == "" {
+			continue
+		}
+		for d := filepath.Dir(filepath.Join(path, f)); len(d) > len(path) && !seen[d]; d = filepath.Dir(d) {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs, nil
+}
+
+// IsIgnored reports whether git ignores name in the checkout at path.
+func IsIgnored(path, name string) bool {
+	_, err := gitRaw(path, "check-ignore", "-q", "--", name)
+	return err == nil
+}
+
+// parseStatusV2 parses git status --porcelain=v2 --branch -z output.
+func parseStatusV2(out string) GitStatus {
+	var st GitStatus
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		switch {
+		case strings.HasPrefix(e, "# branch.oid "):
+			if oid := strings.TrimPrefix(e, "# branch.oid "); oid != "(initial)" {
+				st.Head = oid
+			}
+		case strings.HasPrefix(e, "# branch.head "):
+			if head := strings.TrimPrefix(e, "# branch.head "); head != "(detached)" {
+				st.Branch = head
+			}
+		case strings.HasPrefix(e, "# branch.upstream "):
+			st.Upstream = strings.TrimPrefix(e, "# branch.upstream ")
+		case strings.HasPrefix(e, "# branch.ab "):
+			for _, f := range strings.Fields(strings.TrimPrefix(e, "# branch.ab ")) {
+				n, _ := strconv.Atoi(f[1:])
+				if f[0] == '+' {
+					st.Ahead = n
+				} else {
+					st.Behind = n
+				}
+			}
+		case strings.HasPrefix(e, "1 "), strings.HasPrefix(e, "2 "):
+			if len(e) >= 4 {
+				if e[2] != '.' {
+					st.Staged++
+				}
+				if e[3] != '.' {
+					st.Unstaged++
+				}
+			}
+			if e[0] == '2' {
+				i++ // renames are followed by the original path
+			}
+		case strings.HasPrefix(e, "u "):
+			st.Conflicted++
+		case strings.HasPrefix(e, "? "):
+			st.Untracked++
+		}
+	}
+	return st
+}
+
+// operationInProgress inspects a git dir for an interrupted merge, rebase,
+// cherry-pick or revert.
+func operationInProgress(gitDir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return "rebase"
+	case exists("MERGE_HEAD"):
+		return "merge"
+	case exists("CHERRY_PICK_HEAD"):
+		return "cherry-pick"
+	case exists("REVERT_HEAD"):
+		return "revert"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestWatchDirs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"a/b/c.txt", "a/d.txt", "top.txt", "ignored/x.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("ignored/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "."}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	got, err := WatchDirs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	want := []string{dir, filepath.Join(dir, ".git"), filepath.Join(dir, "a"), filepath.Join(dir, "a", "b")}
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("WatchDirs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("WatchDirs = %v, want %v", got, want)
+			break
+		}
+	}
+	if !IsIgnored(dir, filepath.Join(dir, "ignored")) || IsIgnored(dir, filepath.Join(dir, "a")) {
+		t.Error("IsIgnored disagrees with .gitignore")
+	}
+}
@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds a fetch, so a remote that stops answering can't hold
+// up the callers waiting for it.
+const fetchTimeout = 2 * time.Minute
+
+// Fetch updates the remote-tracking branches of the repository at repoPath.
+// It runs in the background, where nobody can answer a prompt, so git fails
+// instead of asking for credentials or to confirm a host key.
+func (m *Manager) Fetch(repoPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "fetch", "--all", "--prune", "--quiet")
+	cmd.Env = append(os.Environ(), nonInteractiveEnv(repoPath)...)
+	cmd.WaitDelay = 5 * time.Second // for an ssh child still holding the output pipe
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("git fetch: no answer from the remote after %s", fetchTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("git fetch: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// nonInteractiveEnv disables every way git and ssh can prompt: the
+// terminal, askpass helpers and ssh's own questions. Credential helpers
+// still run. The repository's ssh command is kept, in batch mode.
+func nonInteractiveEnv(repoPath string) []string {
+	ssh := os.Getenv("GIT_SSH_COMMAND")
+	if ssh == "" {
+		ssh, _ = gitOutput(repoPath, "config", "core.sshCommand")
+	}
+	if ssh == "" {
+		ssh = "ssh"
+	}
+	return []string{
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ASKPASS=true",
+		"SSH_ASKPASS=true",
+		"GIT_SSH_COMMAND=" + ssh + " -o BatchMode=yes",
+	}
+}
+
+// AheadBehind counts the commits on branch that aren't on ref and the
+// commits on ref that aren't on branch.
+func (m *Manager) AheadBehind(path, branch, ref string) (ahead int, behind int, err error) {
+	out, err := gitOutput(path, "rev-list", "--left-right", "--count", ref+"..."+branch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("git rev-list: %w", err)
+	}
+	if _, err := fmt.Sscanf(out, "%d %d", &behind, &ahead); err != nil {
+		return 0, 0, fmt.Errorf("parse rev-list output %q: %w", out, err)
+	}
+	return ahead, behind, nil
+}
+
+// SyncRef returns the ref a branch based on base should keep up with: the
+// base's upstream when it has one, since that's what advances on fetch, or
+// base itself.
+func SyncRef(path, base string) string {
+	if up, err := gitOutput(path, "rev-parse", "--abbrev-ref", base+"@{upstream}"); err == nil && up != "" {
+		return up
+	}
+	return base
+}
+
+// AbortRebase abandons an in-progress rebase in worktreePath.
+func (m *Manager) AbortRebase(worktreePath string) error {
+	if out, err := exec.Command("git", "-C", worktreePath, "rebase", "--abort").CombinedOutput(); err != nil {
+		return fmt.Errorf("git rebase --abort: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// AbortMerge abandons an in-progress merge in worktreePath.
+func (m *Manager) AbortMerge(worktreePath string) error {
+	if out, err := exec.Command("git", "-C", worktreePath, "merge", "--abort").CombinedOutput(); err != nil {
+		return fmt.Errorf("git merge --abort: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package worktree
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestNonInteractiveEnvKeepsSSHCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init", "--quiet"}, {"config", "core.sshCommand", "ssh -i key"}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+
+	t.Setenv("GIT_SSH_COMMAND", "")
+	env := nonInteractiveEnv(dir)
+	if want := "GIT_SSH_COMMAND=ssh -i key -o BatchMode=yes"; env[len(env)-1] != want {
+		t.Errorf("from core.sshCommand: %q, want %q", env[len(env)-1], want)
+	}
+	t.Setenv("GIT_SSH_COMMAND", "ssh -p 2222")
+	env = nonInteractiveEnv(dir)
+	if want := "GIT_SSH_COMMAND=ssh -p 2222 -o BatchMode=yes"; env[len(env)-1] != want {
+		t.Errorf("from the environment: %q, want %q", env[len(env)-1], want)
+	}
+}
@@ -6,6 +6,8 @@ import SessionHeader from './components/SessionHeader'
 import AddRepositoryDialog from './components/AddRepositoryDialog'
 import SettingsDialog from './components/Settings'
 import ArchivePanel from './components/ArchivePanel'
+import RecoveryBanner from './components/RecoveryBanner'
+import RepoConfigDialog from './components/RepoConfigDialog'
 import LinearWorkspacesView from './components/linear/LinearWorkspacesView'
 import AgentDashboardView from './components/dashboard/AgentDashboardView'
 import { useAimStore, AgentType, SessionState, WorkspaceState } from './stores/sessions'
@@ -18,25 +20,11 @@ declare const window: Window & {
   }
 }
 
-/** Convert user's first prompt into a git branch name: aim/{slug} */
-function slugifyToBranch(text: string): string {
-  const slug = text
-    .toLowerCase()
-    .replace(/[^a-z0-9\s]/g, '')
-    .trim()
-    .split(/\s+/)
-    .slice(0, 6)
-    .join('-')
-    .replace(/-+/g, '-')
-    .replace(/-$/, '')
-    .slice(0, 50)
-  return `aim/${slug || 'session'}`
-}
-
 function App() {
   const [showAddRepo, setShowAddRepo] = useState(false)
   const [showSettings, setShowSettings] = useState(false)
   const [showArchive, setShowArchive] = useState(false)
+  const [configWorkspaceId, setConfigWorkspaceId] = useState<string | null>(null)
   const { activeView } = useNavigationStore()
 
   const {
@@ -46,24 +34,26 @@ function App() {
     updateStatus,
     updateBranch,
     addSession,
+    setActiveSession,
   } = useAimStore()
 
   // Flatten all sessions for lookup
   const allSessions = workspaces.flatMap((w) => w.sessions)
   const activeSession = allSessions.find((s) => s.id === activeSessionId) ?? null
 
-  // Load workspaces from backend on mount
-  useEffect(() => {
+  // Load workspaces from the backend
+  const loadWorkspaces = useCallback(() => {
     import('../wailsjs/go/workspace/Manager')
       .then(({ ListWorkspaces }) => ListWorkspaces())
       .then((list: any[]) => {
-        if (!list || list.length === 0) return
-        const mapped: WorkspaceState[] = list.map((ws) => ({
+        const mapped: WorkspaceState[] = (list ?? []).map((ws) => ({
           id: ws.id,
           name: ws.name,
           path: ws.path,
           agent: ws.agent as AgentType,
           cloned: ws.cloned ?? false,
+          repos: ws.repos ?? [],
+          configNeedsTrust: ws.configNeedsTrust ?? false,
           expanded: ws.sessions?.length > 0,
           sessions: (ws.sessions ?? []).map((s: any): SessionState => ({
             id: s.id,
@@ -76,6 +66,8 @@ function App() {
             status: 'stopped',
             archived: s.archived ?? false,
             archivedAt: s.archivedAt ?? undefined,
+            backup: s.backup ?? undefined,
+            linkedRepos: s.linkedRepos ?? undefined,
           })),
         }))
         setWorkspaces(mapped)
@@ -83,25 +75,69 @@ function App() {
       .catch(() => {})
   }, [setWorkspaces])
 
+  useEffect(() => { loadWorkspaces() }, [loadWorkspaces])
+
+  // A profile switch replaces every workspace and session
+  useEffect(() => {
+    window.runtime?.EventsOn('profile:changed', () => {
+      setActiveSession(null, null)
+      loadWorkspaces()
+    })
+    return () => window.runtime?.EventsOff('profile:changed')
+  }, [loadWorkspaces, setActiveSession])
+
   // Subscribe to status events
   useEffect(() => {
     allSessions.forEach((s) => {
       window.runtime?.EventsOn(`session:status:${s.id}`, (status: unknown) => {
         updateStatus(s.id, status as any)
       })
+      // The backend renames temporary branches after the first prompt
+      window.runtime?.EventsOn(`session:branch:${s.id}`, (branch: unknown) => {
+        updateBranch(s.id, branch as string)
+      })
     })
     return () => {
       allSessions.forEach((s) => {
         window.runtime?.EventsOff(`session:status:${s.id}`)
+        window.runtime?.EventsOff(`session:branch:${s.id}`)
       })
     }
-  }, [allSessions.length, updateStatus])
+  }, [allSessions.length, updateStatus, updateBranch])
 
   // [+ New session] — instantly creates a worktree with a temp branch, no dialog
-  const handleNewSession = useCallback(async (workspaceId: string) => {
+  const handleNewSession = useCallback(async (workspaceId: string, mode?: 'shared' | 'per-repo') => {
     const ws = workspaces.find((w) => w.id === workspaceId)
     if (!ws) return
 
+    // Multi-repo workspaces get a worktree on the same branch in every repo
+    if (ws.repos && ws.repos.length > 0) {
+      try {
+        const { StartTask } = await import('../wailsjs/go/workspace/Manager')
+        const { ListSessions } = await import('../wailsjs/go/session/Manager')
+        // The backend names the branch, following the naming policy
+        const ids = await StartTask({ workspaceId, mode: mode ?? 'shared' } as any)
+        const sessions = (await ListSessions()) as any[]
+        for (const s of sessions.filter((s) => ids.includes(s.id))) {
+          addSession({
+            id: s.id,
+            workspaceId,
+            name: s.name,
+            agent: s.agent as AgentType,
+            directory: s.directory,
+            worktreePath: s.worktreePath ?? '',
+            branch: s.branch ?? '',
+            status: s.status ?? 'idle',
+            archived: false,
+            linkedRepos: s.linkedRepos ?? undefined,
+          })
+        }
+      } catch (err) {
+        console.error('Failed to start task:', err)
+      }
+      return
+    }
+
     try {
       const { IsGitRepo, CreateWorktree } = await import('../wailsjs/go/worktree/Manager')
       const { CreateSession } = await import('../wailsjs/go/session/Manager')
@@ -148,21 +184,6 @@ function App() {
     }
   }, [workspaces, addSession])
 
-  // After the user's first message, rename the temp branch to a slug of that message
-  const handleFirstMessage = useCallback(async (sessionId: string, text: string) => {
-    const session = allSessions.find((s) => s.id === sessionId)
-    if (!session?.branch.startsWith('aim/tmp-')) return
-
-    const newBranch = slugifyToBranch(text)
-    try {
-      const { RenameSessionBranch } = await import('../wailsjs/go/session/Manager')
-      await RenameSessionBranch(sessionId, newBranch)
-      updateBranch(sessionId, newBranch)
-    } catch (err) {
-      console.error('Failed to rename branch:', err)
-    }
-  }, [allSessions, updateBranch])
-
   return (
     <div className="flex h-screen w-screen overflow-hidden bg-[#0f1117]">
       <Sidebar
@@ -170,19 +191,19 @@ function App() {
         onSettings={() => setShowSettings(true)}
         onNewSession={handleNewSession}
         onArchivePanel={() => setShowArchive(true)}
+        onRepoConfig={setConfigWorkspaceId}
       />
 
       <div className="flex flex-col flex-1 min-w-0">
+        <RecoveryBanner />
+
         {activeView === 'workspaces' && (
           <>
             {activeSession ? (
               <>
                 <SessionHeader session={activeSession} />
                 <div className="flex-1 min-h-0">
-                  <Terminal
-                    sessionId={activeSession.id}
-                    onFirstMessage={(text) => handleFirstMessage(activeSession.id, text)}
-                  />
+                  <Terminal sessionId={activeSession.id} />
                 </div>
               </>
             ) : (
@@ -214,6 +235,9 @@ function App() {
       {showAddRepo && <AddRepositoryDialog onClose={() => setShowAddRepo(false)} />}
       {showSettings && <SettingsDialog onClose={() => setShowSettings(false)} />}
       {showArchive && <ArchivePanel onClose={() => setShowArchive(false)} />}
+      {configWorkspaceId && (
+        <RepoConfigDialog workspaceId={configWorkspaceId} onClose={() => setConfigWorkspaceId(null)} />
+      )}
     </div>
   )
 }
@@ -3,6 +3,21 @@ import { useAimStore, AgentType, SessionState } from '../stores/sessions'
 
 type Tab = 'open' | 'clone'
 
+interface CloneJob {
+  id: string
+  status: 'running' | 'done' | 'failed' | 'cancelled'
+  progress: { phase: string; percent: number; current: number; total: number }
+  workspaceId?: string
+  error?: string
+}
+
+declare const window: Window & {
+  runtime?: {
+    EventsOn: (event: string, callback: (...args: unknown[]) => void) => void
+    EventsOff: (event: string) => void
+  }
+}
+
 const agents: { id: AgentType; label: string }[] = [
   { id: 'claude', label: 'Claude Code' },
   { id: 'codex', label: 'OpenAI Codex' },
@@ -21,15 +36,24 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
   // Open tab state
   const [localPath, setLocalPath] = useState('')
   const [openName, setOpenName] = useState('')
+  const [extraRepos, setExtraRepos] = useState<string[]>([])
 
   // Clone tab state
   const [repoUrl, setRepoUrl] = useState('')
   const [cloneName, setCloneName] = useState('')
   const [cloneDest, setCloneDest] = useState('')
   const [reposBaseDir, setReposBaseDir] = useState('~/.aim/repos')
+  const [showCloneOptions, setShowCloneOptions] = useState(false)
+  const [depth, setDepth] = useState('')
+  const [blobless, setBlobless] = useState(false)
+  const [cloneBranch, setCloneBranch] = useState('')
+  const [singleBranch, setSingleBranch] = useState(false)
+  const [submodules, setSubmodules] = useState(false)
+  const [useCache, setUseCache] = useState(true)
 
   const [loading, setLoading] = useState(false)
   const [cloneProgress, setCloneProgress] = useState('')
+  const [cloneJobId, setCloneJobId] = useState<string | null>(null)
   const [error, setError] = useState('')
 
   // Load reposBaseDir from settings
@@ -81,12 +105,24 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
     }
   }, [openName])
 
+  const handleAddExtraRepo = useCallback(async () => {
+    try {
+      const { OpenDirectoryDialog } = await import('../../wailsjs/go/main/App')
+      const path = await OpenDirectoryDialog('Select another repository')
+      if (path && path !== localPath && !extraRepos.includes(path)) {
+        setExtraRepos([...extraRepos, path])
+      }
+    } catch {
+      setError('Could not open directory picker')
+    }
+  }, [localPath, extraRepos])
+
   const handleCreate = useCallback(async () => {
     setError('')
     setLoading(true)
 
     try {
-      const { AddWorkspace, CloneAndAddWorkspace, ListWorkspaces } =
+      const { AddWorkspace, StartClone, ListWorkspaces } =
         await import('../../wailsjs/go/workspace/Manager')
 
       let workspaceId: string
@@ -99,17 +135,41 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
           agent,
           repoUrl: '',
           reposBaseDir: '',
-        })
+          repos: extraRepos,
+        } as any)
       } else {
         if (!repoUrl.trim()) throw new Error('Enter a Git URL')
         setCloneProgress('Cloning repository…')
-        workspaceId = await CloneAndAddWorkspace({
+        const jobId = await StartClone({
           repoUrl: repoUrl.trim(),
           reposBaseDir,
           name: cloneName || undefined,
           agent,
           path: '',
+          clone: {
+            depth: parseInt(depth, 10) || 0,
+            filter: blobless ? 'blob:none' : '',
+            branch: cloneBranch.trim(),
+            singleBranch,
+            recurseSubmodules: submodules,
+            useCache,
+          },
+        } as any)
+        setCloneJobId(jobId)
+        workspaceId = await new Promise<string>((resolve, reject) => {
+          window.runtime?.EventsOn(`clone:job:${jobId}`, (data: unknown) => {
+            const job = data as CloneJob
+            if (job.status === 'running') {
+              const { phase, percent, current, total } = job.progress
+              if (phase) setCloneProgress(`${phase} ${percent}% (${current}/${total})`)
+              return
+            }
+            window.runtime?.EventsOff(`clone:job:${jobId}`)
+            if (job.status === 'done' && job.workspaceId) resolve(job.workspaceId)
+            else reject(new Error(job.error || 'Clone failed'))
+          })
         })
+        setCloneJobId(null)
         setCloneProgress('')
       }
 
@@ -122,6 +182,7 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
           path: ws.path,
           agent: ws.agent as AgentType,
           cloned: ws.cloned ?? false,
+          repos: ws.repos ?? [],
           expanded: true,
           sessions: (ws.sessions ?? []).map((s: any): SessionState => ({
             id: s.id,
@@ -140,12 +201,23 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
 
       onClose()
     } catch (err: any) {
+      setCloneJobId(null)
       setCloneProgress('')
       setError(err?.message ?? String(err))
     } finally {
       setLoading(false)
     }
-  }, [tab, localPath, openName, repoUrl, cloneName, reposBaseDir, agent, addWorkspace, onClose])
+  }, [tab, localPath, openName, extraRepos, repoUrl, cloneName, reposBaseDir, depth, blobless, cloneBranch, singleBranch, submodules, useCache, agent, addWorkspace, onClose])
+
+  const handleCancelClone = useCallback(async () => {
+    if (!cloneJobId) return
+    try {
+      const { CancelClone } = await import('../../wailsjs/go/workspace/Manager')
+      await CancelClone(cloneJobId)
+    } catch (err) {
+      console.error('Cancel clone failed:', err)
+    }
+  }, [cloneJobId])
 
   return (
     <div className="fixed inset-0 z-50 flex items-center justify-center bg-black/60 backdrop-blur-sm">
@@ -209,6 +281,29 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
                 </button>
               </div>
             </div>
+            <div className="mb-4">
+              <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">Other repositories</label>
+              {extraRepos.map((repo) => (
+                <div key={repo} className="flex items-center gap-2 mb-1">
+                  <span className="flex-1 text-xs text-slate-300 font-mono truncate">{repo}</span>
+                  <button
+                    onClick={() => setExtraRepos(extraRepos.filter((r) => r !== repo))}
+                    className="text-xs text-slate-500 hover:text-red-400 transition-colors"
+                  >
+                    Remove
+                  </button>
+                </div>
+              ))}
+              <button
+                onClick={handleAddExtraRepo}
+                className="text-xs text-indigo-400 hover:text-indigo-300 transition-colors"
+              >
+                + Add repository
+              </button>
+              {extraRepos.length > 0 && (
+                <p className="text-[10px] text-slate-600 mt-1">New sessions get a worktree on the same branch in every repository</p>
+              )}
+            </div>
             <div className="mb-5">
               <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">Name</label>
               <input
@@ -248,6 +343,55 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
                 className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-2 text-sm text-slate-200 placeholder-slate-600 focus:outline-none focus:border-indigo-500"
               />
             </div>
+            <div className="mb-5">
+              <button
+                onClick={() => setShowCloneOptions(!showCloneOptions)}
+                className="text-xs text-slate-400 hover:text-slate-200 uppercase tracking-wide transition-colors"
+              >
+                {showCloneOptions ? '▾' : '▸'} Clone options
+              </button>
+              {showCloneOptions && (
+                <div className="mt-3 space-y-3">
+                  <div className="flex gap-3">
+                    <div className="flex-1">
+                      <label className="block text-xs text-slate-500 mb-1">Branch</label>
+                      <input
+                        type="text"
+                        value={cloneBranch}
+                        onChange={(e) => setCloneBranch(e.target.value)}
+                        placeholder="default"
+                        className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-1.5 text-sm text-slate-200 placeholder-slate-600 font-mono focus:outline-none focus:border-indigo-500"
+                      />
+                    </div>
+                    <div className="w-28">
+                      <label className="block text-xs text-slate-500 mb-1">Depth</label>
+                      <input
+                        type="number"
+                        min={0}
+                        value={depth}
+                        onChange={(e) => setDepth(e.target.value)}
+                        placeholder="full"
+                        className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-1.5 text-sm text-slate-200 placeholder-slate-600 focus:outline-none focus:border-indigo-500"
+                      />
+                    </div>
+                  </div>
+                  {([
+                    [singleBranch, setSingleBranch, 'Only fetch this branch'],
+                    [blobless, setBlobless, 'Download file contents on demand (blob:none)'],
+                    [submodules, setSubmodules, 'Clone submodules'],
+                    [useCache, setUseCache, 'Reuse objects cached from other clones of this repo or its forks'],
+                  ] as [boolean, (v: boolean) => void, string][]).map(([checked, set, label]) => (
+                    <label key={label} className="flex items-center gap-2 text-sm text-slate-300">
+                      <input type="checkbox" checked={checked} onChange={(e) => set(e.target.checked)} />
+                      {label}
+                    </label>
+                  ))}
+                  {useCache && (depth || blobless) && (
+                    <p className="text-xs text-slate-500">The object cache isn't used for shallow or partial clones.</p>
+                  )}
+                </div>
+              )}
+            </div>
           </>
         )}
 
@@ -257,7 +401,15 @@ export default function AddRepositoryDialog({ onClose }: AddRepositoryDialogProp
               <circle cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4" strokeOpacity="0.2"/>
               <path d="M12 2a10 10 0 0 1 10 10" stroke="currentColor" strokeWidth="4" strokeLinecap="round"/>
             </svg>
-            {cloneProgress}
+            <span className="flex-1">{cloneProgress}</span>
+            {cloneJobId && (
+              <button
+                onClick={handleCancelClone}
+                className="text-xs text-slate-400 hover:text-red-400 transition-colors"
+              >
+                Cancel
+              </button>
+            )}
           </div>
         )}
 
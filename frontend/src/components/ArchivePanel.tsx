@@ -21,8 +21,9 @@ function daysAgo(iso?: string): string | null {
 }
 
 function ArchivedRow({ session, workspaceName }: { session: SessionState; workspaceName: string }) {
-  const { unarchiveSession, deleteArchivedSession } = useAimStore()
+  const { unarchiveSession, deleteArchivedSession, restoreWorktree } = useAimStore()
   const [confirmDelete, setConfirmDelete] = useState(false)
+  const [backupError, setBackupError] = useState<string | null>(null)
 
   const handleRestore = async () => {
     try {
@@ -34,6 +35,19 @@ function ArchivedRow({ session, workspaceName }: { session: SessionState; worksp
     }
   }
 
+  const handleRestoreChanges = async () => {
+    try {
+      const { RestoreSessionBackup } = await import('../../wailsjs/go/workspace/Manager')
+      const { GetSession } = await import('../../wailsjs/go/session/Manager')
+      const path = await RestoreSessionBackup(session.id)
+      const restored = (await GetSession(session.id)) as any
+      restoreWorktree(session.id, path, restored.linkedRepos ?? undefined)
+      setBackupError(null)
+    } catch (err) {
+      setBackupError(String(err))
+    }
+  }
+
   const handleOpenFinder = async () => {
     const dir = session.worktreePath || session.directory
     if (!dir) return
@@ -61,6 +75,9 @@ function ArchivedRow({ session, workspaceName }: { session: SessionState; worksp
   }
 
   const workDir = session.worktreePath || session.directory
+  // Linked repositories' worktrees are backed up separately
+  const backups = [session.backup, ...(session.linkedRepos ?? []).map((l) => l.backup)].filter((b) => !!b)
+  const backedUpFiles = backups.reduce((n, b) => n + b!.files, 0)
 
   return (
     <div className="flex items-center gap-2 px-3 py-2 rounded-lg hover:bg-slate-800/50 group">
@@ -80,6 +97,11 @@ function ArchivedRow({ session, workspaceName }: { session: SessionState; worksp
         <p className="text-xs text-slate-600 truncate" title={workDir}>
           {workspaceName} · {workDir}
         </p>
+        {backups.length > 0 && (
+          <p className="text-xs text-amber-500/80 truncate" title={backupError ?? backups.map((b) => b!.ref).join('\n')}>
+            {backupError ?? `${backedUpFiles} uncommitted file${backedUpFiles !== 1 ? 's' : ''} backed up`}
+          </p>
+        )}
       </div>
 
       {/* Archived time */}
@@ -98,6 +120,17 @@ function ArchivedRow({ session, workspaceName }: { session: SessionState; worksp
           Restore
         </button>
 
+        {/* Restore backed-up changes */}
+        {backups.length > 0 && (
+          <button
+            onClick={handleRestoreChanges}
+            title="Recreate the worktree with the changes saved when it was removed"
+            className="text-xs px-2 py-1 bg-amber-800/60 hover:bg-amber-700 text-amber-300 rounded transition-colors"
+          >
+            Restore changes
+          </button>
+        )}
+
         {/* Open in Finder */}
         {workDir && (
           <button
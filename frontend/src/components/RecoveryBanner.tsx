@@ -0,0 +1,92 @@
+import { useEffect, useState } from 'react'
+
+declare const window: Window & {
+  runtime?: {
+    EventsOn: (event: string, callback: (...args: unknown[]) => void) => void
+    EventsOff: (event: string) => void
+  }
+}
+
+interface Recovery {
+  file: string
+  backup: string
+  corruptCopy: string
+  error: string
+}
+
+interface SaveFailure {
+  file: string
+  error: string
+}
+
+function baseName(path: string) {
+  return path.split(/[\\/]/).pop() ?? path
+}
+
+// RecoveryBanner tells the user about state files that were unreadable and
+// restored from backup, or that couldn't be saved. Most recoveries happen at
+// startup, before the frontend could hear the event, so the list is fetched
+// on load as well.
+export default function RecoveryBanner() {
+  const [recoveries, setRecoveries] = useState<Recovery[]>([])
+  const [failures, setFailures] = useState<SaveFailure[]>([])
+
+  useEffect(() => {
+    const load = () => {
+      import('../../wailsjs/go/main/App')
+        .then(({ GetRecoveries }) => GetRecoveries())
+        .then((list: any[]) => setRecoveries(list ?? []))
+        .catch(() => {})
+    }
+    const failed = (...args: unknown[]) => {
+      const f = args[0] as SaveFailure
+      setFailures((prev) => [...prev.filter((p) => p.file !== f.file), f])
+    }
+    load()
+    window.runtime?.EventsOn('storage:recovered', load)
+    window.runtime?.EventsOn('storage:save-failed', failed)
+    return () => {
+      window.runtime?.EventsOff('storage:recovered')
+      window.runtime?.EventsOff('storage:save-failed')
+    }
+  }, [])
+
+  if (recoveries.length === 0 && failures.length === 0) return null
+
+  const dismiss = async () => {
+    try {
+      const { DismissRecoveries } = await import('../../wailsjs/go/main/App')
+      await DismissRecoveries()
+    } catch (err) {
+      console.error('Dismiss recoveries failed:', err)
+    }
+    setRecoveries([])
+    setFailures([])
+  }
+
+  return (
+    <div className="flex items-start gap-3 px-4 py-2 bg-amber-950/60 border-b border-amber-800 text-xs text-amber-200">
+      <ul className="flex-1 min-w-0 space-y-1">
+        {recoveries.map((r) => (
+          <li key={r.file + r.corruptCopy} className="truncate" title={r.error}>
+            {r.backup
+              ? `${baseName(r.file)} was unreadable and has been restored from its backup.`
+              : `${baseName(r.file)} was unreadable and no usable backup was found.`}
+            {r.corruptCopy && <span className="text-amber-400/70"> The damaged copy is at {r.corruptCopy}.</span>}
+          </li>
+        ))}
+        {failures.map((f) => (
+          <li key={f.file} className="truncate" title={f.error}>
+            {`${baseName(f.file)} couldn't be saved; recent changes may be lost when aim quits.`}
+          </li>
+        ))}
+      </ul>
+      <button
+        className="px-2 py-0.5 rounded text-amber-300 hover:bg-amber-900/60 transition-colors"
+        onClick={dismiss}
+      >
+        Dismiss
+      </button>
+    </div>
+  )
+}
@@ -0,0 +1,250 @@
+import { useCallback, useEffect, useState } from 'react'
+import { useAimStore } from '../stores/sessions'
+
+interface RepoConfigDialogProps {
+  workspaceId: string
+  onClose: () => void
+}
+
+interface Bootstrap {
+  copy?: string[]
+  symlink?: string[]
+  commands?: string[]
+}
+
+interface RepoConfig {
+  agent?: string
+  setup?: Bootstrap
+  env?: Record<string, string>
+  branchPrefix?: string
+  testCommand?: string
+}
+
+interface RepoSetup {
+  path: string
+  file?: string
+  hash?: string
+  error?: string
+  trusted: boolean
+  setup?: Bootstrap
+  pending?: string[]
+}
+
+interface EffectiveConfig extends RepoConfig {
+  sources: Record<string, string>
+  file?: string
+  hash?: string
+  error?: string
+  trusted: boolean
+  pending?: RepoConfig
+  repos?: RepoSetup[]
+}
+
+const sourceBadge: Record<string, string> = {
+  repo:    'bg-indigo-900 text-indigo-300',
+  local:   'bg-green-900 text-green-300',
+  default: 'bg-slate-800 text-slate-500',
+}
+
+function Field({ label, source, children }: { label: string; source?: string; children: React.ReactNode }) {
+  return (
+    <div className="py-2 border-b border-slate-800/60">
+      <div className="flex items-center gap-2 mb-1">
+        <span className="text-xs font-medium text-slate-400">{label}</span>
+        {source && (
+          <span className={`text-[10px] px-1.5 py-0.5 rounded ${sourceBadge[source] ?? sourceBadge.default}`}>
+            {source}
+          </span>
+        )}
+      </div>
+      <div className="text-xs font-mono text-slate-300 break-all">{children}</div>
+    </div>
+  )
+}
+
+function List({ items }: { items?: string[] }) {
+  if (!items || items.length === 0) return <span className="text-slate-600">—</span>
+  return (
+    <ul className="space-y-0.5">
+      {items.map((item) => <li key={item}>{item}</li>)}
+    </ul>
+  )
+}
+
+function baseName(path: string) {
+  return path.split(/[\\/]/).pop() ?? path
+}
+
+function Pairs({ values }: { values?: Record<string, string> }) {
+  const keys = Object.keys(values ?? {}).sort()
+  if (keys.length === 0) return <span className="text-slate-600">—</span>
+  return (
+    <ul className="space-y-0.5">
+      {keys.map((k) => <li key={k}>{k}={values![k]}</li>)}
+    </ul>
+  )
+}
+
+// RepoConfigDialog shows the config sessions in a workspace run with and
+// where each field comes from. Setup commands and env from a repository's
+// .aim file only apply once the user trusts that version of the file.
+export default function RepoConfigDialog({ workspaceId, onClose }: RepoConfigDialogProps) {
+  const { workspaces, setConfigTrusted } = useAimStore()
+  const workspace = workspaces.find((w) => w.id === workspaceId)
+  const [config, setConfig] = useState<EffectiveConfig | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [trusting, setTrusting] = useState(false)
+
+  const load = useCallback(async () => {
+    try {
+      const { GetEffectiveConfig } = await import('../../wailsjs/go/workspace/Manager')
+      setConfig((await GetEffectiveConfig(workspaceId)) as any)
+      setError(null)
+    } catch (err) {
+      setError(String(err))
+    }
+  }, [workspaceId])
+
+  useEffect(() => { load() }, [load])
+
+  useEffect(() => {
+    const handler = (e: KeyboardEvent) => { if (e.key === 'Escape') onClose() }
+    document.addEventListener('keydown', handler)
+    return () => document.removeEventListener('keydown', handler)
+  }, [onClose])
+
+  const handleTrust = async () => {
+    setTrusting(true)
+    try {
+      const { TrustRepoConfig } = await import('../../wailsjs/go/workspace/Manager')
+      // Trust exactly the versions shown; the backend refuses if any changed since.
+      const repoHashes: Record<string, string> = {}
+      for (const r of config?.repos ?? []) repoHashes[r.path] = r.hash ?? ''
+      await TrustRepoConfig(workspaceId, config?.hash ?? '', repoHashes)
+      setConfigTrusted(workspaceId)
+      await load()
+    } catch (err) {
+      // Show what's on disk now so the user reviews that instead.
+      await load()
+      setError(String(err))
+    } finally {
+      setTrusting(false)
+    }
+  }
+
+  const sources = config?.sources ?? {}
+  const pendingRepos = (config?.repos ?? []).filter((r) => (r.pending?.length ?? 0) > 0)
+  const needsTrust = !!config?.pending || pendingRepos.length > 0
+
+  return (
+    <div className="fixed inset-0 z-50 flex">
+      {/* Backdrop */}
+      <div className="flex-1 bg-black/50" onClick={onClose} />
+
+      {/* Panel */}
+      <div className="w-[480px] h-full bg-[#131620] border-l border-slate-800 flex flex-col">
+        {/* Header */}
+        <div className="flex items-center justify-between px-5 py-4 border-b border-slate-800">
+          <div className="min-w-0">
+            <h2 className="text-base font-semibold text-white">Repository Config</h2>
+            <p className="text-xs text-slate-500 mt-0.5 truncate">
+              {workspace?.name ?? workspaceId}
+              {config?.file ? ` · ${config.file}` : ' · no .aim file'}
+            </p>
+          </div>
+          <button
+            onClick={onClose}
+            className="text-slate-500 hover:text-slate-300 transition-colors"
+          >
+            <svg aria-hidden="true" width="18" height="18" viewBox="0 0 24 24" fill="none" stroke="currentColor" strokeWidth="2.5">
+              <line x1="18" y1="6" x2="6" y2="18" /><line x1="6" y1="6" x2="18" y2="18" />
+            </svg>
+          </button>
+        </div>
+
+        {/* Content */}
+        <div className="flex-1 overflow-y-auto px-5 py-3">
+          {error && <p className="text-xs text-red-400 mb-3">{error}</p>}
+          {config?.error && (
+            <p className="text-xs text-red-400 mb-3">The repository config was ignored: {config.error}</p>
+          )}
+
+          {config && needsTrust && (
+            <div className="mb-4 p-3 rounded-md border border-amber-800 bg-amber-950/40">
+              <p className="text-xs text-amber-200 mb-2">
+                This workspace's repository config wants to run commands in new worktrees or set
+                environment variables for agents. They are held back until you trust this version of
+                the files.
+              </p>
+              {(config.pending?.setup?.commands?.length ?? 0) > 0 && (
+                <Field label="Setup commands">
+                  <List items={config.pending?.setup?.commands} />
+                </Field>
+              )}
+              {config.pending?.testCommand && (
+                <Field label="Test command">{config.pending.testCommand}</Field>
+              )}
+              {Object.keys(config.pending?.env ?? {}).length > 0 && (
+                <Field label="Environment">
+                  <Pairs values={config.pending?.env} />
+                </Field>
+              )}
+              {pendingRepos.map((r) => (
+                <Field key={r.path} label={`Setup commands in ${baseName(r.path)}`}>
+                  <List items={r.pending} />
+                </Field>
+              ))}
+              <button
+                onClick={handleTrust}
+                disabled={trusting}
+                className="mt-3 px-3 py-1.5 text-xs rounded-md bg-amber-600 hover:bg-amber-500 text-white transition-colors disabled:opacity-50"
+              >
+                {trusting ? 'Trusting…' : 'Trust this config'}
+              </button>
+            </div>
+          )}
+
+          {config?.file && !needsTrust && !config.error && (
+            <p className="text-xs text-slate-500 mb-3">
+              {config.trusted ? 'This version of the config is trusted.' : 'Nothing in this config needs approval.'}
+            </p>
+          )}
+
+          {config && (
+            <>
+              <Field label="Agent" source={sources.agent}>{config.agent || '—'}</Field>
+              <Field label="Branch prefix" source={sources.branchPrefix}>{config.branchPrefix || '—'}</Field>
+              <Field label="Test command" source={sources.testCommand}>{config.testCommand || '—'}</Field>
+              <Field label="Setup: copy" source={sources.setup}><List items={config.setup?.copy} /></Field>
+              <Field label="Setup: symlink" source={sources.setup}><List items={config.setup?.symlink} /></Field>
+              <Field label="Setup: commands" source={sources.setup}><List items={config.setup?.commands} /></Field>
+              <Field label="Environment" source={sources.env}><Pairs values={config.env} /></Field>
+            </>
+          )}
+
+          {(config?.repos?.length ?? 0) > 0 && (
+            <div className="mt-5">
+              <h3 className="text-xs font-semibold text-slate-400 mb-1">Other repositories</h3>
+              <p className="text-xs text-slate-600 mb-2">
+                Their worktrees are prepared by each repository's own setup.
+              </p>
+              {config!.repos!.map((r) => (
+                <Field key={r.path} label={baseName(r.path)} source={r.file ? 'repo' : 'default'}>
+                  {r.error ? (
+                    <span className="text-red-400">{r.error}</span>
+                  ) : (
+                    <List items={[
+                      ...(r.setup?.copy ?? []).map((c) => `copy ${c}`),
+                      ...(r.setup?.symlink ?? []).map((c) => `symlink ${c}`),
+                      ...(r.setup?.commands ?? []).map((c) => `$ ${c}`),
+                    ]} />
+                  )}
+                </Field>
+              ))}
+            </div>
+          )}
+        </div>
+      </div>
+    </div>
+  )
+}
@@ -5,6 +5,7 @@ interface SessionHeaderProps {
 }
 
 const statusLabels: Record<SessionStatus, string> = {
+  starting: 'Setting up…',
   idle:     'Idle',
   thinking: 'Thinking…',
   waiting:  'Waiting',
@@ -13,6 +14,7 @@ const statusLabels: Record<SessionStatus, string> = {
 }
 
 const statusColors: Record<SessionStatus, string> = {
+  starting: 'text-sky-400',
   idle:     'text-emerald-400',
   thinking: 'text-yellow-400',
   waiting:  'text-orange-400',
@@ -1,4 +1,4 @@
-import { useState, useEffect } from 'react'
+import { useState, useEffect, useCallback } from 'react'
 import { useLinearStore } from '../stores/linear'
 
 interface SettingsData {
@@ -13,6 +13,23 @@ interface SettingsData {
   linearClientId: string
   reposBaseDir: string
   archiveWorktreeCleanupDays: number
+  worktreeRoot: string
+  cloneCacheDir: string
+  branchNaming: BranchNaming
+}
+
+interface BranchNaming {
+  prefix: string
+  includeIssue: boolean
+  maxWords: number
+  maxLength: number
+  useAgent: boolean
+}
+
+interface Profile {
+  name: string
+  dir: string
+  active: boolean
 }
 
 interface SettingsProps {
@@ -38,12 +55,21 @@ export default function Settings({ onClose }: SettingsProps) {
     linearClientId: '',
     reposBaseDir: '',
     archiveWorktreeCleanupDays: 7,
+    worktreeRoot: '',
+    cloneCacheDir: '',
+    branchNaming: { prefix: 'aim/', includeIssue: true, maxWords: 6, maxLength: 50, useAgent: false },
   })
   const [saved, setSaved] = useState(false)
+  const [migration, setMigration] = useState<string | null>(null)
   const [linearStatus, setLinearStatus] = useState<'disconnected' | 'connected' | 'checking'>('checking')
+  const [profiles, setProfiles] = useState<Profile[]>([])
+  const [newProfile, setNewProfile] = useState('')
+  const [profileError, setProfileError] = useState<string | null>(null)
+  const [switching, setSwitching] = useState(false)
+  const [confirmProfile, setConfirmProfile] = useState<string | null>(null) // "switch:<name>" or "delete:<name>"
   const linearStore = useLinearStore()
 
-  useEffect(() => {
+  const loadSettings = useCallback(() => {
     import('../../wailsjs/go/settings/Manager')
       .then(({ GetSettings }) => GetSettings())
       .then((s: any) => {
@@ -57,6 +83,20 @@ export default function Settings({ onClose }: SettingsProps) {
       .catch(() => setLinearStatus('disconnected'))
   }, [])
 
+  const loadProfiles = useCallback(async () => {
+    try {
+      const { ListProfiles } = await import('../../wailsjs/go/main/App')
+      setProfiles(((await ListProfiles()) ?? []) as Profile[])
+    } catch (err) {
+      setProfileError(String(err))
+    }
+  }, [])
+
+  useEffect(() => {
+    loadSettings()
+    loadProfiles()
+  }, [loadSettings, loadProfiles])
+
   const checkLinearConnection = async () => {
     try {
       const { IsConnected } = await import('../../wailsjs/go/linear/Manager')
@@ -84,6 +124,72 @@ export default function Settings({ onClose }: SettingsProps) {
     }
   }
 
+  const handleCreateProfile = async () => {
+    const name = newProfile.trim()
+    if (!name) return
+    try {
+      const { CreateProfile } = await import('../../wailsjs/go/main/App')
+      await CreateProfile(name)
+      setNewProfile('')
+      setProfileError(null)
+      await loadProfiles()
+    } catch (err) {
+      setProfileError(String(err))
+    }
+  }
+
+  // Switching stops every running session and reloads the app's state from
+  // the other profile, including these settings.
+  const handleSwitchProfile = async (name: string) => {
+    if (confirmProfile !== `switch:${name}`) {
+      setConfirmProfile(`switch:${name}`)
+      return
+    }
+    setConfirmProfile(null)
+    setSwitching(true)
+    try {
+      const { SwitchProfile } = await import('../../wailsjs/go/main/App')
+      await SwitchProfile(name)
+      setProfileError(null)
+      await loadProfiles()
+      loadSettings()
+    } catch (err) {
+      setProfileError(String(err))
+    } finally {
+      setSwitching(false)
+    }
+  }
+
+  const handleDeleteProfile = async (name: string) => {
+    if (confirmProfile !== `delete:${name}`) {
+      setConfirmProfile(`delete:${name}`)
+      return
+    }
+    setConfirmProfile(null)
+    try {
+      const { DeleteProfile } = await import('../../wailsjs/go/main/App')
+      await DeleteProfile(name)
+      setProfileError(null)
+      await loadProfiles()
+    } catch (err) {
+      setProfileError(String(err))
+    }
+  }
+
+  const handleMigrateWorktrees = async () => {
+    setMigration('Moving worktrees…')
+    try {
+      const { MigrateWorktrees } = await import('../../wailsjs/go/workspace/Manager')
+      const results = await MigrateWorktrees()
+      const moved = results.filter((r) => r.moved).length
+      const skipped = results.filter((r) => r.skipped).length
+      const failed = results.filter((r) => r.error).length
+      setMigration(`${moved} moved, ${skipped} skipped (running), ${failed} failed`)
+    } catch (err) {
+      setMigration(String(err))
+    }
+  }
+
   const handleBrowseRepoDir = async () => {
     try {
       const { OpenDirectoryDialog } = await import('../../wailsjs/go/main/App')
@@ -106,6 +212,70 @@ export default function Settings({ onClose }: SettingsProps) {
           </button>
         </div>
 
+        {/* Profiles */}
+        <div className="mb-4">
+          <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
+            Profile
+          </label>
+          <div className="space-y-1">
+            {profiles.map((p) => (
+              <div
+                key={p.name}
+                className={`flex items-center gap-2 px-3 py-1.5 rounded-lg border text-sm ${
+                  p.active ? 'bg-indigo-950/50 border-indigo-700 text-white' : 'bg-slate-800 border-slate-700 text-slate-300'
+                }`}
+              >
+                <span className="flex-1 truncate" title={p.dir}>{p.name}</span>
+                {p.active ? (
+                  <span className="text-[10px] text-indigo-300">active</span>
+                ) : (
+                  <>
+                    <button
+                      onClick={() => handleSwitchProfile(p.name)}
+                      onBlur={() => setConfirmProfile(null)}
+                      disabled={switching}
+                      title={confirmProfile === `switch:${p.name}` ? 'Running sessions will be stopped' : undefined}
+                      className="text-xs text-indigo-400 hover:text-indigo-300 transition-colors disabled:opacity-50"
+                    >
+                      {confirmProfile === `switch:${p.name}` ? 'Stop sessions and switch?' : 'Switch'}
+                    </button>
+                    <button
+                      onClick={() => handleDeleteProfile(p.name)}
+                      onBlur={() => setConfirmProfile(null)}
+                      disabled={switching}
+                      title={confirmProfile === `delete:${p.name}` ? 'Deletes its workspaces, sessions and settings' : undefined}
+                      className={`text-xs transition-colors disabled:opacity-50 ${
+                        confirmProfile === `delete:${p.name}` ? 'text-red-400' : 'text-slate-500 hover:text-red-400'
+                      }`}
+                    >
+                      {confirmProfile === `delete:${p.name}` ? 'Delete everything?' : 'Delete'}
+                    </button>
+                  </>
+                )}
+              </div>
+            ))}
+          </div>
+          <div className="flex gap-2 mt-2">
+            <input
+              type="text"
+              value={newProfile}
+              onChange={(e) => setNewProfile(e.target.value)}
+              onKeyDown={(e) => { if (e.key === 'Enter') handleCreateProfile() }}
+              placeholder="New profile name"
+              className="flex-1 bg-slate-800 border border-slate-700 rounded-lg px-3 py-1.5 text-sm text-slate-200 placeholder-slate-600 focus:outline-none focus:border-indigo-500"
+            />
+            <button
+              onClick={handleCreateProfile}
+              disabled={!newProfile.trim()}
+              className="px-3 py-1.5 bg-slate-800 border border-slate-700 hover:border-slate-600 rounded-lg text-xs text-slate-400 hover:text-slate-200 transition-colors disabled:opacity-50"
+            >
+              Create
+            </button>
+          </div>
+          {profileError && <p className="text-[10px] text-red-400 mt-1">{profileError}</p>}
+          <p className="text-[10px] text-slate-600 mt-1">Each profile has its own workspaces, sessions, settings and Linear account</p>
+        </div>
+
         {/* Default Agent */}
         <div className="mb-4">
           <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
@@ -172,6 +342,78 @@ export default function Settings({ onClose }: SettingsProps) {
           </div>
         </div>
 
+        {/* Worktree root */}
+        <div className="mb-4">
+          <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
+            Worktree Root
+          </label>
+          <input
+            type="text"
+            value={settings.worktreeRoot}
+            onChange={(e) => setSettings((s) => ({ ...s, worktreeRoot: e.target.value }))}
+            placeholder="Inside each repo's .git directory"
+            className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-2 text-sm text-slate-200 font-mono placeholder-slate-600 focus:outline-none focus:border-indigo-500"
+          />
+          <div className="flex items-center justify-between mt-1">
+            <p className="text-[10px] text-slate-600">New worktrees go to &lt;root&gt;/&lt;host&gt;/&lt;org&gt;/&lt;repo&gt;-&lt;hash&gt;/&lt;branch&gt;</p>
+            <button
+              onClick={handleMigrateWorktrees}
+              className="text-[10px] text-indigo-400 hover:text-indigo-300 transition-colors"
+            >
+              Move existing worktrees
+            </button>
+          </div>
+          {migration && <p className="text-[10px] text-slate-500 mt-1">{migration}</p>}
+        </div>
+
+        {/* Clone object cache */}
+        <div className="mb-4">
+          <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
+            Clone Object Cache
+          </label>
+          <input
+            type="text"
+            value={settings.cloneCacheDir}
+            onChange={(e) => setSettings((s) => ({ ...s, cloneCacheDir: e.target.value }))}
+            placeholder="~/.aim/cache"
+            className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-2 text-sm text-slate-200 font-mono placeholder-slate-600 focus:outline-none focus:border-indigo-500"
+          />
+          <p className="text-[10px] text-slate-600 mt-1">Clones of the same repo or its forks borrow objects from here</p>
+        </div>
+
+        {/* Branch naming */}
+        <div className="mb-4">
+          <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
+            Branch Prefix
+          </label>
+          <input
+            type="text"
+            value={settings.branchNaming.prefix}
+            onChange={(e) => setSettings((s) => ({ ...s, branchNaming: { ...s.branchNaming, prefix: e.target.value } }))}
+            placeholder="aim/"
+            className="w-full bg-slate-800 border border-slate-700 rounded-lg px-3 py-2 text-sm text-slate-200 font-mono placeholder-slate-600 focus:outline-none focus:border-indigo-500"
+          />
+          <div className="flex items-center gap-4 mt-2">
+            <label className="flex items-center gap-2 text-xs text-slate-400">
+              <input
+                type="checkbox"
+                checked={settings.branchNaming.includeIssue}
+                onChange={(e) => setSettings((s) => ({ ...s, branchNaming: { ...s.branchNaming, includeIssue: e.target.checked } }))}
+              />
+              Include issue key
+            </label>
+            <label className="flex items-center gap-2 text-xs text-slate-400">
+              <input
+                type="checkbox"
+                checked={settings.branchNaming.useAgent}
+                onChange={(e) => setSettings((s) => ({ ...s, branchNaming: { ...s.branchNaming, useAgent: e.target.checked } }))}
+              />
+              Let Claude name branches
+            </label>
+          </div>
+          <p className="text-[10px] text-slate-600 mt-1">New branches are named from the session's first prompt</p>
+        </div>
+
         {/* Theme */}
         <div className="mb-5">
           <label className="block text-xs text-slate-400 mb-2 uppercase tracking-wide">
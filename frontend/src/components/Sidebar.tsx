@@ -8,11 +8,13 @@ import DashboardSidebar from './dashboard/DashboardSidebar'
 interface SidebarProps {
   onAddRepository: () => void
   onSettings: () => void
-  onNewSession: (workspaceId: string) => void
+  onNewSession: (workspaceId: string, mode?: 'shared' | 'per-repo') => void
   onArchivePanel: () => void
+  onRepoConfig: (workspaceId: string) => void
 }
 
 const statusColors: Record<SessionStatus, string> = {
+  starting: 'bg-sky-400 animate-pulse',
   idle:     'bg-emerald-400',
   thinking: 'bg-yellow-400 animate-pulse',
   waiting:  'bg-orange-400 animate-pulse',
@@ -55,12 +57,13 @@ function SessionRow({ session, isActive, onClick }: {
   )
 }
 
-function WorkspaceRow({ workspace, isActiveWs, activeSessionId, onSessionClick, onNewSession, onToggle }: {
+function WorkspaceRow({ workspace, isActiveWs, activeSessionId, onSessionClick, onNewSession, onRepoConfig, onToggle }: {
   workspace: WorkspaceState
   isActiveWs: boolean
   activeSessionId: string | null
   onSessionClick: (sessionId: string, workspaceId: string) => void
-  onNewSession: (workspaceId: string) => void
+  onNewSession: (workspaceId: string, mode?: 'shared' | 'per-repo') => void
+  onRepoConfig: (workspaceId: string) => void
   onToggle: (id: string) => void
 }) {
   const anyThinking = workspace.sessions
@@ -84,6 +87,14 @@ function WorkspaceRow({ workspace, isActiveWs, activeSessionId, onSessionClick,
           ▶
         </span>
         <span className="flex-1 text-sm font-medium truncate">{workspace.name}</span>
+        {workspace.repos && workspace.repos.length > 0 && (
+          <span className="text-[10px] text-slate-500" title={[workspace.path, ...workspace.repos].join('\n')}>
+            {workspace.repos.length + 1} repos
+          </span>
+        )}
+        {workspace.configNeedsTrust && (
+          <span className="w-1.5 h-1.5 rounded-full bg-amber-400" title="Repository config needs review" />
+        )}
         {anyThinking && (
           <span className="w-1.5 h-1.5 rounded-full bg-yellow-400 animate-pulse" />
         )}
@@ -111,6 +122,24 @@ function WorkspaceRow({ workspace, isActiveWs, activeSessionId, onSessionClick,
             <span>+</span>
             <span>New session</span>
           </button>
+          {workspace.repos && workspace.repos.length > 0 && (
+            <button
+              onClick={() => onNewSession(workspace.id, 'per-repo')}
+              className="w-full flex items-center gap-2 pl-7 pr-3 py-1.5 text-xs text-slate-600 hover:text-indigo-400 hover:bg-slate-800 rounded-md transition-colors"
+            >
+              <span>+</span>
+              <span>Session per repo</span>
+            </button>
+          )}
+          <button
+            onClick={() => onRepoConfig(workspace.id)}
+            className={`w-full flex items-center gap-2 pl-7 pr-3 py-1.5 text-xs rounded-md transition-colors hover:bg-slate-800 ${
+              workspace.configNeedsTrust ? 'text-amber-400 hover:text-amber-300' : 'text-slate-600 hover:text-indigo-400'
+            }`}
+          >
+            <span>⚙</span>
+            <span>{workspace.configNeedsTrust ? 'Review repo config' : 'Repo config'}</span>
+          </button>
         </div>
       )}
     </div>
@@ -119,7 +148,7 @@ function WorkspaceRow({ workspace, isActiveWs, activeSessionId, onSessionClick,
 
 export { StatusDot }
 
-export default function Sidebar({ onAddRepository, onSettings, onNewSession, onArchivePanel }: SidebarProps) {
+export default function Sidebar({ onAddRepository, onSettings, onNewSession, onArchivePanel, onRepoConfig }: SidebarProps) {
   const { workspaces, activeSessionId, activeWorkspaceId, setActiveSession, toggleWorkspace } = useAimStore()
   const { activeView } = useNavigationStore()
 
@@ -143,6 +172,7 @@ export default function Sidebar({ onAddRepository, onSettings, onNewSession, onA
               activeSessionId={activeSessionId}
               onSessionClick={handleSessionClick}
               onNewSession={onNewSession}
+              onRepoConfig={onRepoConfig}
               onToggle={toggleWorkspace}
             />
           ))}
@@ -24,17 +24,12 @@ declare const window: Window & {
 
 interface TerminalProps {
   sessionId: string
-  /** Called with the first line the user types and submits (Enter). Used to rename the branch. */
-  onFirstMessage?: (text: string) => void
 }
 
-export default function Terminal({ sessionId, onFirstMessage }: TerminalProps) {
+export default function Terminal({ sessionId }: TerminalProps) {
   const containerRef = useRef<HTMLDivElement>(null)
   const termRef = useRef<XTerm | null>(null)
   const fitRef = useRef<FitAddon | null>(null)
-  // Track first-message for branch auto-rename
-  const firstMsgBuffer = useRef('')
-  const firstMsgFired = useRef(false)
 
   useEffect(() => {
     if (!containerRef.current) return
@@ -131,24 +126,8 @@ export default function Terminal({ sessionId, onFirstMessage }: TerminalProps) {
 
     window.runtime?.EventsOn(`session:data:${sessionId}`, onData)
 
-    // Forward keystrokes to backend; buffer first line for branch auto-rename
+    // Forward keystrokes to backend
     const disposeInput = term.onData((data) => {
-      if (onFirstMessage && !firstMsgFired.current) {
-        if (data === '\r' || data === '\n') {
-          // Enter pressed — fire if there's content
-          const text = firstMsgBuffer.current.trim()
-          if (text) {
-            firstMsgFired.current = true
-            onFirstMessage(text)
-          }
-        } else if (data === '\x7f') {
-          // Backspace
-          firstMsgBuffer.current = firstMsgBuffer.current.slice(0, -1)
-        } else if (data.length === 1 && data.charCodeAt(0) >= 32) {
-          // Printable character
-          firstMsgBuffer.current += data
-        }
-      }
       import('../../wailsjs/go/session/Manager')
         .then(({ WriteToSession }) => WriteToSession(sessionId, data))
         .catch(() => {})
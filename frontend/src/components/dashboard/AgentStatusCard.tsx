@@ -1,6 +1,7 @@
 import type { SessionMetrics } from '../../stores/dashboard'
 
 const statusColors: Record<string, string> = {
+  starting: 'bg-sky-400 animate-pulse',
   idle: 'bg-emerald-400',
   thinking: 'bg-yellow-400 animate-pulse',
   waiting: 'bg-orange-400 animate-pulse',
@@ -20,6 +21,12 @@ function formatDuration(seconds: number): string {
   return `${Math.floor(seconds / 3600)}h ${Math.floor((seconds % 3600) / 60)}m`
 }
 
+function formatTokens(n: number): string {
+  if (n < 1000) return `${n}`
+  if (n < 1_000_000) return `${(n / 1000).toFixed(1)}k`
+  return `${(n / 1_000_000).toFixed(1)}M`
+}
+
 interface AgentStatusCardProps {
   metrics: SessionMetrics
   onSwitch: () => void
@@ -63,6 +70,14 @@ export default function AgentStatusCard({ metrics, onSwitch }: AgentStatusCardPr
         <span>Idle: {formatDuration(metrics.idleTime)}</span>
       </div>
 
+      {metrics.tokens && (
+        <div className="flex gap-3 mt-1 text-[10px] text-slate-500">
+          <span>In: {formatTokens(metrics.tokens.inputTokens + metrics.tokens.cacheReadTokens + metrics.tokens.cacheWriteTokens)}</span>
+          <span>Out: {formatTokens(metrics.tokens.outputTokens)}</span>
+          <span>Cost: ${metrics.costUsd.toFixed(2)}</span>
+        </div>
+      )}
+
       {metrics.isStuck && (
         <div className="mt-2 text-[10px] text-orange-400 font-medium">
           Stuck — waiting for input
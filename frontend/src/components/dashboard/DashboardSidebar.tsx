@@ -2,6 +2,7 @@ import { useSessionStore, SessionStatus } from '../../stores/sessions'
 import { useNavigationStore } from '../../stores/navigation'
 
 const statusColors: Record<SessionStatus, string> = {
+  starting: 'bg-sky-400 animate-pulse',
   idle: 'bg-emerald-400',
   thinking: 'bg-yellow-400 animate-pulse',
   waiting: 'bg-orange-400 animate-pulse',
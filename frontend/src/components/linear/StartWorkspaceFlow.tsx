@@ -156,6 +156,8 @@ export default function StartWorkspaceFlow({ issue, onClose }: StartWorkspaceFlo
           useWorktree: true,
           worktreePath: '',
           branch: `aim/linear/${issue.identifier.toLowerCase()}`,
+          issueId: issue.id,
+          issueIdentifier: issue.identifier,
         } as any)
         sessionIds.push(sessionId as string)
       }
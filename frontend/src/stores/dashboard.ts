@@ -18,6 +18,34 @@ export interface SessionMetrics {
   totalTime: number
   lastActivity: string
   isStuck: boolean
+  workspaceId: string
+  issueId?: string
+  issueIdentifier?: string
+  tokens: TokenUsage
+  models: ModelUsage[]
+  costUsd: number
+}
+
+export interface TokenUsage {
+  inputTokens: number
+  outputTokens: number
+  cacheReadTokens: number
+  cacheWriteTokens: number
+}
+
+export interface ModelUsage {
+  model: string
+  tokens: TokenUsage
+  costUsd: number
+  priced: boolean
+}
+
+export interface UsageTotal {
+  id: string
+  label: string
+  sessions: number
+  tokens: TokenUsage
+  costUsd: number
 }
 
 export interface MetricSnapshot {
@@ -32,12 +60,16 @@ export interface DashboardData {
   sessions: SessionMetrics[]
   history: MetricSnapshot[]
   stuckAgents: SessionMetrics[]
+  workspaces: UsageTotal[]
+  issues: UsageTotal[]
 }
 
 interface DashboardStore {
   sessions: SessionMetrics[]
   history: MetricSnapshot[]
   stuckAgents: SessionMetrics[]
+  workspaceUsage: UsageTotal[]
+  issueUsage: UsageTotal[]
   isLoading: boolean
 
   setData: (data: DashboardData) => void
@@ -48,6 +80,8 @@ export const useDashboardStore = create<DashboardStore>((set) => ({
   sessions: [],
   history: [],
   stuckAgents: [],
+  workspaceUsage: [],
+  issueUsage: [],
   isLoading: false,
 
   setData: (data) =>
@@ -55,6 +89,8 @@ export const useDashboardStore = create<DashboardStore>((set) => ({
       sessions: data.sessions ?? [],
       history: data.history ?? [],
       stuckAgents: data.stuckAgents ?? [],
+      workspaceUsage: data.workspaces ?? [],
+      issueUsage: data.issues ?? [],
     }),
 
   fetchDashboard: async () => {
@@ -66,6 +102,8 @@ export const useDashboardStore = create<DashboardStore>((set) => ({
           sessions: (data as any).sessions ?? [],
           history: (data as any).history ?? [],
           stuckAgents: (data as any).stuckAgents ?? [],
+          workspaceUsage: (data as any).workspaces ?? [],
+          issueUsage: (data as any).issues ?? [],
         })
       }
     } catch {}
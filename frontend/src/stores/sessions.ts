@@ -1,7 +1,7 @@
 import { create } from 'zustand'
 
 export type AgentType = 'claude' | 'codex' | 'shell'
-export type SessionStatus = 'idle' | 'thinking' | 'waiting' | 'stopped' | 'errored'
+export type SessionStatus = 'starting' | 'idle' | 'thinking' | 'waiting' | 'stopped' | 'errored'
 
 export interface SessionState {
   id: string
@@ -14,6 +14,22 @@ export interface SessionState {
   status: SessionStatus
   archived: boolean       // true when in archive
   archivedAt?: string     // ISO timestamp set when archived
+  setupLog?: string       // output of a failed worktree setup
+  backup?: WorktreeBackup // uncommitted changes saved when the worktree was removed
+  linkedRepos?: LinkedRepo[] // further repositories worked on in the same session
+}
+
+export interface LinkedRepo {
+  repoPath: string
+  worktreePath: string
+  backup?: WorktreeBackup
+}
+
+export interface WorktreeBackup {
+  ref: string
+  branch: string
+  files: number
+  createdAt: string
 }
 
 export interface WorkspaceState {
@@ -22,6 +38,8 @@ export interface WorkspaceState {
   path: string
   agent: AgentType
   cloned: boolean
+  repos?: string[]        // further repositories in a multi-repo workspace
+  configNeedsTrust?: boolean // the repo's .aim config has setup commands or env awaiting approval
   expanded: boolean       // UI-only: whether sidebar row is expanded
   sessions: SessionState[]
 }
@@ -36,12 +54,14 @@ interface AimStore {
   addWorkspace: (workspace: WorkspaceState) => void
   removeWorkspace: (id: string) => void
   toggleWorkspace: (id: string) => void
+  setConfigTrusted: (id: string) => void
 
   // Session actions
   addSession: (session: SessionState) => void
   removeSession: (id: string) => void
   updateStatus: (id: string, status: SessionStatus) => void
   updateBranch: (id: string, branch: string) => void
+  restoreWorktree: (id: string, worktreePath: string, linkedRepos?: LinkedRepo[]) => void
   archiveSession: (id: string) => void
   unarchiveSession: (id: string) => void
   deleteArchivedSession: (id: string) => void
@@ -78,6 +98,13 @@ export const useAimStore = create<AimStore>((set) => ({
       ),
     })),
 
+  setConfigTrusted: (id) =>
+    set((state) => ({
+      workspaces: state.workspaces.map((w) =>
+        w.id === id ? { ...w, configNeedsTrust: false } : w
+      ),
+    })),
+
   addSession: (session) =>
     set((state) => ({
       workspaces: state.workspaces.map((w) =>
@@ -118,6 +145,16 @@ export const useAimStore = create<AimStore>((set) => ({
       })),
     })),
 
+  restoreWorktree: (id, worktreePath, linkedRepos) =>
+    set((state) => ({
+      workspaces: state.workspaces.map((w) => ({
+        ...w,
+        sessions: w.sessions.map((s) =>
+          s.id === id ? { ...s, worktreePath, backup: undefined, linkedRepos: linkedRepos ?? s.linkedRepos } : s
+        ),
+      })),
+    })),
+
   archiveSession: (id) =>
     set((state) => ({
       workspaces: state.workspaces.map((w) => ({
@@ -5,6 +5,10 @@ import {context} from '../models';
 
 export function GetDashboardData():Promise<agent.DashboardData>;
 
+export function Reload():Promise<void>;
+
 export function SetContext(arg1:context.Context):Promise<void>;
 
 export function Shutdown():Promise<void>;
+
+export function Subscribe(arg1:any):Promise<void>;
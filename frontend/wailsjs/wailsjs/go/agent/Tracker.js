@@ -6,6 +6,10 @@ export function GetDashboardData() {
   return window['go']['agent']['Tracker']['GetDashboardData']();
 }
 
+export function Reload() {
+  return window['go']['agent']['Tracker']['Reload']();
+}
+
 export function SetContext(arg1) {
   return window['go']['agent']['Tracker']['SetContext'](arg1);
 }
@@ -13,3 +17,7 @@ export function SetContext(arg1) {
 export function Shutdown() {
   return window['go']['agent']['Tracker']['Shutdown']();
 }
+
+export function Subscribe(arg1) {
+  return window['go']['agent']['Tracker']['Subscribe'](arg1);
+}
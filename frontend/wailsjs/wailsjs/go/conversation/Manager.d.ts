@@ -0,0 +1,10 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {conversation} from '../models';
+import {context} from '../models';
+
+export function GetSessionTranscript(arg1:string):Promise<conversation.Transcript>;
+
+export function Reload():Promise<void>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
@@ -0,0 +1,15 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetSessionTranscript(arg1) {
+  return window['go']['conversation']['Manager']['GetSessionTranscript'](arg1);
+}
+
+export function Reload() {
+  return window['go']['conversation']['Manager']['Reload']();
+}
+
+export function SetContext(arg1) {
+  return window['go']['conversation']['Manager']['SetContext'](arg1);
+}
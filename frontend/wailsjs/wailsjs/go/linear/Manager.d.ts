@@ -3,6 +3,8 @@
 import {linear} from '../models';
 import {context} from '../models';
 
+export function AttachLink(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function CancelOAuth():Promise<void>;
 
 export function DetectReposPrompt(arg1:string,arg2:string,arg3:Array<string>):Promise<string>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AttachLink(arg1, arg2, arg3) {
+  return window['go']['linear']['Manager']['AttachLink'](arg1, arg2, arg3);
+}
+
 export function CancelOAuth() {
   return window['go']['linear']['Manager']['CancelOAuth']();
 }
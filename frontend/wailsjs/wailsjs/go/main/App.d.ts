@@ -1,4 +1,20 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {storage} from '../models';
+import {config} from '../models';
+
+export function CreateProfile(arg1:string):Promise<void>;
+
+export function DeleteProfile(arg1:string):Promise<void>;
+
+export function DismissRecoveries():Promise<void>;
+
+export function GetActiveProfile():Promise<string>;
+
+export function GetRecoveries():Promise<Array<storage.Recovery>>;
+
+export function ListProfiles():Promise<Array<config.Profile>>;
 
 export function OpenDirectoryDialog(arg1:string):Promise<string>;
+
+export function SwitchProfile(arg1:string):Promise<void>;
@@ -1,5 +1,43 @@
 export namespace agent {
 	
+	export class UsageTotal {
+	    id: string;
+	    label: string;
+	    sessions: number;
+	    tokens: transcript.Usage;
+	    costUsd: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageTotal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.sessions = source["sessions"];
+	        this.tokens = this.convertValues(source["tokens"], transcript.Usage);
+	        this.costUsd = source["costUsd"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class MetricSnapshot {
 	    timestamp: string;
 	    activeCount: number;
@@ -20,6 +58,42 @@ export namespace agent {
 	        this.idleCount = source["idleCount"];
 	    }
 	}
+	export class ModelUsage {
+	    model: string;
+	    tokens: transcript.Usage;
+	    costUsd: number;
+	    priced: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.tokens = this.convertValues(source["tokens"], transcript.Usage);
+	        this.costUsd = source["costUsd"];
+	        this.priced = source["priced"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SessionMetrics {
 	    sessionId: string;
 	    sessionName: string;
@@ -31,6 +105,12 @@ export namespace agent {
 	    totalTime: number;
 	    lastActivity: string;
 	    isStuck: boolean;
+	    workspaceId: string;
+	    issueId?: string;
+	    issueIdentifier?: string;
+	    tokens: transcript.Usage;
+	    models: ModelUsage[];
+	    costUsd: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new SessionMetrics(source);
@@ -48,12 +128,38 @@ export namespace agent {
 	        this.totalTime = source["totalTime"];
 	        this.lastActivity = source["lastActivity"];
 	        this.isStuck = source["isStuck"];
+	        this.workspaceId = source["workspaceId"];
+	        this.issueId = source["issueId"];
+	        this.issueIdentifier = source["issueIdentifier"];
+	        this.tokens = this.convertValues(source["tokens"], transcript.Usage);
+	        this.models = this.convertValues(source["models"], ModelUsage);
+	        this.costUsd = source["costUsd"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class DashboardData {
 	    sessions: SessionMetrics[];
 	    history: MetricSnapshot[];
 	    stuckAgents: SessionMetrics[];
+	    workspaces: UsageTotal[];
+	    issues: UsageTotal[];
 	
 	    static createFrom(source: any = {}) {
 	        return new DashboardData(source);
@@ -64,6 +170,75 @@ export namespace agent {
 	        this.sessions = this.convertValues(source["sessions"], SessionMetrics);
 	        this.history = this.convertValues(source["history"], MetricSnapshot);
 	        this.stuckAgents = this.convertValues(source["stuckAgents"], SessionMetrics);
+	        this.workspaces = this.convertValues(source["workspaces"], UsageTotal);
+	        this.issues = this.convertValues(source["issues"], UsageTotal);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+
+}
+
+export namespace config {
+	
+	export class Profile {
+	    name: string;
+	    dir: string;
+	    active: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Profile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.dir = source["dir"];
+	        this.active = source["active"];
+	    }
+	}
+
+}
+
+export namespace conversation {
+	
+	export class Transcript {
+	    sessionId: string;
+	    agent: string;
+	    files: string[];
+	    turns: transcript.Turn[];
+	    fileEdits: transcript.FileEdit[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Transcript(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.agent = source["agent"];
+	        this.files = source["files"];
+	        this.turns = this.convertValues(source["turns"], transcript.Turn);
+	        this.fileEdits = this.convertValues(source["fileEdits"], transcript.FileEdit);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -84,7 +259,27 @@ export namespace agent {
 		    return a;
 		}
 	}
+
+}
+
+export namespace forge {
 	
+	export class PullRequest {
+	    url: string;
+	    number?: number;
+	    provider: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.number = source["number"];
+	        this.provider = source["provider"];
+	    }
+	}
 
 }
 
@@ -285,65 +480,38 @@ export namespace linear {
 
 }
 
-export namespace session {
+export namespace notify {
 	
-	export class SessionConfig {
-	    name: string;
-	    agent: string;
-	    directory: string;
-	    useWorktree: boolean;
-	    worktreePath: string;
-	    branch: string;
-	    workspaceId: string;
-	    repoPath: string;
+	export class QuietHours {
+	    start: string;
+	    end: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new SessionConfig(source);
+	        return new QuietHours(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.agent = source["agent"];
-	        this.directory = source["directory"];
-	        this.useWorktree = source["useWorktree"];
-	        this.worktreePath = source["worktreePath"];
-	        this.branch = source["branch"];
-	        this.workspaceId = source["workspaceId"];
-	        this.repoPath = source["repoPath"];
+	        this.start = source["start"];
+	        this.end = source["end"];
 	    }
 	}
-	export class SessionState {
-	    id: string;
+	export class Rule {
 	    workspaceId: string;
-	    name: string;
-	    agent: string;
-	    directory: string;
-	    worktreePath: string;
-	    branch: string;
-	    status: string;
-	    repoPath: string;
-	    archived?: boolean;
-	    // Go type: time
-	    archivedAt?: any;
+	    triggers: string[];
+	    quietHours?: QuietHours;
+	    debounceSeconds: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new SessionState(source);
+	        return new Rule(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
 	        this.workspaceId = source["workspaceId"];
-	        this.name = source["name"];
-	        this.agent = source["agent"];
-	        this.directory = source["directory"];
-	        this.worktreePath = source["worktreePath"];
-	        this.branch = source["branch"];
-	        this.status = source["status"];
-	        this.repoPath = source["repoPath"];
-	        this.archived = source["archived"];
-	        this.archivedAt = this.convertValues(source["archivedAt"], null);
+	        this.triggers = source["triggers"];
+	        this.quietHours = this.convertValues(source["quietHours"], QuietHours);
+	        this.debounceSeconds = source["debounceSeconds"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -364,88 +532,28 @@ export namespace session {
 		    return a;
 		}
 	}
-
-}
-
-export namespace settings {
-	
-	export class Settings {
-	    defaultAgent: string;
-	    defaultWorktree: boolean;
-	    theme: string;
-	    shellPath: string;
-	    linearApiKey: string;
-	    linearTeamId: string;
-	    defaultRepoDir: string;
-	    linearOAuthToken: string;
-	    linearClientId: string;
-	    reposBaseDir: string;
-	    archiveWorktreeCleanupDays: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new Settings(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.defaultAgent = source["defaultAgent"];
-	        this.defaultWorktree = source["defaultWorktree"];
-	        this.theme = source["theme"];
-	        this.shellPath = source["shellPath"];
-	        this.linearApiKey = source["linearApiKey"];
-	        this.linearTeamId = source["linearTeamId"];
-	        this.defaultRepoDir = source["defaultRepoDir"];
-	        this.linearOAuthToken = source["linearOAuthToken"];
-	        this.linearClientId = source["linearClientId"];
-	        this.reposBaseDir = source["reposBaseDir"];
-	        this.archiveWorktreeCleanupDays = source["archiveWorktreeCleanupDays"];
-	    }
-	}
-
-}
-
-export namespace workspace {
-	
-	export class AddWorkspaceConfig {
-	    path: string;
-	    repoUrl: string;
-	    reposBaseDir: string;
-	    name: string;
-	    agent: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new AddWorkspaceConfig(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.path = source["path"];
-	        this.repoUrl = source["repoUrl"];
-	        this.reposBaseDir = source["reposBaseDir"];
-	        this.name = source["name"];
-	        this.agent = source["agent"];
-	    }
-	}
-	export class WorkspaceWithSessions {
+	export class Channel {
 	    id: string;
 	    name: string;
-	    path: string;
-	    agent: string;
-	    cloned: boolean;
-	    sessions: session.SessionState[];
+	    type: string;
+	    enabled: boolean;
+	    command?: string;
+	    webhookUrl?: string;
+	    rules: Rule[];
 	
 	    static createFrom(source: any = {}) {
-	        return new WorkspaceWithSessions(source);
+	        return new Channel(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.agent = source["agent"];
-	        this.cloned = source["cloned"];
-	        this.sessions = this.convertValues(source["sessions"], session.SessionState);
+	        this.type = source["type"];
+	        this.enabled = source["enabled"];
+	        this.command = source["command"];
+	        this.webhookUrl = source["webhookUrl"];
+	        this.rules = this.convertValues(source["rules"], Rule);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -466,15 +574,1411 @@ export namespace workspace {
 		    return a;
 		}
 	}
-
+	export class Config {
+	    channels: Channel[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channels = this.convertValues(source["channels"], Channel);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace repostatus {
+	
+	export class SyncStatus {
+	    sessionId: string;
+	    branch: string;
+	    base: string;
+	    ahead: number;
+	    behind: number;
+	    conflicts: worktree.Conflict[];
+	    // Go type: time
+	    checkedAt: any;
+	    // Go type: time
+	    fetchedAt: any;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.branch = source["branch"];
+	        this.base = source["base"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.conflicts = this.convertValues(source["conflicts"], worktree.Conflict);
+	        this.checkedAt = this.convertValues(source["checkedAt"], null);
+	        this.fetchedAt = this.convertValues(source["fetchedAt"], null);
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sandbox {
+	
+	export class Policy {
+	    enabled: boolean;
+	    writablePaths: string[];
+	    cacheDirs: string[];
+	    network: string;
+	    allowedHosts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Policy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.writablePaths = source["writablePaths"];
+	        this.cacheDirs = source["cacheDirs"];
+	        this.network = source["network"];
+	        this.allowedHosts = source["allowedHosts"];
+	    }
+	}
+
+}
+
+export namespace session {
+	
+	export class LinkedRepo {
+	    repoPath: string;
+	    worktreePath: string;
+	    backup?: worktree.Backup;
+	
+	    static createFrom(source: any = {}) {
+	        return new LinkedRepo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.repoPath = source["repoPath"];
+	        this.worktreePath = source["worktreePath"];
+	        this.backup = this.convertValues(source["backup"], worktree.Backup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionConfig {
+	    name: string;
+	    agent: string;
+	    directory: string;
+	    useWorktree: boolean;
+	    worktreePath: string;
+	    branch: string;
+	    workspaceId: string;
+	    repoPath: string;
+	    issueId?: string;
+	    issueIdentifier?: string;
+	    initialPrompt?: string;
+	    linkedRepos?: LinkedRepo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.agent = source["agent"];
+	        this.directory = source["directory"];
+	        this.useWorktree = source["useWorktree"];
+	        this.worktreePath = source["worktreePath"];
+	        this.branch = source["branch"];
+	        this.workspaceId = source["workspaceId"];
+	        this.repoPath = source["repoPath"];
+	        this.issueId = source["issueId"];
+	        this.issueIdentifier = source["issueIdentifier"];
+	        this.initialPrompt = source["initialPrompt"];
+	        this.linkedRepos = this.convertValues(source["linkedRepos"], LinkedRepo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionFilter {
+	    tags: string[];
+	    agents: string[];
+	    statuses: string[];
+	    workspaceIds: string[];
+	    branch: string;
+	    archived?: boolean;
+	    // Go type: time
+	    createdAfter?: any;
+	    // Go type: time
+	    createdBefore?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tags = source["tags"];
+	        this.agents = source["agents"];
+	        this.statuses = source["statuses"];
+	        this.workspaceIds = source["workspaceIds"];
+	        this.branch = source["branch"];
+	        this.archived = source["archived"];
+	        this.createdAfter = this.convertValues(source["createdAfter"], null);
+	        this.createdBefore = this.convertValues(source["createdBefore"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionState {
+	    id: string;
+	    workspaceId: string;
+	    name: string;
+	    agent: string;
+	    directory: string;
+	    worktreePath: string;
+	    branch: string;
+	    status: string;
+	    repoPath: string;
+	    archived?: boolean;
+	    // Go type: time
+	    archivedAt?: any;
+	    // Go type: time
+	    createdAt?: any;
+	    tags?: string[];
+	    notes?: string;
+	    agentSessionId?: string;
+	    currentTool?: string;
+	    issueId?: string;
+	    issueIdentifier?: string;
+	    pullRequestUrl?: string;
+	    setupLog?: string;
+	    backup?: worktree.Backup;
+	    linkedRepos?: LinkedRepo[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.workspaceId = source["workspaceId"];
+	        this.name = source["name"];
+	        this.agent = source["agent"];
+	        this.directory = source["directory"];
+	        this.worktreePath = source["worktreePath"];
+	        this.branch = source["branch"];
+	        this.status = source["status"];
+	        this.repoPath = source["repoPath"];
+	        this.archived = source["archived"];
+	        this.archivedAt = this.convertValues(source["archivedAt"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.tags = source["tags"];
+	        this.notes = source["notes"];
+	        this.agentSessionId = source["agentSessionId"];
+	        this.currentTool = source["currentTool"];
+	        this.issueId = source["issueId"];
+	        this.issueIdentifier = source["issueIdentifier"];
+	        this.pullRequestUrl = source["pullRequestUrl"];
+	        this.setupLog = source["setupLog"];
+	        this.backup = this.convertValues(source["backup"], worktree.Backup);
+	        this.linkedRepos = this.convertValues(source["linkedRepos"], LinkedRepo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionView {
+	    id: string;
+	    name: string;
+	    filter: SessionFilter;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.filter = this.convertValues(source["filter"], SessionFilter);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace settings {
+	
+	export class BranchNaming {
+	    prefix: string;
+	    includeIssue: boolean;
+	    maxWords: number;
+	    maxLength: number;
+	    useAgent: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BranchNaming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.prefix = source["prefix"];
+	        this.includeIssue = source["includeIssue"];
+	        this.maxWords = source["maxWords"];
+	        this.maxLength = source["maxLength"];
+	        this.useAgent = source["useAgent"];
+	    }
+	}
+	export class ModelPrice {
+	    input: number;
+	    output: number;
+	    cacheRead: number;
+	    cacheWrite: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelPrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input = source["input"];
+	        this.output = source["output"];
+	        this.cacheRead = source["cacheRead"];
+	        this.cacheWrite = source["cacheWrite"];
+	    }
+	}
+	export class Settings {
+	    defaultAgent: string;
+	    defaultWorktree: boolean;
+	    theme: string;
+	    shellPath: string;
+	    linearApiKey: string;
+	    linearTeamId: string;
+	    defaultRepoDir: string;
+	    linearOAuthToken: string;
+	    linearClientId: string;
+	    reposBaseDir: string;
+	    archiveWorktreeCleanupDays: number;
+	    worktreeRoot: string;
+	    cloneCacheDir: string;
+	    branchNaming: BranchNaming;
+	    modelPrices?: Record<string, ModelPrice>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.defaultAgent = source["defaultAgent"];
+	        this.defaultWorktree = source["defaultWorktree"];
+	        this.theme = source["theme"];
+	        this.shellPath = source["shellPath"];
+	        this.linearApiKey = source["linearApiKey"];
+	        this.linearTeamId = source["linearTeamId"];
+	        this.defaultRepoDir = source["defaultRepoDir"];
+	        this.linearOAuthToken = source["linearOAuthToken"];
+	        this.linearClientId = source["linearClientId"];
+	        this.reposBaseDir = source["reposBaseDir"];
+	        this.archiveWorktreeCleanupDays = source["archiveWorktreeCleanupDays"];
+	        this.worktreeRoot = source["worktreeRoot"];
+	        this.cloneCacheDir = source["cloneCacheDir"];
+	        this.branchNaming = this.convertValues(source["branchNaming"], BranchNaming);
+	        this.modelPrices = this.convertValues(source["modelPrices"], ModelPrice, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace storage {
+	
+	export class Recovery {
+	    file: string;
+	    backup: string;
+	    corruptCopy: string;
+	    error: string;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Recovery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.backup = source["backup"];
+	        this.corruptCopy = source["corruptCopy"];
+	        this.error = source["error"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace transcript {
+	
+	export class FileEdit {
+	    path: string;
+	    kind: string;
+	    tool: string;
+	    toolCallId: string;
+	    turn: number;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.tool = source["tool"];
+	        this.toolCallId = source["toolCallId"];
+	        this.turn = source["turn"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Message {
+	    text: string;
+	    thinking?: boolean;
+	    model?: string;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.thinking = source["thinking"];
+	        this.model = source["model"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolCall {
+	    id: string;
+	    name: string;
+	    input: number[];
+	    result?: string;
+	    isError?: boolean;
+	    done: boolean;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.input = source["input"];
+	        this.result = source["result"];
+	        this.isError = source["isError"];
+	        this.done = source["done"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Turn {
+	    index: number;
+	    prompt: string;
+	    // Go type: time
+	    time: any;
+	    messages: Message[];
+	    toolCalls: ToolCall[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Turn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.prompt = source["prompt"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.messages = this.convertValues(source["messages"], Message);
+	        this.toolCalls = this.convertValues(source["toolCalls"], ToolCall);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Usage {
+	    inputTokens: number;
+	    outputTokens: number;
+	    cacheReadTokens: number;
+	    cacheWriteTokens: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Usage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputTokens = source["inputTokens"];
+	        this.outputTokens = source["outputTokens"];
+	        this.cacheReadTokens = source["cacheReadTokens"];
+	        this.cacheWriteTokens = source["cacheWriteTokens"];
+	    }
+	}
+
+}
+
+export namespace workspace {
+	
+	export class AddWorkspaceConfig {
+	    path: string;
+	    repoUrl: string;
+	    reposBaseDir: string;
+	    name: string;
+	    agent: string;
+	    clone: worktree.CloneOptions;
+	    repos?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AddWorkspaceConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.repoUrl = source["repoUrl"];
+	        this.reposBaseDir = source["reposBaseDir"];
+	        this.name = source["name"];
+	        this.agent = source["agent"];
+	        this.clone = this.convertValues(source["clone"], worktree.CloneOptions);
+	        this.repos = source["repos"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CloneJob {
+	    id: string;
+	    repoUrl: string;
+	    path: string;
+	    status: string;
+	    progress: worktree.CloneProgress;
+	    workspaceId?: string;
+	    error?: string;
+	    // Go type: time
+	    startedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.repoUrl = source["repoUrl"];
+	        this.path = source["path"];
+	        this.status = source["status"];
+	        this.progress = this.convertValues(source["progress"], worktree.CloneProgress);
+	        this.workspaceId = source["workspaceId"];
+	        this.error = source["error"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepoSetup {
+	    path: string;
+	    file?: string;
+	    hash?: string;
+	    error?: string;
+	    trusted: boolean;
+	    setup?: worktree.Bootstrap;
+	    pending?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoSetup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.file = source["file"];
+	        this.hash = source["hash"];
+	        this.error = source["error"];
+	        this.trusted = source["trusted"];
+	        this.setup = this.convertValues(source["setup"], worktree.Bootstrap);
+	        this.pending = source["pending"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RepoConfig {
+	    agent?: string;
+	    setup?: worktree.Bootstrap;
+	    env?: Record<string, string>;
+	    branchPrefix?: string;
+	    testCommand?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RepoConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agent = source["agent"];
+	        this.setup = this.convertValues(source["setup"], worktree.Bootstrap);
+	        this.env = source["env"];
+	        this.branchPrefix = source["branchPrefix"];
+	        this.testCommand = source["testCommand"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EffectiveConfig {
+	    agent?: string;
+	    setup?: worktree.Bootstrap;
+	    env?: Record<string, string>;
+	    branchPrefix?: string;
+	    testCommand?: string;
+	    sources: Record<string, string>;
+	    file?: string;
+	    hash?: string;
+	    error?: string;
+	    trusted: boolean;
+	    pending?: RepoConfig;
+	    repos?: RepoSetup[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EffectiveConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.agent = source["agent"];
+	        this.setup = this.convertValues(source["setup"], worktree.Bootstrap);
+	        this.env = source["env"];
+	        this.branchPrefix = source["branchPrefix"];
+	        this.testCommand = source["testCommand"];
+	        this.sources = source["sources"];
+	        this.file = source["file"];
+	        this.hash = source["hash"];
+	        this.error = source["error"];
+	        this.trusted = source["trusted"];
+	        this.pending = this.convertValues(source["pending"], RepoConfig);
+	        this.repos = this.convertValues(source["repos"], RepoSetup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GCItem {
+	    path: string;
+	    repoPath: string;
+	    branch?: string;
+	    reason: string;
+	    detail?: string;
+	    sessionId?: string;
+	    bytes: number;
+	    dirty: boolean;
+	    merged: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GCItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.repoPath = source["repoPath"];
+	        this.branch = source["branch"];
+	        this.reason = source["reason"];
+	        this.detail = source["detail"];
+	        this.sessionId = source["sessionId"];
+	        this.bytes = source["bytes"];
+	        this.dirty = source["dirty"];
+	        this.merged = source["merged"];
+	    }
+	}
+	export class GCReport {
+	    items: GCItem[];
+	    totalBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GCReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], GCItem);
+	        this.totalBytes = source["totalBytes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GCResult {
+	    path: string;
+	    removed: boolean;
+	    freed: number;
+	    backup?: worktree.Backup;
+	    trash?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GCResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.removed = source["removed"];
+	        this.freed = source["freed"];
+	        this.backup = this.convertValues(source["backup"], worktree.Backup);
+	        this.trash = source["trash"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MergeResult {
+	    merged: boolean;
+	    branch: string;
+	    base: string;
+	    strategy: string;
+	    commit?: string;
+	    conflicts: worktree.Conflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.merged = source["merged"];
+	        this.branch = source["branch"];
+	        this.base = source["base"];
+	        this.strategy = source["strategy"];
+	        this.commit = source["commit"];
+	        this.conflicts = this.convertValues(source["conflicts"], worktree.Conflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PullRequestOptions {
+	    remote: string;
+	    base: string;
+	    title: string;
+	    body: string;
+	    draft: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequestOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remote = source["remote"];
+	        this.base = source["base"];
+	        this.title = source["title"];
+	        this.body = source["body"];
+	        this.draft = source["draft"];
+	    }
+	}
+	
+	
+	export class TaskConfig {
+	    workspaceId: string;
+	    mode: string;
+	    branch?: string;
+	    initialPrompt?: string;
+	    issueId?: string;
+	    issueIdentifier?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TaskConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.workspaceId = source["workspaceId"];
+	        this.mode = source["mode"];
+	        this.branch = source["branch"];
+	        this.initialPrompt = source["initialPrompt"];
+	        this.issueId = source["issueId"];
+	        this.issueIdentifier = source["issueIdentifier"];
+	    }
+	}
+	export class WorkspaceWithSessions {
+	    id: string;
+	    name: string;
+	    path: string;
+	    agent: string;
+	    cloned: boolean;
+	    sandbox?: sandbox.Policy;
+	    worktreeRoot?: string;
+	    bootstrap?: worktree.Bootstrap;
+	    repos?: string[];
+	    overrides?: RepoConfig;
+	    trustedConfig?: string;
+	    trustedRepoConfigs?: Record<string, string>;
+	    sessions: session.SessionState[];
+	    configNeedsTrust: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkspaceWithSessions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.agent = source["agent"];
+	        this.cloned = source["cloned"];
+	        this.sandbox = this.convertValues(source["sandbox"], sandbox.Policy);
+	        this.worktreeRoot = source["worktreeRoot"];
+	        this.bootstrap = this.convertValues(source["bootstrap"], worktree.Bootstrap);
+	        this.repos = source["repos"];
+	        this.overrides = this.convertValues(source["overrides"], RepoConfig);
+	        this.trustedConfig = source["trustedConfig"];
+	        this.trustedRepoConfigs = source["trustedRepoConfigs"];
+	        this.sessions = this.convertValues(source["sessions"], session.SessionState);
+	        this.configNeedsTrust = source["configNeedsTrust"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WorktreeMigration {
+	    sessionId: string;
+	    branch: string;
+	    from: string;
+	    to: string;
+	    moved: boolean;
+	    skipped?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeMigration(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sessionId = source["sessionId"];
+	        this.branch = source["branch"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.moved = source["moved"];
+	        this.skipped = source["skipped"];
+	        this.error = source["error"];
+	    }
+	}
+
 }
 
 export namespace worktree {
 	
+	export class Backup {
+	    ref: string;
+	    commit: string;
+	    base: string;
+	    branch: string;
+	    files: number;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Backup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ref = source["ref"];
+	        this.commit = source["commit"];
+	        this.base = source["base"];
+	        this.branch = source["branch"];
+	        this.files = source["files"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Bootstrap {
+	    copy?: string[];
+	    symlink?: string[];
+	    commands?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Bootstrap(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.copy = source["copy"];
+	        this.symlink = source["symlink"];
+	        this.commands = source["commands"];
+	    }
+	}
+	export class CloneOptions {
+	    depth?: number;
+	    filter?: string;
+	    branch?: string;
+	    singleBranch?: boolean;
+	    recurseSubmodules?: boolean;
+	    useCache?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.depth = source["depth"];
+	        this.filter = source["filter"];
+	        this.branch = source["branch"];
+	        this.singleBranch = source["singleBranch"];
+	        this.recurseSubmodules = source["recurseSubmodules"];
+	        this.useCache = source["useCache"];
+	    }
+	}
+	export class CloneProgress {
+	    phase: string;
+	    percent: number;
+	    current: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.phase = source["phase"];
+	        this.percent = source["percent"];
+	        this.current = source["current"];
+	        this.total = source["total"];
+	    }
+	}
+	export class Conflict {
+	    path: string;
+	    kind: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Conflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	    }
+	}
+	export class FileChange {
+	    path: string;
+	    oldPath?: string;
+	    status: string;
+	    additions: number;
+	    deletions: number;
+	    binary: boolean;
+	    patch: string;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.oldPath = source["oldPath"];
+	        this.status = source["status"];
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
+	        this.binary = source["binary"];
+	        this.patch = source["patch"];
+	        this.truncated = source["truncated"];
+	    }
+	}
+	export class GitStatus {
+	    path: string;
+	    branch: string;
+	    head: string;
+	    headSubject: string;
+	    upstream: string;
+	    ahead: number;
+	    behind: number;
+	    staged: number;
+	    unstaged: number;
+	    untracked: number;
+	    conflicted: number;
+	    operation: string;
+	    dirty: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.branch = source["branch"];
+	        this.head = source["head"];
+	        this.headSubject = source["headSubject"];
+	        this.upstream = source["upstream"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.staged = source["staged"];
+	        this.unstaged = source["unstaged"];
+	        this.untracked = source["untracked"];
+	        this.conflicted = source["conflicted"];
+	        this.operation = source["operation"];
+	        this.dirty = source["dirty"];
+	    }
+	}
+	export class MergeCheck {
+	    branch: string;
+	    base: string;
+	    commits: number;
+	    conflicts: Conflict[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.base = source["base"];
+	        this.commits = source["commits"];
+	        this.conflicts = this.convertValues(source["conflicts"], Conflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Remote {
+	    name: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Remote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	    }
+	}
 	export class RepoURL {
 	    host: string;
+	    port?: string;
+	    namespace: string;
 	    org: string;
 	    repo: string;
+	    local?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new RepoURL(source);
@@ -483,14 +1987,61 @@ export namespace worktree {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.namespace = source["namespace"];
 	        this.org = source["org"];
 	        this.repo = source["repo"];
+	        this.local = source["local"];
+	    }
+	}
+	export class WorktreeDiff {
+	    base: string;
+	    baseCommit: string;
+	    branch: string;
+	    files: FileChange[];
+	    untracked: FileChange[];
+	    additions: number;
+	    deletions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorktreeDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.base = source["base"];
+	        this.baseCommit = source["baseCommit"];
+	        this.branch = source["branch"];
+	        this.files = this.convertValues(source["files"], FileChange);
+	        this.untracked = this.convertValues(source["untracked"], FileChange);
+	        this.additions = source["additions"];
+	        this.deletions = source["deletions"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class WorktreeInfo {
 	    path: string;
 	    branch: string;
 	    hash: string;
+	    locked?: boolean;
+	    prunable?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new WorktreeInfo(source);
@@ -501,6 +2052,8 @@ export namespace worktree {
 	        this.path = source["path"];
 	        this.branch = source["branch"];
 	        this.hash = source["hash"];
+	        this.locked = source["locked"];
+	        this.prunable = source["prunable"];
 	    }
 	}
 
@@ -0,0 +1,14 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {notify} from '../models';
+import {context} from '../models';
+
+export function GetNotificationConfig():Promise<notify.Config>;
+
+export function Reload():Promise<void>;
+
+export function SaveNotificationConfig(arg1:notify.Config):Promise<notify.Config>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
+
+export function TestChannel(arg1:string):Promise<void>;
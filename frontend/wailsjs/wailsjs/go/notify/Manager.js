@@ -0,0 +1,23 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetNotificationConfig() {
+  return window['go']['notify']['Manager']['GetNotificationConfig']();
+}
+
+export function Reload() {
+  return window['go']['notify']['Manager']['Reload']();
+}
+
+export function SaveNotificationConfig(arg1) {
+  return window['go']['notify']['Manager']['SaveNotificationConfig'](arg1);
+}
+
+export function SetContext(arg1) {
+  return window['go']['notify']['Manager']['SetContext'](arg1);
+}
+
+export function TestChannel(arg1) {
+  return window['go']['notify']['Manager']['TestChannel'](arg1);
+}
@@ -0,0 +1,23 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+import {worktree} from '../models';
+import {repostatus} from '../models';
+import {context} from '../models';
+
+export function GetGitStatus(arg1:string):Promise<worktree.GitStatus>;
+
+export function GetGitStatuses():Promise<Record<string, worktree.GitStatus>>;
+
+export function GetSyncStatuses():Promise<Array<repostatus.SyncStatus>>;
+
+export function RebaseSessionWorktree(arg1:string):Promise<repostatus.SyncStatus>;
+
+export function RefreshSyncStatus(arg1:string):Promise<repostatus.SyncStatus>;
+
+export function Reload():Promise<void>;
+
+export function SetContext(arg1:context.Context):Promise<void>;
+
+export function Shutdown():Promise<void>;
+
+export function UpdateFromBase(arg1:string):Promise<repostatus.SyncStatus>;
@@ -0,0 +1,39 @@
+// @ts-check
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+export function GetGitStatus(arg1) {
+  return window['go']['repostatus']['Manager']['GetGitStatus'](arg1);
+}
+
+export function GetGitStatuses() {
+  return window['go']['repostatus']['Manager']['GetGitStatuses']();
+}
+
+export function GetSyncStatuses() {
+  return window['go']['repostatus']['Manager']['GetSyncStatuses']();
+}
+
+export function RebaseSessionWorktree(arg1) {
+  return window['go']['repostatus']['Manager']['RebaseSessionWorktree'](arg1);
+}
+
+export function RefreshSyncStatus(arg1) {
+  return window['go']['repostatus']['Manager']['RefreshSyncStatus'](arg1);
+}
+
+export function Reload() {
+  return window['go']['repostatus']['Manager']['Reload']();
+}
+
+export function SetContext(arg1) {
+  return window['go']['repostatus']['Manager']['SetContext'](arg1);
+}
+
+export function Shutdown() {
+  return window['go']['repostatus']['Manager']['Shutdown']();
+}
+
+export function UpdateFromBase(arg1) {
+  return window['go']['repostatus']['Manager']['UpdateFromBase'](arg1);
+}
@@ -1,30 +1,67 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {session} from '../models';
+import {worktree} from '../models';
 import {context} from '../models';
 
+export function AddStatusListener(arg1:session.StatusListener):Promise<void>;
+
 export function ArchiveSession(arg1:string):Promise<void>;
 
+export function AttachLinkedWorktree(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function AttachSessionWorktree(arg1:string,arg2:string):Promise<void>;
+
 export function CloseSession(arg1:string):Promise<void>;
 
 export function CreateSession(arg1:session.SessionConfig):Promise<string>;
 
 export function DeleteArchivedSession(arg1:string):Promise<void>;
 
+export function DeleteSessionView(arg1:string):Promise<void>;
+
+export function ForgetSessionWorktree(arg1:string,arg2:string,arg3:worktree.Backup):Promise<void>;
+
+export function GetSession(arg1:string):Promise<session.SessionState>;
+
 export function GetSessionLog(arg1:string):Promise<string>;
 
+export function ListSessionTags():Promise<Array<string>>;
+
+export function ListSessionViews():Promise<Array<session.SessionView>>;
+
 export function ListSessions():Promise<Array<session.SessionState>>;
 
+export function QuerySessions(arg1:session.SessionFilter):Promise<Array<session.SessionState>>;
+
+export function Reload():Promise<void>;
+
 export function RenameSessionBranch(arg1:string,arg2:string):Promise<void>;
 
 export function ResizeSession(arg1:string,arg2:number,arg3:number):Promise<void>;
 
 export function ResumeSession(arg1:string):Promise<void>;
 
+export function SaveSessionView(arg1:session.SessionView):Promise<string>;
+
 export function SetContext(arg1:context.Context):Promise<void>;
 
+export function SetLaunchOptionsFunc(arg1:session.LaunchOptionsFunc):Promise<void>;
+
+export function SetSessionNotes(arg1:string,arg2:string):Promise<void>;
+
+export function SetSessionPullRequest(arg1:string,arg2:string):Promise<void>;
+
+export function SetSessionTags(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetSessionWorktreePath(arg1:string,arg2:string):Promise<void>;
+
 export function Shutdown():Promise<void>;
 
+export function SuggestBranchName(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
+
 export function UnarchiveSession(arg1:string):Promise<void>;
 
+export function UntitledBranchName(arg1:string,arg2:string):Promise<string>;
+
 export function WriteToSession(arg1:string,arg2:string):Promise<void>;
@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddStatusListener(arg1) {
+  return window['go']['session']['Manager']['AddStatusListener'](arg1);
+}
+
 export function ArchiveSession(arg1) {
   return window['go']['session']['Manager']['ArchiveSession'](arg1);
 }
 
+export function AttachLinkedWorktree(arg1, arg2, arg3) {
+  return window['go']['session']['Manager']['AttachLinkedWorktree'](arg1, arg2, arg3);
+}
+
+export function AttachSessionWorktree(arg1, arg2) {
+  return window['go']['session']['Manager']['AttachSessionWorktree'](arg1, arg2);
+}
+
 export function CloseSession(arg1) {
   return window['go']['session']['Manager']['CloseSession'](arg1);
 }
@@ -18,14 +30,42 @@ export function DeleteArchivedSession(arg1) {
   return window['go']['session']['Manager']['DeleteArchivedSession'](arg1);
 }
 
+export function DeleteSessionView(arg1) {
+  return window['go']['session']['Manager']['DeleteSessionView'](arg1);
+}
+
+export function ForgetSessionWorktree(arg1, arg2, arg3) {
+  return window['go']['session']['Manager']['ForgetSessionWorktree'](arg1, arg2, arg3);
+}
+
+export function GetSession(arg1) {
+  return window['go']['session']['Manager']['GetSession'](arg1);
+}
+
 export function GetSessionLog(arg1) {
   return window['go']['session']['Manager']['GetSessionLog'](arg1);
 }
 
+export function ListSessionTags() {
+  return window['go']['session']['Manager']['ListSessionTags']();
+}
+
+export function ListSessionViews() {
+  return window['go']['session']['Manager']['ListSessionViews']();
+}
+
 export function ListSessions() {
   return window['go']['session']['Manager']['ListSessions']();
 }
 
+export function QuerySessions(arg1) {
+  return window['go']['session']['Manager']['QuerySessions'](arg1);
+}
+
+export function Reload() {
+  return window['go']['session']['Manager']['Reload']();
+}
+
 export function RenameSessionBranch(arg1, arg2) {
   return window['go']['session']['Manager']['RenameSessionBranch'](arg1, arg2);
 }
@@ -38,18 +78,50 @@ export function ResumeSession(arg1) {
   return window['go']['session']['Manager']['ResumeSession'](arg1);
 }
 
+export function SaveSessionView(arg1) {
+  return window['go']['session']['Manager']['SaveSessionView'](arg1);
+}
+
 export function SetContext(arg1) {
   return window['go']['session']['Manager']['SetContext'](arg1);
 }
 
+export function SetLaunchOptionsFunc(arg1) {
+  return window['go']['session']['Manager']['SetLaunchOptionsFunc'](arg1);
+}
+
+export function SetSessionNotes(arg1, arg2) {
+  return window['go']['session']['Manager']['SetSessionNotes'](arg1, arg2);
+}
+
+export function SetSessionPullRequest(arg1, arg2) {
+  return window['go']['session']['Manager']['SetSessionPullRequest'](arg1, arg2);
+}
+
+export function SetSessionTags(arg1, arg2) {
+  return window['go']['session']['Manager']['SetSessionTags'](arg1, arg2);
+}
+
+export function SetSessionWorktreePath(arg1, arg2) {
+  return window['go']['session']['Manager']['SetSessionWorktreePath'](arg1, arg2);
+}
+
 export function Shutdown() {
   return window['go']['session']['Manager']['Shutdown']();
 }
 
+export function SuggestBranchName(arg1, arg2, arg3, arg4) {
+  return window['go']['session']['Manager']['SuggestBranchName'](arg1, arg2, arg3, arg4);
+}
+
 export function UnarchiveSession(arg1) {
   return window['go']['session']['Manager']['UnarchiveSession'](arg1);
 }
 
+export function UntitledBranchName(arg1, arg2) {
+  return window['go']['session']['Manager']['UntitledBranchName'](arg1, arg2);
+}
+
 export function WriteToSession(arg1, arg2) {
   return window['go']['session']['Manager']['WriteToSession'](arg1, arg2);
 }
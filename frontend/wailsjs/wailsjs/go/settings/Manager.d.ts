@@ -5,6 +5,8 @@ import {context} from '../models';
 
 export function GetSettings():Promise<settings.Settings>;
 
+export function Reload():Promise<void>;
+
 export function SaveSettings(arg1:settings.Settings):Promise<void>;
 
 export function SetContext(arg1:context.Context):Promise<void>;
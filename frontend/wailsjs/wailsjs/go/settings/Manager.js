@@ -6,6 +6,10 @@ export function GetSettings() {
   return window['go']['settings']['Manager']['GetSettings']();
 }
 
+export function Reload() {
+  return window['go']['settings']['Manager']['Reload']();
+}
+
 export function SaveSettings(arg1) {
   return window['go']['settings']['Manager']['SaveSettings'](arg1);
 }
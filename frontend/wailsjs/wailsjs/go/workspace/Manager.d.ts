@@ -1,16 +1,61 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {workspace} from '../models';
+import {forge} from '../models';
 import {context} from '../models';
+import {worktree} from '../models';
+import {sandbox} from '../models';
 
 export function AddWorkspace(arg1:workspace.AddWorkspaceConfig):Promise<string>;
 
+export function CancelClone(arg1:string):Promise<void>;
+
 export function CloneAndAddWorkspace(arg1:workspace.AddWorkspaceConfig):Promise<string>;
 
 export function CloneDestPreview(arg1:string,arg2:string):Promise<string>;
 
+export function CollectWorktrees(arg1:Array<string>):Promise<Array<workspace.GCResult>>;
+
+export function CreatePullRequest(arg1:string,arg2:workspace.PullRequestOptions):Promise<forge.PullRequest>;
+
+export function GetEffectiveConfig(arg1:string):Promise<workspace.EffectiveConfig>;
+
+export function ListCloneJobs():Promise<Array<workspace.CloneJob>>;
+
 export function ListWorkspaces():Promise<Array<workspace.WorkspaceWithSessions>>;
 
+export function MergeSessionBranch(arg1:string,arg2:string):Promise<workspace.MergeResult>;
+
+export function MigrateWorktrees():Promise<Array<workspace.WorktreeMigration>>;
+
+export function Reload():Promise<void>;
+
 export function RemoveWorkspace(arg1:string):Promise<void>;
 
+export function RestoreSessionBackup(arg1:string):Promise<string>;
+
+export function SandboxSupport():Promise<string>;
+
+export function ScanWorktrees():Promise<workspace.GCReport>;
+
 export function SetContext(arg1:context.Context):Promise<void>;
+
+export function SetIssueLinker(arg1:workspace.IssueLinker):Promise<void>;
+
+export function SetWorkspaceBootstrap(arg1:string,arg2:worktree.Bootstrap):Promise<void>;
+
+export function SetWorkspaceOverrides(arg1:string,arg2:workspace.RepoConfig):Promise<void>;
+
+export function SetWorkspaceRepos(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetWorkspaceSandbox(arg1:string,arg2:sandbox.Policy):Promise<void>;
+
+export function SetWorkspaceWorktreeRoot(arg1:string,arg2:string):Promise<void>;
+
+export function StartClone(arg1:workspace.AddWorkspaceConfig):Promise<string>;
+
+export function StartConflictResolution(arg1:string,arg2:string):Promise<string>;
+
+export function StartTask(arg1:workspace.TaskConfig):Promise<Array<string>>;
+
+export function TrustRepoConfig(arg1:string,arg2:string,arg3:Record<string, string>):Promise<void>;
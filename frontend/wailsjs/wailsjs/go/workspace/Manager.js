@@ -6,6 +6,10 @@ export function AddWorkspace(arg1) {
   return window['go']['workspace']['Manager']['AddWorkspace'](arg1);
 }
 
+export function CancelClone(arg1) {
+  return window['go']['workspace']['Manager']['CancelClone'](arg1);
+}
+
 export function CloneAndAddWorkspace(arg1) {
   return window['go']['workspace']['Manager']['CloneAndAddWorkspace'](arg1);
 }
@@ -14,14 +18,94 @@ export function CloneDestPreview(arg1, arg2) {
   return window['go']['workspace']['Manager']['CloneDestPreview'](arg1, arg2);
 }
 
+export function CollectWorktrees(arg1) {
+  return window['go']['workspace']['Manager']['CollectWorktrees'](arg1);
+}
+
+export function CreatePullRequest(arg1, arg2) {
+  return window['go']['workspace']['Manager']['CreatePullRequest'](arg1, arg2);
+}
+
+export function GetEffectiveConfig(arg1) {
+  return window['go']['workspace']['Manager']['GetEffectiveConfig'](arg1);
+}
+
+export function ListCloneJobs() {
+  return window['go']['workspace']['Manager']['ListCloneJobs']();
+}
+
 export function ListWorkspaces() {
   return window['go']['workspace']['Manager']['ListWorkspaces']();
 }
 
+export function MergeSessionBranch(arg1, arg2) {
+  return window['go']['workspace']['Manager']['MergeSessionBranch'](arg1, arg2);
+}
+
+export function MigrateWorktrees() {
+  return window['go']['workspace']['Manager']['MigrateWorktrees']();
+}
+
+export function Reload() {
+  return window['go']['workspace']['Manager']['Reload']();
+}
+
 export function RemoveWorkspace(arg1) {
   return window['go']['workspace']['Manager']['RemoveWorkspace'](arg1);
 }
 
+export function RestoreSessionBackup(arg1) {
+  return window['go']['workspace']['Manager']['RestoreSessionBackup'](arg1);
+}
+
+export function SandboxSupport() {
+  return window['go']['workspace']['Manager']['SandboxSupport']();
+}
+
+export function ScanWorktrees() {
+  return window['go']['workspace']['Manager']['ScanWorktrees']();
+}
+
 export function SetContext(arg1) {
   return window['go']['workspace']['Manager']['SetContext'](arg1);
 }
+
+export function SetIssueLinker(arg1) {
+  return window['go']['workspace']['Manager']['SetIssueLinker'](arg1);
+}
+
+export function SetWorkspaceBootstrap(arg1, arg2) {
+  return window['go']['workspace']['Manager']['SetWorkspaceBootstrap'](arg1, arg2);
+}
+
+export function SetWorkspaceOverrides(arg1, arg2) {
+  return window['go']['workspace']['Manager']['SetWorkspaceOverrides'](arg1, arg2);
+}
+
+export function SetWorkspaceRepos(arg1, arg2) {
+  return window['go']['workspace']['Manager']['SetWorkspaceRepos'](arg1, arg2);
+}
+
+export function SetWorkspaceSandbox(arg1, arg2) {
+  return window['go']['workspace']['Manager']['SetWorkspaceSandbox'](arg1, arg2);
+}
+
+export function SetWorkspaceWorktreeRoot(arg1, arg2) {
+  return window['go']['workspace']['Manager']['SetWorkspaceWorktreeRoot'](arg1, arg2);
+}
+
+export function StartClone(arg1) {
+  return window['go']['workspace']['Manager']['StartClone'](arg1);
+}
+
+export function StartConflictResolution(arg1, arg2) {
+  return window['go']['workspace']['Manager']['StartConflictResolution'](arg1, arg2);
+}
+
+export function StartTask(arg1) {
+  return window['go']['workspace']['Manager']['StartTask'](arg1);
+}
+
+export function TrustRepoConfig(arg1, arg2, arg3) {
+  return window['go']['workspace']['Manager']['TrustRepoConfig'](arg1, arg2, arg3);
+}
@@ -3,18 +3,68 @@
 import {worktree} from '../models';
 import {context} from '../models';
 
+export function AbortMerge(arg1:string):Promise<void>;
+
+export function AbortRebase(arg1:string):Promise<void>;
+
+export function AheadBehind(arg1:string,arg2:string,arg3:string):Promise<number>;
+
+export function BranchExists(arg1:string,arg2:string):Promise<boolean>;
+
+export function CheckMerge(arg1:string,arg2:string,arg3:string):Promise<worktree.MergeCheck>;
+
 export function CloneDestPath(arg1:string,arg2:string):Promise<string>;
 
 export function CloneRepo(arg1:string,arg2:string):Promise<void>;
 
+export function CommitWorktree(arg1:string,arg2:string):Promise<string>;
+
 export function CreateWorktree(arg1:string,arg2:string):Promise<string>;
 
+export function DeleteBackup(arg1:string,arg2:worktree.Backup):Promise<void>;
+
+export function DeleteBranch(arg1:string,arg2:string):Promise<void>;
+
+export function Fetch(arg1:string):Promise<void>;
+
+export function GetGitStatus(arg1:string):Promise<worktree.GitStatus>;
+
+export function GetWorktreeDiff(arg1:string,arg2:string):Promise<worktree.WorktreeDiff>;
+
+export function InProgress(arg1:string):Promise<string>;
+
+export function IsClean(arg1:string):Promise<boolean>;
+
 export function IsGitRepo(arg1:string):Promise<boolean>;
 
+export function ListRemotes(arg1:string):Promise<Array<worktree.Remote>>;
+
 export function ListWorktrees(arg1:string):Promise<Array<worktree.WorktreeInfo>>;
 
+export function ManagedDirs(arg1:string):Promise<Array<string>>;
+
+export function MergeBranch(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<string>;
+
+export function MoveWorktree(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function ParseRepoURL(arg1:string):Promise<worktree.RepoURL>;
 
-export function RemoveWorktree(arg1:string,arg2:string):Promise<void>;
+export function PruneWorktree(arg1:string,arg2:string):Promise<void>;
+
+export function PushBranch(arg1:string,arg2:string):Promise<string>;
+
+export function RemoveWorktree(arg1:string,arg2:string):Promise<worktree.Backup>;
+
+export function RestoreBackup(arg1:string,arg2:worktree.Backup):Promise<void>;
 
 export function SetContext(arg1:context.Context):Promise<void>;
+
+export function SetRootFunc(arg1:worktree.RootFunc):Promise<void>;
+
+export function StartMerge(arg1:string,arg2:string):Promise<void>;
+
+export function StartRebase(arg1:string,arg2:string):Promise<void>;
+
+export function ValidateBranchName(arg1:string):Promise<void>;
+
+export function WorktreePath(arg1:string,arg2:string):Promise<string>;
@@ -2,6 +2,26 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AbortMerge(arg1) {
+  return window['go']['worktree']['Manager']['AbortMerge'](arg1);
+}
+
+export function AbortRebase(arg1) {
+  return window['go']['worktree']['Manager']['AbortRebase'](arg1);
+}
+
+export function AheadBehind(arg1, arg2, arg3) {
+  return window['go']['worktree']['Manager']['AheadBehind'](arg1, arg2, arg3);
+}
+
+export function BranchExists(arg1, arg2) {
+  return window['go']['worktree']['Manager']['BranchExists'](arg1, arg2);
+}
+
+export function CheckMerge(arg1, arg2, arg3) {
+  return window['go']['worktree']['Manager']['CheckMerge'](arg1, arg2, arg3);
+}
+
 export function CloneDestPath(arg1, arg2) {
   return window['go']['worktree']['Manager']['CloneDestPath'](arg1, arg2);
 }
@@ -10,26 +30,106 @@ export function CloneRepo(arg1, arg2) {
   return window['go']['worktree']['Manager']['CloneRepo'](arg1, arg2);
 }
 
+export function CommitWorktree(arg1, arg2) {
+  return window['go']['worktree']['Manager']['CommitWorktree'](arg1, arg2);
+}
+
 export function CreateWorktree(arg1, arg2) {
   return window['go']['worktree']['Manager']['CreateWorktree'](arg1, arg2);
 }
 
+export function DeleteBackup(arg1, arg2) {
+  return window['go']['worktree']['Manager']['DeleteBackup'](arg1, arg2);
+}
+
+export function DeleteBranch(arg1, arg2) {
+  return window['go']['worktree']['Manager']['DeleteBranch'](arg1, arg2);
+}
+
+export function Fetch(arg1) {
+  return window['go']['worktree']['Manager']['Fetch'](arg1);
+}
+
+export function GetGitStatus(arg1) {
+  return window['go']['worktree']['Manager']['GetGitStatus'](arg1);
+}
+
+export function GetWorktreeDiff(arg1, arg2) {
+  return window['go']['worktree']['Manager']['GetWorktreeDiff'](arg1, arg2);
+}
+
+export function InProgress(arg1) {
+  return window['go']['worktree']['Manager']['InProgress'](arg1);
+}
+
+export function IsClean(arg1) {
+  return window['go']['worktree']['Manager']['IsClean'](arg1);
+}
+
 export function IsGitRepo(arg1) {
   return window['go']['worktree']['Manager']['IsGitRepo'](arg1);
 }
 
+export function ListRemotes(arg1) {
+  return window['go']['worktree']['Manager']['ListRemotes'](arg1);
+}
+
 export function ListWorktrees(arg1) {
   return window['go']['worktree']['Manager']['ListWorktrees'](arg1);
 }
 
+export function ManagedDirs(arg1) {
+  return window['go']['worktree']['Manager']['ManagedDirs'](arg1);
+}
+
+export function MergeBranch(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['worktree']['Manager']['MergeBranch'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function MoveWorktree(arg1, arg2, arg3) {
+  return window['go']['worktree']['Manager']['MoveWorktree'](arg1, arg2, arg3);
+}
+
 export function ParseRepoURL(arg1) {
   return window['go']['worktree']['Manager']['ParseRepoURL'](arg1);
 }
 
+export function PruneWorktree(arg1, arg2) {
+  return window['go']['worktree']['Manager']['PruneWorktree'](arg1, arg2);
+}
+
+export function PushBranch(arg1, arg2) {
+  return window['go']['worktree']['Manager']['PushBranch'](arg1, arg2);
+}
+
 export function RemoveWorktree(arg1, arg2) {
   return window['go']['worktree']['Manager']['RemoveWorktree'](arg1, arg2);
 }
 
+export function RestoreBackup(arg1, arg2) {
+  return window['go']['worktree']['Manager']['RestoreBackup'](arg1, arg2);
+}
+
 export function SetContext(arg1) {
   return window['go']['worktree']['Manager']['SetContext'](arg1);
 }
+
+export function SetRootFunc(arg1) {
+  return window['go']['worktree']['Manager']['SetRootFunc'](arg1);
+}
+
+export function StartMerge(arg1, arg2) {
+  return window['go']['worktree']['Manager']['StartMerge'](arg1, arg2);
+}
+
+export function StartRebase(arg1, arg2) {
+  return window['go']['worktree']['Manager']['StartRebase'](arg1, arg2);
+}
+
+export function ValidateBranchName(arg1) {
+  return window['go']['worktree']['Manager']['ValidateBranchName'](arg1);
+}
+
+export function WorktreePath(arg1, arg2) {
+  return window['go']['worktree']['Manager']['WorktreePath'](arg1, arg2);
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"embed"
 	"log"
+	"os"
 
+	"github.com/Benbentwo/aim/backend/hooks"
+	"github.com/Benbentwo/aim/backend/sandbox"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/logger"
 	"github.com/wailsapp/wails/v2/pkg/options"
@@ -15,6 +18,16 @@ import (
 var assets embed.FS
 
 func main() {
+	// Agents launched by aim run "aim hook" from their hook config; handle
+	// that before starting the GUI.
+	if len(os.Args) > 1 && os.Args[1] == hooks.ClientArg {
+		os.Exit(hooks.RunClient(os.Stdin))
+	}
+	// Allowlist sandboxes run the agent under "aim sandbox-relay".
+	if len(os.Args) > 1 && os.Args[1] == sandbox.RelayArg {
+		os.Exit(sandbox.RunRelay(os.Args[2:]))
+	}
+
 	app := NewApp()
 
 	err := wails.Run(&options.App{
@@ -38,6 +51,9 @@ func main() {
 			app.WorkspaceManager,
 			app.LinearManager,
 			app.AgentTracker,
+			app.Notifier,
+			app.Conversations,
+			app.RepoStatus,
 		},
 		Mac: &mac.Options{
 			TitleBar: &mac.TitleBar{